@@ -0,0 +1,287 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/auth"
+	"github.com/mizuchilabs/beacon/internal/config"
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/util"
+)
+
+// AdminMonitor is a monitor as managed through the admin API
+type AdminMonitor struct {
+	ID                  int64     `json:"id"`
+	Name                string    `json:"name"`
+	URL                 string    `json:"url"`
+	CheckInterval       int64     `json:"check_interval"`
+	Group               string    `json:"group,omitempty"`
+	DegradedThresholdMs int64     `json:"degraded_threshold_ms"`
+	DegradedPercentile  int64     `json:"degraded_percentile,omitempty"`
+	DegradedWindow      string    `json:"degraded_window,omitempty"`
+	Public              bool      `json:"public"`
+	Source              string    `json:"source"` // config or api, only api monitors can be changed
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// MonitorRequest creates or replaces a monitor, with the fields and defaults
+// of the config file
+type MonitorRequest struct {
+	Name                string `json:"name"`
+	URL                 string `json:"url"`
+	CheckInterval       int64  `json:"check_interval"`
+	Group               string `json:"group"`
+	DegradedThresholdMs int64  `json:"degraded_threshold_ms"`
+	DegradedPercentile  int64  `json:"degraded_percentile"`
+	DegradedWindow      string `json:"degraded_window"` // e.g. 5m
+	Public              *bool  `json:"public"`          // true by default
+}
+
+// APIToken describes a token without revealing it
+type APIToken struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Scope      string     `json:"scope"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Token      string     `json:"token,omitempty"` // only when created
+}
+
+// AdminGetMonitors returns all monitors with their settings
+func (s *Server) AdminGetMonitors(w http.ResponseWriter, r *http.Request) {
+	monitors, err := s.cfg.Conn.Q.GetMonitors(r.Context())
+	if err != nil {
+		slog.Error("Failed to get monitors", "error", err)
+		http.Error(w, "Failed to get monitors", http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]AdminMonitor, len(monitors))
+	for i, m := range monitors {
+		result[i] = newAdminMonitor(m)
+	}
+	util.RespondJSON(w, http.StatusOK, result)
+}
+
+// AdminCreateMonitor adds a monitor and starts checking it
+func (s *Server) AdminCreateMonitor(w http.ResponseWriter, r *http.Request) {
+	m, ok := decodeMonitorRequest(w, r)
+	if !ok {
+		return
+	}
+
+	monitor, err := s.cfg.CreateMonitor(r.Context(), m)
+	if err != nil {
+		respondMonitorError(w, err)
+		return
+	}
+
+	slog.Info("Monitor created through the admin API",
+		"monitor_id", monitor.ID,
+		"by", principalFrom(r.Context()).Name,
+	)
+	util.RespondJSON(w, http.StatusCreated, newAdminMonitor(monitor))
+}
+
+// AdminUpdateMonitor replaces the settings of a monitor of the admin API
+func (s *Server) AdminUpdateMonitor(w http.ResponseWriter, r *http.Request) {
+	monitorID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+		return
+	}
+	m, ok := decodeMonitorRequest(w, r)
+	if !ok {
+		return
+	}
+
+	monitor, err := s.cfg.UpdateMonitor(r.Context(), monitorID, m)
+	if err != nil {
+		respondMonitorError(w, err)
+		return
+	}
+
+	slog.Info("Monitor updated through the admin API",
+		"monitor_id", monitor.ID,
+		"by", principalFrom(r.Context()).Name,
+	)
+	util.RespondJSON(w, http.StatusOK, newAdminMonitor(monitor))
+}
+
+// AdminDeleteMonitor removes a monitor of the admin API with its checks
+func (s *Server) AdminDeleteMonitor(w http.ResponseWriter, r *http.Request) {
+	monitorID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.cfg.DeleteMonitor(r.Context(), monitorID); err != nil {
+		respondMonitorError(w, err)
+		return
+	}
+
+	slog.Info("Monitor deleted through the admin API",
+		"monitor_id", monitorID,
+		"by", principalFrom(r.Context()).Name,
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AdminGetTokens returns the stored API tokens
+func (s *Server) AdminGetTokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := s.cfg.Conn.Q.GetAPITokens(r.Context())
+	if err != nil {
+		slog.Error("Failed to get API tokens", "error", err)
+		http.Error(w, "Failed to get API tokens", http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]APIToken, len(tokens))
+	for i, t := range tokens {
+		result[i] = newAPIToken(t)
+	}
+	util.RespondJSON(w, http.StatusOK, result)
+}
+
+// AdminCreateToken creates an API token, which is only shown in the response
+func (s *Server) AdminCreateToken(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name  string `json:"name"`
+		Scope string `json:"scope"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		http.Error(w, "Missing name", http.StatusBadRequest)
+		return
+	}
+	scope, err := auth.ParseScope(req.Scope)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	token, stored, err := auth.CreateToken(r.Context(), s.cfg.Conn, req.Name, scope)
+	switch {
+	case errors.Is(err, auth.ErrTokenExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		slog.Error("Failed to create API token", "error", err)
+		http.Error(w, "Failed to create API token", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("API token created",
+		"name", stored.Name,
+		"scope", stored.Scope,
+		"by", principalFrom(r.Context()).Name,
+	)
+	result := newAPIToken(stored)
+	result.Token = token
+	util.RespondJSON(w, http.StatusCreated, result)
+}
+
+// AdminDeleteToken revokes an API token
+func (s *Server) AdminDeleteToken(w http.ResponseWriter, r *http.Request) {
+	tokenID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid token ID", http.StatusBadRequest)
+		return
+	}
+
+	n, err := s.cfg.Conn.Q.DeleteAPIToken(r.Context(), tokenID)
+	if err != nil {
+		slog.Error("Failed to delete API token", "id", tokenID, "error", err)
+		http.Error(w, "Failed to delete API token", http.StatusInternalServerError)
+		return
+	}
+	if n == 0 {
+		http.Error(w, "Token not found", http.StatusNotFound)
+		return
+	}
+
+	slog.Info("API token revoked", "id", tokenID, "by", principalFrom(r.Context()).Name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeMonitorRequest(w http.ResponseWriter, r *http.Request) (config.MonitorConfig, bool) {
+	var req MonitorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return config.MonitorConfig{}, false
+	}
+
+	m := config.MonitorConfig{
+		Name:                req.Name,
+		URL:                 req.URL,
+		CheckInterval:       req.CheckInterval,
+		Group:               req.Group,
+		DegradedThresholdMs: req.DegradedThresholdMs,
+		DegradedPercentile:  req.DegradedPercentile,
+		Public:              req.Public,
+	}
+	if req.DegradedWindow != "" {
+		window, err := time.ParseDuration(req.DegradedWindow)
+		if err != nil {
+			http.Error(w, "Invalid degraded_window, expected e.g. 5m", http.StatusBadRequest)
+			return config.MonitorConfig{}, false
+		}
+		m.DegradedWindow = window
+	}
+	return m, true
+}
+
+func respondMonitorError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, config.ErrMonitorInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, config.ErrMonitorNotFound):
+		http.Error(w, "Monitor not found", http.StatusNotFound)
+	case errors.Is(err, config.ErrMonitorReadOnly), errors.Is(err, config.ErrMonitorExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		slog.Error("Failed to change monitor", "error", err)
+		http.Error(w, "Failed to change monitor", http.StatusInternalServerError)
+	}
+}
+
+func newAdminMonitor(m *db.Monitor) AdminMonitor {
+	monitor := AdminMonitor{
+		ID:                  m.ID,
+		Name:                m.Name,
+		URL:                 m.Url,
+		CheckInterval:       m.CheckInterval,
+		Group:               m.GroupName,
+		DegradedThresholdMs: m.DegradedThreshold,
+		DegradedPercentile:  m.DegradedPercentile,
+		Public:              m.Public,
+		Source:              m.Source,
+		CreatedAt:           m.CreatedAt,
+		UpdatedAt:           m.UpdatedAt,
+	}
+	if m.DegradedPercentile > 0 {
+		monitor.DegradedWindow = (time.Duration(m.DegradedWindow) * time.Second).String()
+	}
+	return monitor
+}
+
+func newAPIToken(t *db.ApiToken) APIToken {
+	return APIToken{
+		ID:         t.ID,
+		Name:       t.Name,
+		Scope:      t.Scope,
+		LastUsedAt: t.LastUsedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
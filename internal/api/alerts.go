@@ -0,0 +1,193 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/util"
+)
+
+// ackPage asks for confirmation, so link previews in chat apps can't acknowledge alerts
+var ackPage = template.Must(template.New("ack").Parse(`<!doctype html>
+<html>
+<head><meta charset="utf-8"><title>Acknowledge alert</title></head>
+<body style="font-family: sans-serif; max-width: 32rem; margin: 4rem auto">
+{{if .Done}}
+<p>{{.Message}}</p>
+{{else}}
+<form method="post">
+  <input type="hidden" name="token" value="{{.Token}}">
+  <p>Acknowledge alert #{{.ID}} and stop further escalation?</p>
+  <input name="by" placeholder="Your name" required>
+  <button type="submit">Acknowledge</button>
+</form>
+{{end}}
+</body>
+</html>`))
+
+// AlertStatus describes the open alert of a monitor
+type AlertStatus struct {
+	ID             int64      `json:"id"`
+	StartedAt      time.Time  `json:"started_at"`
+	Reason         string     `json:"reason"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy *string    `json:"acknowledged_by,omitempty"`
+	SnoozedUntil   *time.Time `json:"snoozed_until,omitempty"`
+	SnoozedBy      *string    `json:"snoozed_by,omitempty"`
+}
+
+func newAlertStatus(alert *db.Alert) *AlertStatus {
+	return &AlertStatus{
+		ID:             alert.ID,
+		StartedAt:      alert.StartedAt,
+		Reason:         alert.Reason,
+		AcknowledgedAt: alert.AcknowledgedAt,
+		AcknowledgedBy: alert.AcknowledgedBy,
+		SnoozedUntil:   alert.SnoozedUntil,
+		SnoozedBy:      alert.SnoozedBy,
+	}
+}
+
+type ackPageData struct {
+	ID      int64
+	Token   string
+	Done    bool
+	Message string
+}
+
+// GetAlertAck renders the confirmation page for a signed acknowledgement link
+func (s *Server) GetAlertAck(w http.ResponseWriter, r *http.Request) {
+	alertID, ok := s.verifyAlertLink(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := ackPage.Execute(w, ackPageData{
+		ID:    alertID,
+		Token: r.FormValue("token"),
+	}); err != nil {
+		slog.Error("failed to render page", "error", err)
+	}
+}
+
+// AcknowledgeAlert stops the escalation of an alert
+func (s *Server) AcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	alertID, ok := s.verifyAlertLink(w, r)
+	if !ok {
+		return
+	}
+
+	by := strings.TrimSpace(r.FormValue("by"))
+	if by == "" {
+		by = "link"
+	}
+
+	message := "Alert acknowledged"
+	alert, err := s.cfg.Notifier.Acknowledge(r.Context(), alertID, by)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		message = "Alert is already acknowledged or resolved"
+	case err != nil:
+		http.Error(w, "Failed to acknowledge alert", http.StatusInternalServerError)
+		return
+	default:
+		slog.Info("Alert acknowledged", "alert_id", alert.ID, "by", by)
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := ackPage.Execute(w, ackPageData{Done: true, Message: message}); err != nil {
+			slog.Error("failed to render page", "error", err)
+		}
+		return
+	}
+
+	util.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": message,
+	})
+}
+
+func (s *Server) verifyAlertLink(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	alertID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid alert ID", http.StatusBadRequest)
+		return 0, false
+	}
+	if !s.cfg.Notifier.VerifyAlertToken(alertID, r.FormValue("token")) {
+		http.Error(w, "Invalid token", http.StatusForbidden)
+		return 0, false
+	}
+	return alertID, true
+}
+
+// AcknowledgeMonitor acknowledges the ongoing outage of a monitor
+func (s *Server) AcknowledgeMonitor(w http.ResponseWriter, r *http.Request) {
+	monitorID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+		return
+	}
+
+	by := principalFrom(r.Context()).Name
+	alert, err := s.cfg.Notifier.AcknowledgeMonitor(r.Context(), monitorID, by)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "No unacknowledged outage for this monitor", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to acknowledge alert", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("Alert acknowledged", "alert_id", alert.ID, "monitor_id", monitorID, "by", by)
+	util.RespondJSON(w, http.StatusOK, newAlertStatus(alert))
+}
+
+// SnoozeMonitor silences the ongoing outage of a monitor until the given time
+func (s *Server) SnoozeMonitor(w http.ResponseWriter, r *http.Request) {
+	monitorID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+		return
+	}
+
+	until, err := parseUntil(r.URL.Query().Get("until"))
+	if err != nil {
+		http.Error(w, "Invalid until: use RFC 3339 time or duration like 2h", http.StatusBadRequest)
+		return
+	}
+	if !until.After(time.Now()) {
+		http.Error(w, "Until must be in the future", http.StatusBadRequest)
+		return
+	}
+
+	by := principalFrom(r.Context()).Name
+	alert, err := s.cfg.Notifier.SnoozeMonitor(r.Context(), monitorID, until, by)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "No ongoing outage for this monitor", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to snooze alert", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("Alert snoozed", "alert_id", alert.ID, "monitor_id", monitorID, "until", until, "by", by)
+	util.RespondJSON(w, http.StatusOK, newAlertStatus(alert))
+}
+
+// parseUntil accepts either an absolute RFC 3339 time or a duration from now
+func parseUntil(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/auth"
+	"github.com/mizuchilabs/beacon/internal/config"
+	"github.com/mizuchilabs/beacon/internal/util"
+)
+
+type contextKey string
+
+const principalKey contextKey = "principal"
+
+// sessionCookie holds the session token of a signed in user
+const sessionCookie = "beacon_session"
+
+// Principal identifies the caller of an authenticated request
+type Principal struct {
+	Name  string     `json:"name"`
+	Scope auth.Scope `json:"scope"`
+}
+
+// WithAuth rejects requests that don't carry a valid API token with the
+// required scope. Signed in users of the dashboard have the read scope, or the
+// scope of their role with OpenID Connect.
+func (s *Server) WithAuth(scope auth.Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := principalFrom(r.Context())
+		if principal == nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="beacon"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !principal.Scope.Includes(scope) {
+			http.Error(w, "Token lacks the "+string(scope)+" scope", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// WithPrincipal identifies the caller once, for the handlers and WithAuth to
+// look up
+func (s *Server) WithPrincipal(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal := s.authenticate(r); principal != nil {
+			r = r.WithContext(context.WithValue(r.Context(), principalKey, principal))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate identifies the caller by a bearer token or, depending on the
+// auth mode, by the session or the proxy header of a dashboard user
+func (s *Server) authenticate(r *http.Request) *Principal {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && token != "" {
+		return s.tokenPrincipal(r, token)
+	}
+
+	switch s.cfg.Auth {
+	case config.AuthLocal, config.AuthOIDC:
+		return s.sessionPrincipal(r)
+	case config.AuthProxy:
+		return s.proxyPrincipal(r)
+	}
+	return nil
+}
+
+// tokenPrincipal looks up the bearer token among the tokens of the
+// environment, which have the admin scope, and those created through the CLI
+// or the API
+func (s *Server) tokenPrincipal(r *http.Request, token string) *Principal {
+	for name, t := range s.cfg.APITokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return &Principal{Name: name, Scope: auth.ScopeAdmin}
+		}
+	}
+
+	stored, err := s.cfg.Conn.Q.GetAPITokenByHash(r.Context(), auth.HashToken(token))
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("Failed to get API token", "error", err)
+		}
+		return nil
+	}
+	if err := s.cfg.Conn.Q.TouchAPIToken(r.Context(), stored.ID); err != nil {
+		slog.Error("Failed to update API token", "id", stored.ID, "error", err)
+	}
+	return &Principal{Name: stored.Name, Scope: auth.Scope(stored.Scope)}
+}
+
+// sessionPrincipal returns the user signed in with the session cookie
+func (s *Server) sessionPrincipal(r *http.Request) *Principal {
+	cookie, err := r.Cookie(sessionCookie)
+	if err != nil || cookie.Value == "" {
+		return nil
+	}
+	if err := s.csrf.Check(r); err != nil {
+		slog.Warn("Ignoring session of a cross-origin request", "origin", r.Header.Get("Origin"))
+		return nil
+	}
+	session, err := auth.GetSession(r.Context(), s.cfg.Conn, cookie.Value)
+	if err != nil {
+		if !errors.Is(err, auth.ErrInvalidCredentials) {
+			slog.Error("Failed to get session", "error", err)
+		}
+		return nil
+	}
+	return &Principal{Name: session.Name, Scope: session.Scope}
+}
+
+// newCrossOriginProtection trusts the dev server besides the own origin
+func newCrossOriginProtection() *http.CrossOriginProtection {
+	csrf := http.NewCrossOriginProtection()
+	if err := csrf.AddTrustedOrigin("http://localhost:5173"); err != nil {
+		panic(err)
+	}
+	return csrf
+}
+
+// proxyPrincipal returns the user named by the auth header, with the scope of
+// the role header if set, which are only trusted if the request comes from a
+// trusted proxy
+func (s *Server) proxyPrincipal(r *http.Request) *Principal {
+	name := strings.TrimSpace(r.Header.Get(s.cfg.AuthHeader))
+	if name == "" {
+		return nil
+	}
+	addr, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil || !s.trustedProxy(addr.Addr().Unmap()) {
+		// Any client can send the header, so this mustn't fill the logs
+		slog.Debug("Ignoring auth header of an untrusted address", "remote_addr", r.RemoteAddr)
+		return nil
+	}
+
+	scope := auth.ScopeRead
+	if s.cfg.AuthHeaderRole != "" {
+		if role := strings.TrimSpace(r.Header.Get(s.cfg.AuthHeaderRole)); role != "" {
+			if scope, err = auth.ParseRole(role); err != nil {
+				slog.Debug("Ignoring invalid role of the proxy", "username", name, "error", err)
+				scope = auth.ScopeRead
+			}
+		}
+	}
+	return &Principal{Name: name, Scope: scope}
+}
+
+func (s *Server) trustedProxy(addr netip.Addr) bool {
+	for _, prefix := range s.cfg.Proxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// principalFrom returns the authenticated caller, if any
+func principalFrom(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey).(*Principal)
+	return principal
+}
+
+// canView reports whether the request may see a monitor, private monitors are
+// only shown to signed in users and API clients
+func (s *Server) canView(r *http.Request, public bool) bool {
+	return public || principalFrom(r.Context()) != nil
+}
+
+// GetSession returns the signed in user, if any
+func (s *Server) GetSession(w http.ResponseWriter, r *http.Request) {
+	util.RespondJSON(w, http.StatusOK, map[string]any{
+		"user": principalFrom(r.Context()),
+	})
+}
+
+// Login signs a local user in with a session cookie
+func (s *Server) Login(w http.ResponseWriter, r *http.Request) {
+	if s.cfg.Auth != config.AuthLocal {
+		http.Error(w, "Local users are disabled", http.StatusNotFound)
+		return
+	}
+
+	var req struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	session, token, expires, err := auth.Login(r.Context(), s.cfg.Conn, req.Username, req.Password, s.cfg.SessionDuration)
+	switch {
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		slog.Warn("Failed sign in", "username", req.Username)
+		http.Error(w, "Invalid username or password", http.StatusUnauthorized)
+		return
+	case errors.Is(err, auth.ErrTooManyAttempts):
+		slog.Warn("Throttled sign in", "username", req.Username)
+		http.Error(w, "Too many failed sign ins, please try again later", http.StatusTooManyRequests)
+		return
+	case err != nil:
+		slog.Error("Failed to sign in", "error", err)
+		http.Error(w, "Failed to sign in", http.StatusInternalServerError)
+		return
+	}
+
+	s.setSessionCookie(w, r, token, expires)
+	slog.Info("User signed in", "username", session.Name, "scope", session.Scope)
+	util.RespondJSON(w, http.StatusOK, map[string]any{
+		"user": &Principal{Name: session.Name, Scope: session.Scope},
+	})
+}
+
+// Logout ends the session of a local or OpenID Connect user
+func (s *Server) Logout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookie); err == nil && cookie.Value != "" {
+		if err := auth.EndSession(r.Context(), s.cfg.Conn, cookie.Value); err != nil {
+			slog.Error("Failed to delete session", "error", err)
+			http.Error(w, "Failed to sign out", http.StatusInternalServerError)
+			return
+		}
+	}
+	s.setSessionCookie(w, r, "", time.Unix(0, 0))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setSessionCookie sets the session cookie, or clears it if it expired
+func (s *Server) setSessionCookie(w http.ResponseWriter, r *http.Request, token string, expires time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure: r.TLS != nil ||
+			r.Header.Get("X-Forwarded-Proto") == "https" ||
+			strings.HasPrefix(s.cfg.BaseURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/mizuchilabs/beacon/internal/auth"
+	"github.com/mizuchilabs/beacon/internal/config"
+)
+
+func TestProxyPrincipal(t *testing.T) {
+	s := NewServer(&config.Config{
+		EnvConfig: config.EnvConfig{
+			Auth:           config.AuthProxy,
+			AuthHeader:     "X-Forwarded-User",
+			AuthHeaderRole: "X-Forwarded-Role",
+		},
+		Proxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		user, role string
+		want       *Principal
+	}{
+		{"viewer by default", "10.0.0.1:1234", "alice", "", &Principal{Name: "alice", Scope: auth.ScopeRead}},
+		{"role header", "10.0.0.1:1234", "alice", "editor", &Principal{Name: "alice", Scope: auth.ScopeWrite}},
+		{"invalid role", "10.0.0.1:1234", "alice", "root", &Principal{Name: "alice", Scope: auth.ScopeRead}},
+		{"untrusted address", "192.0.2.1:1234", "alice", "admin", nil},
+		{"without user", "10.0.0.1:1234", "", "admin", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/auth/session", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-User", tt.user)
+			req.Header.Set("X-Forwarded-Role", tt.role)
+
+			got := s.proxyPrincipal(req)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("proxyPrincipal() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/scheduler"
+	"github.com/mizuchilabs/beacon/internal/util"
+)
+
+// Badge colors, named as on shields.io
+const (
+	colorBrightGreen = "brightgreen"
+	colorGreen       = "green"
+	colorYellow      = "yellow"
+	colorOrange      = "orange"
+	colorRed         = "red"
+	colorBlue        = "blue"
+	colorGrey        = "lightgrey"
+)
+
+var badgeColors = map[string]string{
+	colorBrightGreen: "#4c1",
+	colorGreen:       "#97ca00",
+	colorYellow:      "#dfb317",
+	colorOrange:      "#fe7d37",
+	colorRed:         "#e05d44",
+	colorBlue:        "#007ec6",
+	colorGrey:        "#9f9f9f",
+}
+
+// Badge is a label and a colored message
+type Badge struct {
+	Label   string
+	Message string
+	Color   string
+}
+
+// ShieldsBadge is the endpoint format of shields.io
+type ShieldsBadge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+	CacheSeconds  int    `json:"cacheSeconds,omitempty"`
+}
+
+// GetBadge renders the status, uptime or response time of a monitor as an
+// SVG badge, or as JSON for the endpoint badges of shields.io, e.g.
+// /api/badge/1/uptime.svg?period=30d
+func (s *Server) GetBadge(w http.ResponseWriter, r *http.Request) {
+	monitorID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+		return
+	}
+
+	name := r.PathValue("badge")
+	ext := path.Ext(name)
+	if ext != ".svg" && ext != ".json" {
+		http.Error(w, "Badge not found", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	style := query.Get("style")
+	if style != "" && style != "flat" && style != "flat-square" {
+		http.Error(w, "Invalid style, expected flat or flat-square", http.StatusBadRequest)
+		return
+	}
+	period, err := parsePeriod(query.Get("period"))
+	if err != nil {
+		http.Error(w, "Invalid period, expected e.g. 24h or 30d", http.StatusBadRequest)
+		return
+	}
+
+	seconds := strconv.FormatInt(int64(period.Seconds()), 10)
+	stats, err := s.cfg.Conn.Q.GetMonitorStats(r.Context(), &db.GetMonitorStatsParams{
+		Seconds:   &seconds,
+		MonitorID: &monitorID,
+	})
+	if err != nil {
+		slog.Error("Failed to get monitor stats", "monitor_id", monitorID, "error", err)
+		http.Error(w, "Failed to get monitor stats", http.StatusInternalServerError)
+		return
+	}
+	if len(stats) == 0 || !s.canView(r, stats[0].Public) {
+		http.Error(w, "Monitor not found", http.StatusNotFound)
+		return
+	}
+	stat := stats[0]
+
+	var badge Badge
+	switch strings.TrimSuffix(name, ext) {
+	case "status":
+		badge = s.statusBadge(stat)
+	case "uptime":
+		badge = uptimeBadge(stat, query.Get("period"))
+	case "response":
+		badge = responseBadge(stat)
+	default:
+		http.Error(w, "Badge not found", http.StatusNotFound)
+		return
+	}
+	if label, ok := query["label"]; ok {
+		badge.Label = label[0]
+	}
+
+	// Badges change at most once per check, private ones mustn't be kept by
+	// shared caches for other callers
+	maxAge := int(stat.CheckInterval)
+	cache := "public"
+	if !stat.Public {
+		cache = "private"
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", cache, maxAge))
+
+	if ext == ".json" {
+		util.RespondJSON(w, http.StatusOK, ShieldsBadge{
+			SchemaVersion: 1,
+			Label:         badge.Label,
+			Message:       badge.Message,
+			Color:         badge.Color,
+			CacheSeconds:  max(maxAge, 300), // the minimum of shields.io
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(badge.SVG(style == "flat-square")); err != nil {
+		slog.Error("Failed to write badge", "error", err)
+	}
+}
+
+func (s *Server) statusBadge(stat *db.GetMonitorStatsRow) Badge {
+	badge := Badge{Label: stat.Name, Message: "unknown", Color: colorGrey}
+	if _, ok := s.cfg.Maintenance.Active(stat.Name, stat.GroupName, time.Now()); ok {
+		badge.Message, badge.Color = "maintenance", colorBlue
+		return badge
+	}
+
+	switch s.cfg.Scheduler.State(stat.ID).Status {
+	case scheduler.StatusUp:
+		badge.Message, badge.Color = "up", colorBrightGreen
+	case scheduler.StatusDegraded:
+		badge.Message, badge.Color = "degraded", colorYellow
+	case scheduler.StatusDown:
+		badge.Message, badge.Color = "down", colorRed
+	}
+	return badge
+}
+
+func uptimeBadge(stat *db.GetMonitorStatsRow, period string) Badge {
+	label := "uptime"
+	if period != "" {
+		label += " " + period
+	}
+	if stat.TotalChecks == 0 {
+		return Badge{Label: label, Message: "no data", Color: colorGrey}
+	}
+
+	color := colorRed
+	switch pct := stat.UptimePct; {
+	case pct >= 99.9:
+		color = colorBrightGreen
+	case pct >= 99:
+		color = colorGreen
+	case pct >= 95:
+		color = colorYellow
+	case pct >= 90:
+		color = colorOrange
+	}
+	return Badge{
+		Label:   label,
+		Message: strconv.FormatFloat(stat.UptimePct, 'f', -1, 64) + "%",
+		Color:   color,
+	}
+}
+
+func responseBadge(stat *db.GetMonitorStatsRow) Badge {
+	badge := Badge{Label: "response time", Message: "no data", Color: colorGrey}
+	if stat.TotalChecks == 0 {
+		return badge
+	}
+
+	badge.Message = fmt.Sprintf("%d ms", stat.AvgResponseTime)
+	badge.Color = colorBrightGreen
+	if stat.AvgResponseTime > stat.DegradedThreshold {
+		badge.Color = colorYellow
+	}
+	return badge
+}
+
+// parsePeriod parses periods like 24h or 30d, one day by default
+func parsePeriod(v string) (time.Duration, error) {
+	if v == "" {
+		return 24 * time.Hour, nil
+	}
+
+	unit := time.Hour
+	switch {
+	case strings.HasSuffix(v, "d"):
+		unit = 24 * time.Hour
+	case !strings.HasSuffix(v, "h"):
+		return 0, fmt.Errorf("invalid period %q", v)
+	}
+	n, err := strconv.Atoi(v[:len(v)-1])
+	if err != nil || n < 1 || time.Duration(n)*unit > 365*24*time.Hour {
+		return 0, fmt.Errorf("invalid period %q", v)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// SVG renders the badge like those of shields.io, with rounded corners unless
+// square
+func (b Badge) SVG(square bool) []byte {
+	color, ok := badgeColors[b.Color]
+	if !ok {
+		color = badgeColors[colorGrey]
+	}
+
+	// Text is padded by 5px on each side
+	labelWidth := textWidth(b.Label) + 10
+	messageWidth := textWidth(b.Message) + 10
+	width := labelWidth + messageWidth
+
+	radius, gradient := 3, `<rect width="100%" height="20" fill="url(#s)"/>`
+	if square {
+		radius, gradient = 0, ""
+	}
+
+	label, message := html.EscapeString(b.Label), html.EscapeString(b.Message)
+	return fmt.Appendf(nil, `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[2]s: %[3]s">`+
+		`<title>%[2]s: %[3]s</title>`+
+		`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`+
+		`<clipPath id="r"><rect width="%[1]d" height="20" rx="%[4]d" fill="#fff"/></clipPath>`+
+		`<g clip-path="url(#r)"><rect width="%[5]d" height="20" fill="#555"/><rect x="%[5]d" width="%[6]d" height="20" fill="%[7]s"/>%[8]s</g>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`+
+		`<text x="%[9]d" y="15" fill="#010101" fill-opacity=".3">%[2]s</text><text x="%[9]d" y="14">%[2]s</text>`+
+		`<text x="%[10]d" y="15" fill="#010101" fill-opacity=".3">%[3]s</text><text x="%[10]d" y="14">%[3]s</text>`+
+		`</g></svg>`,
+		width, label, message, radius,
+		labelWidth, messageWidth, color, gradient,
+		labelWidth/2, labelWidth+messageWidth/2,
+	)
+}
+
+// textWidth approximates the width of text in 11px Verdana
+func textWidth(s string) int {
+	var width float64
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune("ijlt.,:;!|'", r):
+			width += 3.5
+		case r == ' ' || strings.ContainsRune("frI()[]", r):
+			width += 4.5
+		case strings.ContainsRune("mwMW%", r):
+			width += 10.5
+		case r >= 'A' && r <= 'Z':
+			width += 7.5
+		default:
+			width += 7
+		}
+	}
+	return int(width + 0.5)
+}
@@ -29,9 +29,9 @@ func (s *Server) WithCORS(h http.Handler) http.Handler {
 
 	return cors.New(cors.Options{
 		AllowedOrigins:   allowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Content-Type"},
-		AllowCredentials: false,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
+		AllowCredentials: true, // session cookies of the dev server
 		MaxAge:           int(2 * time.Hour / time.Second),
 	}).Handler(h)
 }
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"github.com/mizuchilabs/beacon/internal/util"
+)
+
+// emailPage asks for confirmation, so mail scanners following links can't
+// confirm or cancel subscriptions
+var emailPage = template.Must(template.New("email").Parse(`<!doctype html>
+<html>
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body style="font-family: sans-serif; max-width: 32rem; margin: 4rem auto">
+{{if .Done}}
+<p>{{.Message}}</p>
+{{else}}
+<form method="post">
+  <p>{{.Message}}</p>
+  <button type="submit">{{.Title}}</button>
+</form>
+{{end}}
+</body>
+</html>`))
+
+type emailPageData struct {
+	Title   string
+	Message string
+	Done    bool
+}
+
+type EmailSubscribeRequest struct {
+	Email     string `json:"email"`
+	MonitorID *int64 `json:"monitor_id,omitempty"` // all monitors and incidents if empty
+}
+
+// SubscribeEmail starts an email subscription and sends the confirmation link
+func (s *Server) SubscribeEmail(w http.ResponseWriter, r *http.Request) {
+	if !s.cfg.Notifier.EmailEnabled() {
+		http.Error(w, "Email subscriptions are disabled", http.StatusNotFound)
+		return
+	}
+
+	var req EmailSubscribeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	addr, err := mail.ParseAddress(req.Email)
+	if err != nil || addr.Name != "" {
+		http.Error(w, "Invalid email address", http.StatusBadRequest)
+		return
+	}
+	email := strings.ToLower(addr.Address)
+
+	if req.MonitorID != nil {
+		monitor, err := s.cfg.Conn.Q.GetMonitor(r.Context(), *req.MonitorID)
+		if err != nil || !s.canView(r, monitor.Public) {
+			http.Error(w, "Unknown monitor", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := s.cfg.Notifier.SubscribeEmail(r.Context(), email, req.MonitorID); err != nil {
+		slog.Error("Failed to subscribe email", "error", err)
+		http.Error(w, "Failed to subscribe", http.StatusInternalServerError)
+		return
+	}
+
+	// Same answer for new and existing subscriptions, so addresses can't be probed
+	util.RespondJSON(w, http.StatusAccepted, map[string]string{
+		"message": "Check your inbox to confirm the subscription",
+	})
+}
+
+// GetEmailConfirm renders the confirmation page for a signed confirmation link
+func (s *Server) GetEmailConfirm(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.verifyConfirmLink(w, r); !ok {
+		return
+	}
+	renderEmailPage(w, r, emailPageData{
+		Title:   "Confirm subscription",
+		Message: "Receive status updates by email?",
+	})
+}
+
+// ConfirmEmail activates an email subscription
+func (s *Server) ConfirmEmail(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.verifyConfirmLink(w, r)
+	if !ok {
+		return
+	}
+
+	_, err := s.cfg.Conn.Q.GetEmailSubscriber(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Subscription not found, please subscribe again", http.StatusNotFound)
+		return
+	}
+	if err == nil {
+		err = s.cfg.Conn.Q.ConfirmEmailSubscriber(r.Context(), id)
+	}
+	if err != nil {
+		http.Error(w, "Failed to confirm subscription", http.StatusInternalServerError)
+		return
+	}
+
+	renderEmailPage(w, r, emailPageData{Done: true, Message: "Subscription confirmed"})
+}
+
+// GetEmailUnsubscribe renders the confirmation page for a signed unsubscribe link
+func (s *Server) GetEmailUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.verifyUnsubscribeLink(w, r); !ok {
+		return
+	}
+	renderEmailPage(w, r, emailPageData{
+		Title:   "Unsubscribe",
+		Message: "Stop receiving status updates by email?",
+	})
+}
+
+// UnsubscribeEmail removes all subscriptions of an address. Mail clients
+// post here directly for one-click unsubscribes.
+func (s *Server) UnsubscribeEmail(w http.ResponseWriter, r *http.Request) {
+	email, ok := s.verifyUnsubscribeLink(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.cfg.Conn.Q.DeleteEmailSubscribersByAddress(r.Context(), email); err != nil {
+		http.Error(w, "Failed to unsubscribe", http.StatusInternalServerError)
+		return
+	}
+	slog.Info("Email subscriber unsubscribed")
+
+	renderEmailPage(w, r, emailPageData{Done: true, Message: "Unsubscribed successfully"})
+}
+
+func (s *Server) verifyConfirmLink(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid subscription ID", http.StatusBadRequest)
+		return 0, false
+	}
+	if !s.cfg.Notifier.VerifyConfirmToken(id, r.FormValue("token")) {
+		http.Error(w, "Invalid token", http.StatusForbidden)
+		return 0, false
+	}
+	return id, true
+}
+
+func (s *Server) verifyUnsubscribeLink(w http.ResponseWriter, r *http.Request) (string, bool) {
+	email := strings.ToLower(r.FormValue("email"))
+	if email == "" {
+		http.Error(w, "Missing email", http.StatusBadRequest)
+		return "", false
+	}
+	if !s.cfg.Notifier.VerifyUnsubscribeToken(email, r.FormValue("token")) {
+		http.Error(w, "Invalid token", http.StatusForbidden)
+		return "", false
+	}
+	return email, true
+}
+
+// renderEmailPage answers browsers with a page and API clients with JSON
+func renderEmailPage(w http.ResponseWriter, r *http.Request, data emailPageData) {
+	if r.Method != http.MethodGet && !strings.Contains(r.Header.Get("Accept"), "text/html") {
+		util.RespondJSON(w, http.StatusOK, map[string]string{
+			"message": data.Message,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := emailPage.Execute(w, data); err != nil {
+		slog.Error("failed to render page", "error", err)
+	}
+}
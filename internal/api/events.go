@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/scheduler"
+)
+
+// keepAliveInterval keeps idle streams from being closed by proxies
+const keepAliveInterval = 30 * time.Second
+
+// StreamEvents streams check results and state changes of monitors as
+// server-sent events, optionally only those of the given monitors
+func (s *Server) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	var monitorIDs []int64
+	for _, v := range r.URL.Query()["monitor"] {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+			return
+		}
+		monitorIDs = append(monitorIDs, id)
+	}
+
+	// Private monitors are left out for anonymous visitors
+	showPrivate := principalFrom(r.Context()) != nil
+
+	// Streams outlive the write timeout of the server
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	events, unsubscribe := s.cfg.Events.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 5000\n\n")
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			var monitorID int64
+			var private bool
+			switch data := event.Data.(type) {
+			case scheduler.CheckEvent:
+				monitorID, private = data.MonitorID, data.Private
+			case scheduler.StateEvent:
+				monitorID, private = data.MonitorID, data.Private
+			}
+			if len(monitorIDs) > 0 && !slices.Contains(monitorIDs, monitorID) {
+				continue
+			}
+			if private && !showPrivate {
+				continue
+			}
+
+			data, err := json.Marshal(event.Data)
+			if err != nil {
+				slog.Error("Failed to encode event", "type", event.Type, "error", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case <-r.Context().Done():
+			return
+		case <-s.shutdown:
+			return
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
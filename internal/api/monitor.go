@@ -2,6 +2,9 @@ package api
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"slices"
@@ -9,18 +12,32 @@ import (
 	"time"
 
 	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/export"
+	"github.com/mizuchilabs/beacon/internal/slo"
 	"github.com/mizuchilabs/beacon/internal/util"
 )
 
 type MonitorStats struct {
-	ID              int64       `json:"id"`
-	Name            string      `json:"name"`
-	URL             string      `json:"url"`
-	CheckInterval   int64       `json:"check_interval"`
-	AvgResponseTime int64       `json:"avg_response_time"`
-	UptimePct       float64     `json:"uptime_pct"`
-	Percentiles     Percentiles `json:"percentiles"`
-	Datapoints      []DataPoint `json:"data_points"`
+	ID              int64        `json:"id"`
+	Name            string       `json:"name"`
+	URL             string       `json:"url"`
+	CheckInterval   int64        `json:"check_interval"`
+	AvgResponseTime int64        `json:"avg_response_time"`
+	UptimePct       float64      `json:"uptime_pct"`
+	Percentiles     Percentiles  `json:"percentiles"`
+	Datapoints      []DataPoint  `json:"data_points"`
+	Alert           *AlertStatus `json:"alert,omitempty"`
+	Group           string       `json:"group,omitempty"`
+	Maintenance     string       `json:"maintenance,omitempty"` // active maintenance window
+	Flapping        bool         `json:"flapping,omitempty"`
+	Status          string       `json:"status,omitempty"`  // up, degraded or down, empty if unknown
+	Private         bool         `json:"private,omitempty"` // only shown to signed in users
+
+	// Checks slower than this count as degraded
+	DegradedThreshold int64 `json:"degraded_threshold"`
+
+	// Error budgets of the service level objective, if any
+	SLO *slo.Status `json:"slo,omitempty"`
 }
 
 type Percentiles struct {
@@ -32,12 +49,31 @@ type Percentiles struct {
 }
 
 type DataPoint struct {
-	Timestamp     time.Time `json:"timestamp"`
-	ResponseTime  int64     `json:"response_time"`
-	IsUp          bool      `json:"is_up"`
-	UpRatio       float64   `json:"up_ratio,omitempty"`
-	DegradedRatio float64   `json:"degraded_ratio,omitempty"`
-	DownRatio     float64   `json:"down_ratio,omitempty"`
+	Timestamp        time.Time `json:"timestamp"`
+	ResponseTime     int64     `json:"response_time"`
+	IsUp             bool      `json:"is_up"`
+	UpRatio          float64   `json:"up_ratio,omitempty"`
+	DegradedRatio    float64   `json:"degraded_ratio,omitempty"`
+	DownRatio        float64   `json:"down_ratio,omitempty"`
+	MaintenanceRatio float64   `json:"maintenance_ratio,omitempty"`
+}
+
+// CheckResult is a single check of a monitor
+type CheckResult struct {
+	CheckedAt     time.Time  `json:"checked_at"`
+	IsUp          bool       `json:"is_up"`
+	StatusCode    int64      `json:"status_code"`
+	ResponseTime  int64      `json:"response_time"`
+	Error         *string    `json:"error,omitempty"`
+	State         *string    `json:"state,omitempty"` // maintenance or dependency_down
+	CertExpiresAt *time.Time `json:"cert_expires_at,omitempty"`
+}
+
+// CheckPage holds checks, newest first, and the cursor of the next page if
+// there are more
+type CheckPage struct {
+	Checks     []CheckResult `json:"checks"`
+	NextCursor string        `json:"next_cursor,omitempty"`
 }
 
 func (s *Server) GetConfig(w http.ResponseWriter, r *http.Request) {
@@ -47,29 +83,74 @@ func (s *Server) GetConfig(w http.ResponseWriter, r *http.Request) {
 		"timezone":          s.cfg.Timezone,
 		"chart_type":        s.cfg.ChartType,
 		"incidents_enabled": s.cfg.Incidents != nil,
+		"email_enabled":     s.cfg.Notifier.EmailEnabled(),
+		"auth":              s.cfg.Auth, // local, proxy or empty if disabled
 	})
 }
 
 func (s *Server) GetMonitors(w http.ResponseWriter, r *http.Request) {
-	secondsStr := r.URL.Query().Get("seconds")
+	result, err := s.monitorStats(r, r.URL.Query().Get("seconds"), nil)
+	if err != nil {
+		slog.Error("Failed to get monitor stats", "error", err)
+		http.Error(w, "Failed to get monitor stats", http.StatusInternalServerError)
+		return
+	}
+
+	util.RespondJSON(w, http.StatusOK, result)
+}
+
+// GetMonitor returns the stats and data points of a single monitor
+func (s *Server) GetMonitor(w http.ResponseWriter, r *http.Request) {
+	monitorID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := s.monitorStats(r, r.URL.Query().Get("seconds"), &monitorID)
+	if err != nil {
+		slog.Error("Failed to get monitor stats", "monitor_id", monitorID, "error", err)
+		http.Error(w, "Failed to get monitor stats", http.StatusInternalServerError)
+		return
+	}
+	if len(result) == 0 {
+		http.Error(w, "Monitor not found", http.StatusNotFound)
+		return
+	}
+
+	util.RespondJSON(w, http.StatusOK, result[0])
+}
+
+// monitorStats returns the stats of all monitors the request may see, or
+// only of the given one, over the last seconds, one day by default
+func (s *Server) monitorStats(
+	r *http.Request,
+	secondsStr string,
+	monitorID *int64,
+) ([]MonitorStats, error) {
+	ctx := r.Context()
 	if secondsStr == "" {
 		secondsStr = "86400"
 	}
 
 	seconds, _ := strconv.ParseInt(secondsStr, 10, 64)
-	stats, err := s.cfg.Conn.Q.GetMonitorStats(r.Context(), &secondsStr)
+	stats, err := s.cfg.Conn.Q.GetMonitorStats(ctx, &db.GetMonitorStatsParams{
+		Seconds:   &secondsStr,
+		MonitorID: monitorID,
+	})
 	if err != nil {
-		http.Error(w, "Failed to get monitor stats", http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 
 	since := time.Now().Add(-time.Duration(seconds) * time.Second)
 	slog.Debug("GetMonitors", "seconds", seconds, "since", since)
 
-	responseTimes, err := s.cfg.Conn.Q.GetResponseTimes(r.Context(), since)
+	responseTimes, err := s.cfg.Conn.Q.GetResponseTimes(ctx, &db.GetResponseTimesParams{
+		Since:     since,
+		MonitorID: monitorID,
+	})
 	if err != nil {
-		http.Error(w, "Failed to get response times", http.StatusInternalServerError)
-		return
+		return nil, fmt.Errorf("failed to get response times: %w", err)
 	}
 
 	timesByMonitor := make(map[int64][]int64)
@@ -93,15 +174,34 @@ func (s *Server) GetMonitors(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	pointsByMonitor, err := s.getDataPoints(r.Context(), seconds, since)
+	pointsByMonitor, err := s.getDataPoints(ctx, seconds, since, monitorID)
 	if err != nil {
-		http.Error(w, "Failed to get data points", http.StatusInternalServerError)
-		return
+		return nil, fmt.Errorf("failed to get data points: %w", err)
 	}
 
-	result := make([]MonitorStats, len(stats))
-	for i, stat := range stats {
-		result[i] = MonitorStats{
+	openAlerts, err := s.cfg.Conn.Q.GetOpenAlerts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alerts: %w", err)
+	}
+
+	alertsByMonitor := make(map[int64]*AlertStatus, len(openAlerts))
+	for _, alert := range openAlerts {
+		alertsByMonitor[alert.MonitorID] = newAlertStatus(alert)
+	}
+
+	now := time.Now()
+	result := make([]MonitorStats, 0, len(stats))
+	for _, stat := range stats {
+		if !s.canView(r, stat.Public) {
+			continue
+		}
+		window, _ := s.cfg.Maintenance.Active(stat.Name, stat.GroupName, now)
+		state := s.cfg.Scheduler.State(stat.ID)
+		budget, err := s.cfg.SLO.Status(ctx, stat.ID, stat.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get error budgets: %w", err)
+		}
+		result = append(result, MonitorStats{
 			ID:              stat.ID,
 			Name:            stat.Name,
 			URL:             stat.Url,
@@ -110,23 +210,218 @@ func (s *Server) GetMonitors(w http.ResponseWriter, r *http.Request) {
 			AvgResponseTime: stat.AvgResponseTime,
 			Percentiles:     percentilesByMonitor[stat.ID],
 			Datapoints:      pointsByMonitor[stat.ID],
+			Alert:           alertsByMonitor[stat.ID],
+			Group:           stat.GroupName,
+			Maintenance:     window,
+			Flapping:        state.Flapping,
+			Status:          string(state.Status),
+			Private:         !stat.Public,
+
+			DegradedThreshold: stat.DegradedThreshold,
+			SLO:               budget,
+		})
+	}
+	return result, nil
+}
+
+// GetMonitorChecks returns the raw checks of a monitor, filtered by an
+// optional time range and status, in pages
+func (s *Server) GetMonitorChecks(w http.ResponseWriter, r *http.Request) {
+	monitorID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	params := &db.GetChecksParams{MonitorID: monitorID, Limit: 100}
+
+	if params.Since, err = parseTimeParam(query.Get("from")); err != nil {
+		http.Error(w, "Invalid from time, expected RFC 3339", http.StatusBadRequest)
+		return
+	}
+	if params.Until, err = parseTimeParam(query.Get("to")); err != nil {
+		http.Error(w, "Invalid to time, expected RFC 3339", http.StatusBadRequest)
+		return
+	}
+	switch v := query.Get("status"); v {
+	case "":
+	case "up", "down":
+		isUp := v == "up"
+		params.IsUp = &isUp
+	default:
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+	if v := query.Get("cursor"); v != "" {
+		before, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		params.Before = &before
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || limit < 1 || limit > 500 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
 		}
+		params.Limit = limit
 	}
 
-	util.RespondJSON(w, http.StatusOK, result)
+	if _, ok := s.getMonitor(w, r, monitorID); !ok {
+		return
+	}
+
+	// One more than requested tells whether there is a next page
+	limit := params.Limit
+	params.Limit++
+	checks, err := s.cfg.Conn.Q.GetChecks(r.Context(), params)
+	if err != nil {
+		slog.Error("Failed to get checks", "monitor_id", monitorID, "error", err)
+		http.Error(w, "Failed to get checks", http.StatusInternalServerError)
+		return
+	}
+
+	page := CheckPage{Checks: make([]CheckResult, 0, min(len(checks), int(limit)))}
+	if int64(len(checks)) > limit {
+		checks = checks[:limit]
+		page.NextCursor = strconv.FormatInt(checks[limit-1].CheckedAt.Unix(), 10)
+	}
+	for _, c := range checks {
+		page.Checks = append(page.Checks, CheckResult{
+			CheckedAt:     c.CheckedAt,
+			IsUp:          c.IsUp,
+			StatusCode:    c.StatusCode,
+			ResponseTime:  c.ResponseTime,
+			Error:         c.Error,
+			State:         c.State,
+			CertExpiresAt: c.CertExpiresAt,
+		})
+	}
+
+	util.RespondJSON(w, http.StatusOK, page)
+}
+
+// exportTimeout bounds the time to stream an export, beyond the write
+// timeout of other responses
+const exportTimeout = 10 * time.Minute
+
+// ExportMonitorChecks streams the checks of a monitor as CSV, JSON or NDJSON,
+// oldest first
+func (s *Server) ExportMonitorChecks(w http.ResponseWriter, r *http.Request) {
+	monitorID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	format := export.FormatCSV
+	if v := query.Get("format"); v != "" {
+		if format, err = export.ParseFormat(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	from, err := parseTimeParam(query.Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from time, expected RFC 3339", http.StatusBadRequest)
+		return
+	}
+	to, err := parseTimeParam(query.Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid to time, expected RFC 3339", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := s.getMonitor(w, r, monitorID); !ok {
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Now().Add(exportTimeout)); err != nil {
+		slog.Warn("Failed to extend write deadline of export", "error", err)
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="monitor-%d-checks.%s"`, monitorID, format))
+
+	// The status is sent with the first checks, later errors can only end
+	// the response early
+	if _, err := export.Checks(r.Context(), s.cfg.Conn, w, format, monitorID, from, to); err != nil {
+		slog.Error("Failed to export checks", "monitor_id", monitorID, "error", err)
+	}
+}
+
+// getMonitor returns a monitor the request may see, or responds that it wasn't
+// found
+func (s *Server) getMonitor(w http.ResponseWriter, r *http.Request, id int64) (*db.Monitor, bool) {
+	monitor, err := s.cfg.Conn.Q.GetMonitor(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !s.canView(r, monitor.Public)) {
+		http.Error(w, "Monitor not found", http.StatusNotFound)
+		return nil, false
+	}
+	if err != nil {
+		http.Error(w, "Failed to get monitor", http.StatusInternalServerError)
+		return nil, false
+	}
+	return monitor, true
+}
+
+// parseTimeParam parses an optional RFC 3339 time. Checks are stored in UTC
+// and compared as text, so the time is converted to UTC.
+func parseTimeParam(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, err
+	}
+	t = t.UTC()
+	return &t, nil
+}
+
+// GetMonitorSLO returns the error budgets of a monitor's service level objective
+func (s *Server) GetMonitorSLO(w http.ResponseWriter, r *http.Request) {
+	monitorID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+		return
+	}
+
+	monitor, ok := s.getMonitor(w, r, monitorID)
+	if !ok {
+		return
+	}
+
+	status, err := s.cfg.SLO.Status(r.Context(), monitor.ID, monitor.Name)
+	if err != nil {
+		slog.Error("Failed to get error budgets", "monitor_id", monitor.ID, "error", err)
+		http.Error(w, "Failed to get error budgets", http.StatusInternalServerError)
+		return
+	}
+	if status == nil {
+		http.Error(w, "Monitor has no SLO", http.StatusNotFound)
+		return
+	}
+
+	util.RespondJSON(w, http.StatusOK, status)
 }
 
 func (s *Server) getDataPoints(
 	ctx context.Context,
 	seconds int64,
 	since time.Time,
+	monitorID *int64,
 ) (map[int64][]DataPoint, error) {
 	bucketSize := s.computeBucketSize(seconds)
 
 	rows, err := s.cfg.Conn.Q.GetDataPoints(ctx, &db.GetDataPointsParams{
-		BucketSize:        bucketSize,
-		DegradedThreshold: 500,
-		Since:             since,
+		BucketSize: bucketSize,
+		Since:      since,
+		MonitorID:  monitorID,
 	})
 	if err != nil {
 		return nil, err
@@ -142,13 +437,14 @@ func (s *Server) getDataPoints(
 		dp := DataPoint{
 			Timestamp:    time.Unix(row.BucketTs, 0),
 			ResponseTime: row.AvgResponseTime,
-			IsUp:         row.UpCount > total/2,
+			IsUp:         row.UpCount+row.MaintenanceCount > total/2,
 		}
 
 		if s.cfg.ChartType == "bars" {
 			dp.UpRatio = row.UpCount / total
 			dp.DegradedRatio = row.DegradedCount / total
 			dp.DownRatio = row.DownCount / total
+			dp.MaintenanceRatio = row.MaintenanceCount / total
 		}
 
 		result[row.MonitorID] = append(result[row.MonitorID], dp)
@@ -1,11 +1,18 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/notify"
 	"github.com/mizuchilabs/beacon/internal/util"
 )
 
@@ -19,6 +26,38 @@ type PushSubscriptionKeys struct {
 	Auth   string `json:"auth"`
 }
 
+// PushSubscriptionSet lists the monitors and groups a browser is subscribed
+// to. The group "*" covers all monitors, including ones added later.
+type PushSubscriptionSet struct {
+	Monitors []int64  `json:"monitors"`
+	Groups   []string `json:"groups"`
+}
+
+// PushSubscriptionStatus tells a browser whether it has to subscribe again
+// after the VAPID keys were rotated
+type PushSubscriptionStatus struct {
+	PushSubscriptionSet
+	Stale bool `json:"stale"`
+}
+
+type SubscribeRequest struct {
+	PushSubscriptionRequest
+	PushSubscriptionSet
+}
+
+type NotificationLog struct {
+	ID            int64      `json:"id"`
+	MonitorID     *int64     `json:"monitor_id,omitempty"`
+	Channel       string     `json:"channel"`
+	EventType     string     `json:"event_type"`
+	Status        string     `json:"status"`
+	Attempts      int64      `json:"attempts"`
+	LastError     *string    `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
 // GetVAPIDPublicKey returns the VAPID public key for client subscription
 func (s *Server) GetVAPIDPublicKey(w http.ResponseWriter, r *http.Request) {
 	keys, err := s.cfg.Conn.Q.GetVAPIDKeys(r.Context())
@@ -27,8 +66,9 @@ func (s *Server) GetVAPIDPublicKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	util.RespondJSON(w, http.StatusOK, map[string]string{
+	util.RespondJSON(w, http.StatusOK, map[string]any{
 		"publicKey": keys.PublicKey,
+		"createdAt": keys.CreatedAt,
 	})
 }
 
@@ -52,13 +92,24 @@ func (s *Server) SubscribeToPushNotifications(w http.ResponseWriter, r *http.Req
 		http.Error(w, "Missing required fields", http.StatusBadRequest)
 		return
 	}
+	if _, ok := s.getMonitor(w, r, monitorID); !ok {
+		return
+	}
 
-	// Store subscription
-	err = s.cfg.Conn.Q.CreatePushSubscription(r.Context(), &db.CreatePushSubscriptionParams{
-		MonitorID: monitorID,
-		Endpoint:  req.Endpoint,
-		P256dhKey: req.Keys.P256dh,
-		AuthKey:   req.Keys.Auth,
+	// Store subscription, replacing an existing one for the same monitor
+	err = s.withTx(r.Context(), func(q *db.Queries) error {
+		if err := q.DeletePushSubscription(r.Context(), &db.DeletePushSubscriptionParams{
+			Endpoint:  req.Endpoint,
+			MonitorID: &monitorID,
+		}); err != nil {
+			return err
+		}
+		return q.CreatePushSubscription(r.Context(), &db.CreatePushSubscriptionParams{
+			MonitorID: &monitorID,
+			Endpoint:  req.Endpoint,
+			P256dhKey: req.Keys.P256dh,
+			AuthKey:   req.Keys.Auth,
+		})
 	})
 	if err != nil {
 		http.Error(w, "Failed to subscribe", http.StatusInternalServerError)
@@ -94,7 +145,7 @@ func (s *Server) UnsubscribeFromPushNotifications(w http.ResponseWriter, r *http
 
 	err = s.cfg.Conn.Q.DeletePushSubscription(r.Context(), &db.DeletePushSubscriptionParams{
 		Endpoint:  req.Endpoint,
-		MonitorID: monitorID,
+		MonitorID: &monitorID,
 	})
 	if err != nil {
 		http.Error(w, "Failed to unsubscribe", http.StatusInternalServerError)
@@ -105,3 +156,215 @@ func (s *Server) UnsubscribeFromPushNotifications(w http.ResponseWriter, r *http
 		"message": "Unsubscribed successfully",
 	})
 }
+
+// SendTestNotification sends a test push notification of a monitor to a
+// browser subscribed to it and reports whether it was delivered
+func (s *Server) SendTestNotification(w http.ResponseWriter, r *http.Request) {
+	monitorID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Endpoint string `json:"endpoint"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Endpoint == "" {
+		http.Error(w, "Missing endpoint", http.StatusBadRequest)
+		return
+	}
+
+	monitor, ok := s.getMonitor(w, r, monitorID)
+	if !ok {
+		return
+	}
+
+	err = s.cfg.Notifier.SendTestPush(r.Context(), monitor, req.Endpoint)
+	switch {
+	case errors.Is(err, notify.ErrNoSubscription):
+		http.Error(w, "Subscription not found", http.StatusNotFound)
+		return
+	case errors.Is(err, notify.ErrStaleSubscription):
+		http.Error(w, "Subscription is outdated, please subscribe again", http.StatusConflict)
+		return
+	case errors.Is(err, notify.ErrPushRateLimited):
+		http.Error(w, "Too many notifications, please try again later", http.StatusTooManyRequests)
+		return
+	case err != nil:
+		slog.Warn("Failed to send test notification", "monitor_id", monitorID, "error", err)
+		http.Error(w, "Failed to deliver test notification", http.StatusBadGateway)
+		return
+	}
+
+	util.RespondJSON(w, http.StatusOK, map[string]string{
+		"message": "Test notification delivered",
+	})
+}
+
+// Subscribe replaces the set of monitors and groups a browser is subscribed
+// to. An empty set removes all of its subscriptions.
+func (s *Server) Subscribe(w http.ResponseWriter, r *http.Request) {
+	var req SubscribeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validate request
+	if req.Endpoint == "" || req.Keys.P256dh == "" || req.Keys.Auth == "" {
+		http.Error(w, "Missing required fields", http.StatusBadRequest)
+		return
+	}
+	if slices.Contains(req.Groups, "") {
+		http.Error(w, "Invalid group name", http.StatusBadRequest)
+		return
+	}
+	slices.Sort(req.Monitors)
+	req.Monitors = slices.Compact(append([]int64{}, req.Monitors...))
+	slices.Sort(req.Groups)
+	req.Groups = slices.Compact(append([]string{}, req.Groups...))
+
+	for _, id := range req.Monitors {
+		monitor, err := s.cfg.Conn.Q.GetMonitor(r.Context(), id)
+		if err != nil || !s.canView(r, monitor.Public) {
+			http.Error(w, fmt.Sprintf("Unknown monitor %d", id), http.StatusBadRequest)
+			return
+		}
+	}
+
+	err := s.withTx(r.Context(), func(q *db.Queries) error {
+		if err := q.DeletePushSubscriptionByEndpoint(r.Context(), req.Endpoint); err != nil {
+			return err
+		}
+		for _, id := range req.Monitors {
+			if err := q.CreatePushSubscription(r.Context(), &db.CreatePushSubscriptionParams{
+				MonitorID: &id,
+				Endpoint:  req.Endpoint,
+				P256dhKey: req.Keys.P256dh,
+				AuthKey:   req.Keys.Auth,
+			}); err != nil {
+				return err
+			}
+		}
+		for _, group := range req.Groups {
+			if err := q.CreatePushSubscription(r.Context(), &db.CreatePushSubscriptionParams{
+				GroupName: &group,
+				Endpoint:  req.Endpoint,
+				P256dhKey: req.Keys.P256dh,
+				AuthKey:   req.Keys.Auth,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, "Failed to subscribe", http.StatusInternalServerError)
+		return
+	}
+
+	util.RespondJSON(w, http.StatusOK, req.PushSubscriptionSet)
+}
+
+// GetSubscriptions returns the monitors and groups a browser is subscribed to
+func (s *Server) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	endpoint := r.URL.Query().Get("endpoint")
+	if endpoint == "" {
+		http.Error(w, "Missing endpoint", http.StatusBadRequest)
+		return
+	}
+
+	subscriptions, err := s.cfg.Conn.Q.GetPushSubscriptionsByEndpoint(r.Context(), endpoint)
+	if err != nil {
+		http.Error(w, "Failed to get subscriptions", http.StatusInternalServerError)
+		return
+	}
+
+	result := PushSubscriptionStatus{
+		PushSubscriptionSet: PushSubscriptionSet{Monitors: []int64{}, Groups: []string{}},
+	}
+	for _, sub := range subscriptions {
+		result.Stale = result.Stale || sub.Stale
+		if sub.MonitorID != nil {
+			result.Monitors = append(result.Monitors, *sub.MonitorID)
+		}
+		if sub.GroupName != nil {
+			result.Groups = append(result.Groups, *sub.GroupName)
+		}
+	}
+
+	util.RespondJSON(w, http.StatusOK, result)
+}
+
+// withTx runs fn with queries bound to a single transaction
+func (s *Server) withTx(ctx context.Context, fn func(q *db.Queries) error) error {
+	tx, err := s.cfg.Conn.Get().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(s.cfg.Conn.Q.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetNotifications returns the notification delivery log, newest first
+func (s *Server) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	params := &db.GetNotificationsParams{Limit: 50}
+
+	if v := query.Get("monitor_id"); v != "" {
+		monitorID, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+			return
+		}
+		params.MonitorID = &monitorID
+	}
+	if v := query.Get("status"); v != "" {
+		if v != "pending" && v != "sent" && v != "failed" {
+			http.Error(w, "Invalid status", http.StatusBadRequest)
+			return
+		}
+		params.Status = &v
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || limit < 1 || limit > 500 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		params.Limit = limit
+	}
+
+	notifications, err := s.cfg.Conn.Q.GetNotifications(r.Context(), params)
+	if err != nil {
+		http.Error(w, "Failed to get notifications", http.StatusInternalServerError)
+		return
+	}
+
+	// Push endpoints and payloads are left out on purpose
+	result := make([]NotificationLog, 0, len(notifications))
+	for _, n := range notifications {
+		result = append(result, NotificationLog{
+			ID:            n.ID,
+			MonitorID:     n.MonitorID,
+			Channel:       n.Channel,
+			EventType:     n.EventType,
+			Status:        n.Status,
+			Attempts:      n.Attempts,
+			LastError:     n.LastError,
+			NextAttemptAt: n.NextAttemptAt,
+			CreatedAt:     n.CreatedAt,
+			SentAt:        n.SentAt,
+		})
+	}
+
+	util.RespondJSON(w, http.StatusOK, result)
+}
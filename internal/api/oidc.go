@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/auth"
+	"github.com/mizuchilabs/beacon/internal/config"
+	"github.com/mizuchilabs/beacon/internal/oidc"
+	"github.com/mizuchilabs/beacon/internal/util"
+)
+
+// oidcCookie keeps the sign in request until the provider redirects back
+const oidcCookie = "beacon_oidc"
+
+// oidcTimeout is how long users have to sign in at the provider
+const oidcTimeout = 10 * time.Minute
+
+// oidcState is the signed content of the oidc cookie
+type oidcState struct {
+	oidc.Request
+	Redirect string    `json:"redirect"` // back to after signing in
+	Expires  time.Time `json:"expires"`
+}
+
+// OIDCLogin redirects to the OpenID Connect provider to sign in
+func (s *Server) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	if s.cfg.Auth != config.AuthOIDC {
+		http.Error(w, "OpenID Connect is disabled", http.StatusNotFound)
+		return
+	}
+
+	req, err := oidc.NewRequest()
+	if err != nil {
+		slog.Error("Failed to start sign in", "error", err)
+		http.Error(w, "Failed to sign in", http.StatusInternalServerError)
+		return
+	}
+	authURL, err := s.cfg.OIDC.AuthURL(r.Context(), req)
+	if err != nil {
+		slog.Error("Failed to start sign in", "error", err)
+		http.Error(w, "Identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	state, err := json.Marshal(oidcState{
+		Request:  req,
+		Redirect: safeRedirect(r.URL.Query().Get("redirect")),
+		Expires:  time.Now().Add(oidcTimeout),
+	})
+	if err != nil {
+		http.Error(w, "Failed to sign in", http.StatusInternalServerError)
+		return
+	}
+	payload := base64.RawURLEncoding.EncodeToString(state)
+	s.setOIDCCookie(w, r, payload+"."+util.Sign([]byte(s.cfg.Secret), oidcMessage(payload)), int(oidcTimeout/time.Second))
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// OIDCCallback signs the user in once the provider redirects back, with the
+// scope of the role mapped from their groups
+func (s *Server) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	if s.cfg.Auth != config.AuthOIDC {
+		http.Error(w, "OpenID Connect is disabled", http.StatusNotFound)
+		return
+	}
+	s.setOIDCCookie(w, r, "", -1)
+
+	state, ok := s.oidcState(r)
+	if !ok || r.URL.Query().Get("state") != state.State {
+		oidcFailed(w, r, "Sign in expired, please try again")
+		return
+	}
+	query := r.URL.Query()
+	if query.Get("error") != "" {
+		slog.Warn("Sign in declined by the identity provider",
+			"error", query.Get("error"),
+			"description", query.Get("error_description"),
+		)
+		oidcFailed(w, r, "Sign in declined by the identity provider")
+		return
+	}
+
+	claims, err := s.cfg.OIDC.Exchange(r.Context(), query.Get("code"), state.Request)
+	if err != nil {
+		slog.Error("Failed to sign in with OpenID Connect", "error", err)
+		oidcFailed(w, r, "Failed to sign in with the identity provider")
+		return
+	}
+
+	name := claims.String(s.cfg.OIDCNameClaim)
+	for _, claim := range []string{"email", "sub"} {
+		if name == "" {
+			name = claims.String(claim)
+		}
+	}
+	scope, ok := s.cfg.OIDCScope(claims.Strings(s.cfg.OIDCGroupsClaim))
+	if !ok {
+		slog.Warn("Denied sign in without a role", "username", name)
+		oidcFailed(w, r, "Your account has no access to this dashboard")
+		return
+	}
+
+	token, expires, err := auth.StartSession(r.Context(), s.cfg.Conn, auth.Session{
+		Name:  name,
+		Scope: scope,
+	}, s.cfg.SessionDuration)
+	if err != nil {
+		slog.Error("Failed to sign in", "error", err)
+		oidcFailed(w, r, "Failed to sign in")
+		return
+	}
+
+	s.setSessionCookie(w, r, token, expires)
+	slog.Info("User signed in", "username", name, "scope", scope)
+	http.Redirect(w, r, state.Redirect, http.StatusFound)
+}
+
+// oidcState returns the sign in request of the oidc cookie, if it's valid
+func (s *Server) oidcState(r *http.Request) (*oidcState, bool) {
+	cookie, err := r.Cookie(oidcCookie)
+	if err != nil {
+		return nil, false
+	}
+	payload, sig, ok := strings.Cut(cookie.Value, ".")
+	if !ok || !util.Verify([]byte(s.cfg.Secret), oidcMessage(payload), sig) {
+		return nil, false
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, false
+	}
+	var state oidcState
+	if err := json.Unmarshal(data, &state); err != nil || time.Now().After(state.Expires) {
+		return nil, false
+	}
+	return &state, true
+}
+
+// setOIDCCookie sets the oidc cookie, or clears it for a negative max age
+func (s *Server) setOIDCCookie(w http.ResponseWriter, r *http.Request, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookie,
+		Value:    value,
+		Path:     "/api/auth/oidc",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure: r.TLS != nil ||
+			r.Header.Get("X-Forwarded-Proto") == "https" ||
+			strings.HasPrefix(s.cfg.BaseURL, "https://"),
+		SameSite: http.SameSiteLaxMode, // sent along the redirect of the provider
+	})
+}
+
+// oidcFailed sends the user back to the sign in page with the error
+func oidcFailed(w http.ResponseWriter, r *http.Request, msg string) {
+	http.Redirect(w, r, "/login?"+url.Values{"error": {msg}}.Encode(), http.StatusFound)
+}
+
+func oidcMessage(payload string) string {
+	return "oidc:" + payload
+}
+
+// safeRedirect only allows paths of the dashboard, not other hosts
+func safeRedirect(path string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.Contains(path, `\`) {
+		return "/"
+	}
+	return path
+}
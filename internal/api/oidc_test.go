@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mizuchilabs/beacon/internal/config"
+	"github.com/mizuchilabs/beacon/internal/oidc"
+)
+
+// newOIDCServer returns a server signing in with a provider that only serves
+// its discovery document, enough to start sign ins
+func newOIDCServer(t *testing.T) *Server {
+	t.Helper()
+	var issuer *httptest.Server
+	issuer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer.URL,
+			"authorization_endpoint": issuer.URL + "/authorize",
+			"token_endpoint":         issuer.URL + "/token",
+			"jwks_uri":               issuer.URL + "/jwks",
+		})
+	}))
+	t.Cleanup(issuer.Close)
+
+	return NewServer(&config.Config{
+		EnvConfig: config.EnvConfig{
+			Auth:    config.AuthOIDC,
+			BaseURL: "https://status.example.com",
+			Secret:  "secret",
+		},
+		OIDC: oidc.New(oidc.Config{
+			Issuer:      issuer.URL,
+			ClientID:    "beacon",
+			RedirectURL: "https://status.example.com/api/auth/oidc/callback",
+			Scopes:      []string{"openid"},
+		}),
+	})
+}
+
+func TestOIDCCallbackState(t *testing.T) {
+	s := newOIDCServer(t)
+
+	rec := httptest.NewRecorder()
+	s.OIDCLogin(rec, httptest.NewRequest(http.MethodGet, "/api/auth/oidc/login?redirect=/monitors/1", nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("OIDCLogin() status = %d, want %d", rec.Code, http.StatusFound)
+	}
+	authURL, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := authURL.Query().Get("state")
+	var cookie *http.Cookie
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == oidcCookie {
+			cookie = c
+		}
+	}
+	if state == "" || cookie == nil {
+		t.Fatalf("OIDCLogin() set no state or cookie")
+	}
+	payload, sig, _ := strings.Cut(cookie.Value, ".")
+
+	tests := []struct {
+		name   string
+		cookie string
+		query  url.Values
+		want   string // error shown on the sign in page
+	}{
+		{
+			name:  "without cookie",
+			query: url.Values{"state": {state}, "code": {"code"}},
+			want:  "Sign in expired, please try again",
+		},
+		{
+			name:   "state mismatch",
+			cookie: cookie.Value,
+			query:  url.Values{"state": {"other"}, "code": {"code"}},
+			want:   "Sign in expired, please try again",
+		},
+		{
+			name:   "without state",
+			cookie: cookie.Value,
+			query:  url.Values{"code": {"code"}},
+			want:   "Sign in expired, please try again",
+		},
+		{
+			name:   "tampered cookie",
+			cookie: payload + "x." + sig,
+			query:  url.Values{"state": {state}, "code": {"code"}},
+			want:   "Sign in expired, please try again",
+		},
+		{
+			name:   "declined by the provider",
+			cookie: cookie.Value,
+			query:  url.Values{"state": {state}, "error": {"access_denied"}},
+			want:   "Sign in declined by the identity provider",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/auth/oidc/callback?"+tt.query.Encode(), nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: oidcCookie, Value: tt.cookie})
+			}
+			rec := httptest.NewRecorder()
+			s.OIDCCallback(rec, req)
+
+			want := "/login?" + url.Values{"error": {tt.want}}.Encode()
+			if got := rec.Header().Get("Location"); rec.Code != http.StatusFound || got != want {
+				t.Errorf("OIDCCallback() = %d %q, want %d %q", rec.Code, got, http.StatusFound, want)
+			}
+			for _, c := range rec.Result().Cookies() {
+				if c.Name == oidcCookie && c.MaxAge >= 0 {
+					t.Error("OIDCCallback() kept the sign in request")
+				}
+			}
+		})
+	}
+}
+
+func TestSafeRedirect(t *testing.T) {
+	tests := map[string]string{
+		"/monitors/1":          "/monitors/1",
+		"":                     "/",
+		"https://evil.example": "/",
+		"//evil.example":       "/",
+		`/\evil.example`:       "/",
+	}
+	for path, want := range tests {
+		if got := safeRedirect(path); got != want {
+			t.Errorf("safeRedirect(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/util"
+)
+
+// Outage is a run of consecutive failed checks of a monitor
+type Outage struct {
+	ID              int64      `json:"id"`
+	MonitorID       int64      `json:"monitor_id"`
+	MonitorName     string     `json:"monitor_name,omitempty"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"` // null while ongoing
+	DurationSeconds int64      `json:"duration_seconds"`
+	FirstError      string     `json:"first_error"`
+	StatusCodes     []int64    `json:"status_codes"`
+	Checks          int64      `json:"checks"`
+}
+
+// GetMonitorOutages returns the outages of a monitor ongoing or ended within
+// the last days, newest first
+func (s *Server) GetMonitorOutages(w http.ResponseWriter, r *http.Request) {
+	monitorID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+		return
+	}
+	since, limit, ok := outageParams(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := s.getMonitor(w, r, monitorID); !ok {
+		return
+	}
+
+	outages, err := s.cfg.Conn.Q.GetOutages(r.Context(), &db.GetOutagesParams{
+		MonitorID: monitorID,
+		Since:     &since,
+		Limit:     limit,
+	})
+	if err != nil {
+		slog.Error("Failed to get outages", "monitor_id", monitorID, "error", err)
+		http.Error(w, "Failed to get outages", http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]Outage, 0, len(outages))
+	for _, o := range outages {
+		result = append(result, newOutage(o, ""))
+	}
+	util.RespondJSON(w, http.StatusOK, result)
+}
+
+// GetOutages returns the outages of all monitors ongoing or ended within the
+// last days, newest first
+func (s *Server) GetOutages(w http.ResponseWriter, r *http.Request) {
+	since, limit, ok := outageParams(w, r)
+	if !ok {
+		return
+	}
+
+	// Filtered before the limit, so private outages don't crowd out the rest
+	outages, err := s.cfg.Conn.Q.GetRecentOutages(r.Context(), &db.GetRecentOutagesParams{
+		Since:   &since,
+		Private: s.canView(r, false),
+		Limit:   limit,
+	})
+	if err != nil {
+		slog.Error("Failed to get outages", "error", err)
+		http.Error(w, "Failed to get outages", http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]Outage, 0, len(outages))
+	for _, o := range outages {
+		result = append(result, newOutage(&db.Outage{
+			ID:          o.ID,
+			MonitorID:   o.MonitorID,
+			StartedAt:   o.StartedAt,
+			EndedAt:     o.EndedAt,
+			FirstError:  o.FirstError,
+			StatusCodes: o.StatusCodes,
+			Checks:      o.Checks,
+		}, o.MonitorName))
+	}
+	util.RespondJSON(w, http.StatusOK, result)
+}
+
+// outageParams parses the days to look back, 30 by default, and the limit of
+// outages, 100 by default
+func outageParams(w http.ResponseWriter, r *http.Request) (time.Time, int64, bool) {
+	query := r.URL.Query()
+	days, limit := int64(30), int64(100)
+	if v := query.Get("days"); v != "" {
+		var err error
+		if days, err = strconv.ParseInt(v, 10, 64); err != nil || days < 1 || days > 3650 {
+			http.Error(w, "Invalid days", http.StatusBadRequest)
+			return time.Time{}, 0, false
+		}
+	}
+	if v := query.Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.ParseInt(v, 10, 64); err != nil || limit < 1 || limit > 500 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return time.Time{}, 0, false
+		}
+	}
+	return time.Now().UTC().AddDate(0, 0, -int(days)), limit, true
+}
+
+func newOutage(o *db.Outage, monitorName string) Outage {
+	end := time.Now()
+	if o.EndedAt != nil {
+		end = *o.EndedAt
+	}
+	codes := []int64{}
+	for v := range strings.SplitSeq(o.StatusCodes, ",") {
+		if code, err := strconv.ParseInt(v, 10, 64); err == nil {
+			codes = append(codes, code)
+		}
+	}
+	return Outage{
+		ID:              o.ID,
+		MonitorID:       o.MonitorID,
+		MonitorName:     monitorName,
+		StartedAt:       o.StartedAt,
+		EndedAt:         o.EndedAt,
+		DurationSeconds: int64(end.Sub(o.StartedAt).Seconds()),
+		FirstError:      o.FirstError,
+		StatusCodes:     codes,
+		Checks:          o.Checks,
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/report"
+	"github.com/mizuchilabs/beacon/internal/util"
+)
+
+// GetReport returns the uptime report of a calendar period in the configured
+// timezone, as JSON or as a page for printing
+func (s *Server) GetReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	period := report.PeriodMonth
+	if v := query.Get("period"); v != "" {
+		var err error
+		if period, err = report.ParsePeriod(v); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	// Any date within the period, the current one by default
+	date := time.Now().In(s.cfg.Location)
+	if v := query.Get("date"); v != "" {
+		var err error
+		if date, err = time.ParseInLocation(time.DateOnly, v, s.cfg.Location); err != nil {
+			http.Error(w, "Invalid date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+	}
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "html" {
+		http.Error(w, "Invalid format, expected json or html", http.StatusBadRequest)
+		return
+	}
+
+	monitors, err := s.cfg.Conn.Q.GetMonitors(r.Context())
+	if err != nil {
+		slog.Error("Failed to get monitors", "error", err)
+		http.Error(w, "Failed to get monitors", http.StatusInternalServerError)
+		return
+	}
+	visible := make([]*db.Monitor, 0, len(monitors))
+	for _, m := range monitors {
+		if !s.canView(r, m.Public) {
+			continue
+		}
+		if v := query.Get("monitor"); v != "" && v != strconv.FormatInt(m.ID, 10) {
+			continue
+		}
+		visible = append(visible, m)
+	}
+
+	result, err := report.Generate(r.Context(), s.cfg.Conn, visible, period, date)
+	if err != nil {
+		slog.Error("Failed to generate report", "error", err)
+		http.Error(w, "Failed to generate report", http.StatusInternalServerError)
+		return
+	}
+
+	if format != "html" {
+		util.RespondJSON(w, http.StatusOK, result)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(
+		`inline; filename="uptime-report-%s-%s.html"`,
+		period,
+		result.From.Format(time.DateOnly),
+	))
+	if err := result.WriteHTML(w, s.cfg.Title); err != nil {
+		slog.Error("Failed to render report", "error", err)
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"net/http/pprof"
 	"time"
 
+	"github.com/mizuchilabs/beacon/internal/auth"
 	"github.com/mizuchilabs/beacon/internal/config"
+	"github.com/mizuchilabs/beacon/internal/metrics"
 	"github.com/mizuchilabs/beacon/web"
 	"github.com/vearutop/statigz"
 )
@@ -17,12 +19,20 @@ import (
 type Server struct {
 	mux *http.ServeMux
 	cfg *config.Config
+
+	// Closed on shutdown, ending event streams
+	shutdown chan struct{}
+
+	// Rejects unsafe requests of other origins signed in with session cookies
+	csrf *http.CrossOriginProtection
 }
 
 func NewServer(cfg *config.Config) *Server {
 	return &Server{
-		mux: http.NewServeMux(),
-		cfg: cfg,
+		mux:      http.NewServeMux(),
+		cfg:      cfg,
+		shutdown: make(chan struct{}),
+		csrf:     newCrossOriginProtection(),
 	}
 }
 
@@ -35,6 +45,7 @@ func (s *Server) Start(ctx context.Context) error {
 		WithRateLimit,
 		WithBodyLimit,
 		WithSecurityHeaders,
+		s.WithPrincipal,
 	)
 	server := &http.Server{
 		Addr:              ":" + s.cfg.ServerPort,
@@ -45,6 +56,7 @@ func (s *Server) Start(ctx context.Context) error {
 		IdleTimeout:       120 * time.Second,
 		MaxHeaderBytes:    8192, // 8KB
 	}
+	server.RegisterOnShutdown(func() { close(s.shutdown) })
 
 	serverErr := make(chan error, 1)
 	go func() {
@@ -69,18 +81,59 @@ func (s *Server) Start(ctx context.Context) error {
 
 func (s *Server) setupRoutes() {
 	s.mux.HandleFunc("GET /api/monitors", s.GetMonitors)
+	s.mux.HandleFunc("GET /api/monitors/{id}", s.GetMonitor)
+	s.mux.HandleFunc("GET /api/monitors/{id}/checks", s.GetMonitorChecks)
+	s.mux.HandleFunc("GET /api/monitors/{id}/export", s.ExportMonitorChecks)
+	s.mux.HandleFunc("GET /api/monitors/{id}/outages", s.GetMonitorOutages)
+	s.mux.HandleFunc("GET /api/outages", s.GetOutages)
+	s.mux.HandleFunc("GET /api/monitors/{id}/slo", s.GetMonitorSLO)
+	s.mux.HandleFunc("GET /api/badge/{id}/{badge}", s.GetBadge)
 	s.mux.HandleFunc("GET /api/config", s.GetConfig)
+	s.mux.HandleFunc("GET /api/events", s.StreamEvents)
+	s.mux.HandleFunc("GET /api/reports", s.WithAuth(auth.ScopeRead, s.GetReport))
 	s.mux.HandleFunc("GET /api/incidents", s.GetIncidents)
 	s.mux.HandleFunc("GET /api/incidents/{id}", s.GetIncident)
 
+	// Dashboard users
+	s.mux.HandleFunc("GET /api/auth/session", s.GetSession)
+	s.mux.HandleFunc("POST /api/auth/login", s.Login)
+	s.mux.HandleFunc("POST /api/auth/logout", s.Logout)
+	s.mux.HandleFunc("GET /api/auth/oidc/login", s.OIDCLogin)
+	s.mux.HandleFunc("GET /api/auth/oidc/callback", s.OIDCCallback)
+
 	// Push notifications
 	s.mux.HandleFunc("POST /api/monitor/{id}/subscribe", s.SubscribeToPushNotifications)
 	s.mux.HandleFunc("POST /api/monitor/{id}/unsubscribe", s.UnsubscribeFromPushNotifications)
+	s.mux.HandleFunc("POST /api/monitor/{id}/test-notification", s.SendTestNotification)
+	s.mux.HandleFunc("POST /api/subscribe", s.Subscribe)
+	s.mux.HandleFunc("GET /api/subscriptions", s.GetSubscriptions)
 	s.mux.HandleFunc("GET /api/vapid-public-key", s.GetVAPIDPublicKey)
+	s.mux.HandleFunc("POST /api/email/subscribe", s.SubscribeEmail)
+	s.mux.HandleFunc("GET /api/email/confirm", s.GetEmailConfirm)
+	s.mux.HandleFunc("POST /api/email/confirm", s.ConfirmEmail)
+	s.mux.HandleFunc("GET /api/email/unsubscribe", s.GetEmailUnsubscribe)
+	s.mux.HandleFunc("POST /api/email/unsubscribe", s.UnsubscribeEmail)
+	s.mux.HandleFunc("GET /api/notifications", s.WithAuth(auth.ScopeRead, s.GetNotifications))
+
+	// Alerts
+	s.mux.HandleFunc("GET /api/alerts/{id}/ack", s.GetAlertAck)
+	s.mux.HandleFunc("POST /api/alerts/{id}/ack", s.AcknowledgeAlert)
+	s.mux.HandleFunc("POST /api/monitor/{id}/ack", s.WithAuth(auth.ScopeWrite, s.AcknowledgeMonitor))
+	s.mux.HandleFunc("POST /api/monitor/{id}/snooze", s.WithAuth(auth.ScopeWrite, s.SnoozeMonitor))
+
+	// Admin
+	s.mux.HandleFunc("GET /api/admin/monitors", s.WithAuth(auth.ScopeRead, s.AdminGetMonitors))
+	s.mux.HandleFunc("POST /api/admin/monitors", s.WithAuth(auth.ScopeWrite, s.AdminCreateMonitor))
+	s.mux.HandleFunc("PUT /api/admin/monitors/{id}", s.WithAuth(auth.ScopeWrite, s.AdminUpdateMonitor))
+	s.mux.HandleFunc("DELETE /api/admin/monitors/{id}", s.WithAuth(auth.ScopeWrite, s.AdminDeleteMonitor))
+	s.mux.HandleFunc("GET /api/admin/tokens", s.WithAuth(auth.ScopeAdmin, s.AdminGetTokens))
+	s.mux.HandleFunc("POST /api/admin/tokens", s.WithAuth(auth.ScopeAdmin, s.AdminCreateToken))
+	s.mux.HandleFunc("DELETE /api/admin/tokens/{id}", s.WithAuth(auth.ScopeAdmin, s.AdminDeleteToken))
 
 	s.mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
+	s.mux.HandleFunc("GET /metrics", s.WithAuth(auth.ScopeRead, metrics.Handler().ServeHTTP))
 
 	// Static files
 	s.mux.Handle("/", statigz.FileServer(web.StaticFS, statigz.FSPrefix("build")))
@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+// Session is a signed in user of the dashboard
+type Session struct {
+	UserID *int64 // of local users
+	Name   string
+	Scope  Scope
+}
+
+// StartSession stores a session and returns its token and when it expires.
+// Sessions are stored like API tokens, only by their hash.
+func StartSession(
+	ctx context.Context,
+	conn *db.Connection,
+	session Session,
+	duration time.Duration,
+) (string, time.Time, error) {
+	token, tokenHash, err := NewToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := time.Now().UTC()
+	if err := conn.Q.DeleteExpiredSessions(ctx, now); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	expires := now.Add(duration)
+	if err := conn.Q.CreateSession(ctx, &db.CreateSessionParams{
+		UserID:    session.UserID,
+		Username:  session.Name,
+		Scope:     string(session.Scope),
+		TokenHash: tokenHash,
+		ExpiresAt: expires,
+	}); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to store session: %w", err)
+	}
+	return token, expires, nil
+}
+
+// GetSession returns the session of the token, if it hasn't expired
+func GetSession(ctx context.Context, conn *db.Connection, token string) (*Session, error) {
+	stored, err := conn.Q.GetSession(ctx, HashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidCredentials
+	}
+	return &Session{
+		UserID: stored.UserID,
+		Name:   stored.Username,
+		Scope:  Scope(stored.Scope),
+	}, nil
+}
+
+// EndSession deletes the session of the token
+func EndSession(ctx context.Context, conn *db.Connection, token string) error {
+	return conn.Q.DeleteSession(ctx, HashToken(token))
+}
+
+// roles of users signed in with an OpenID Connect provider, by the scope they
+// grant
+var roles = map[string]Scope{
+	"viewer": ScopeRead,
+	"editor": ScopeWrite,
+	"admin":  ScopeAdmin,
+}
+
+// ParseRole returns the scope granted by a role
+func ParseRole(role string) (Scope, error) {
+	scope, ok := roles[role]
+	if !ok {
+		return "", fmt.Errorf("invalid role %q, expected viewer, editor or admin", role)
+	}
+	return scope, nil
+}
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Failed sign ins of a username are free up to loginFreeAttempts, then each
+// one doubles the wait before the next attempt, up to loginMaxBackoff.
+// Failures are forgotten once the username saw none for loginMaxBackoff.
+const (
+	loginFreeAttempts = 3
+	loginBaseBackoff  = time.Second
+	loginMaxBackoff   = 15 * time.Minute
+)
+
+// loginThrottle slows down password guessing per username, regardless of the
+// address the attempts come from
+type loginThrottle struct {
+	mu       sync.Mutex
+	failures map[string]*loginFailures
+}
+
+type loginFailures struct {
+	count int
+	last  time.Time
+	until time.Time // no attempts before
+}
+
+var logins = &loginThrottle{failures: make(map[string]*loginFailures)}
+
+// wait returns how long sign ins of a username are blocked at now
+func (t *loginThrottle) wait(username string, now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f, ok := t.failures[strings.ToLower(username)]
+	if !ok || !f.until.After(now) {
+		return 0
+	}
+	return f.until.Sub(now)
+}
+
+// fail records a failed sign in of a username at now
+func (t *loginThrottle) fail(username string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for name, f := range t.failures {
+		if now.Sub(f.last) > loginMaxBackoff {
+			delete(t.failures, name)
+		}
+	}
+
+	key := strings.ToLower(username)
+	f, ok := t.failures[key]
+	if !ok {
+		f = &loginFailures{}
+		t.failures[key] = f
+	}
+	f.count++
+	f.last = now
+	if f.count > loginFreeAttempts {
+		shift := min(f.count-loginFreeAttempts-1, 20)
+		f.until = now.Add(min(loginBaseBackoff<<shift, loginMaxBackoff))
+	}
+}
+
+// reset forgets the failed sign ins of a username
+func (t *loginThrottle) reset(username string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.failures, strings.ToLower(username))
+}
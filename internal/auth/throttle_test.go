@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginThrottle(t *testing.T) {
+	start := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	throttle := &loginThrottle{failures: make(map[string]*loginFailures)}
+
+	// Free attempts
+	for range loginFreeAttempts {
+		throttle.fail("alice", start)
+	}
+	if wait := throttle.wait("alice", start); wait != 0 {
+		t.Fatalf("wait after %d failures = %v, want 0", loginFreeAttempts, wait)
+	}
+
+	// Doubling backoff, per username regardless of case
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		throttle.fail("Alice", start)
+		if wait := throttle.wait("alice", start); wait != want {
+			t.Errorf("wait after %d failures = %v, want %v", loginFreeAttempts+i+1, wait, want)
+		}
+	}
+	if wait := throttle.wait("bob", start); wait != 0 {
+		t.Errorf("wait of other user = %v, want 0", wait)
+	}
+	if wait := throttle.wait("alice", start.Add(4*time.Second)); wait != 0 {
+		t.Errorf("wait after backoff = %v, want 0", wait)
+	}
+
+	// Capped
+	for range 30 {
+		throttle.fail("alice", start)
+	}
+	if wait := throttle.wait("alice", start); wait != loginMaxBackoff {
+		t.Errorf("wait after many failures = %v, want %v", wait, loginMaxBackoff)
+	}
+
+	// Forgotten after a successful sign in
+	throttle.reset("ALICE")
+	if wait := throttle.wait("alice", start); wait != 0 {
+		t.Errorf("wait after reset = %v, want 0", wait)
+	}
+
+	// and after a quiet period
+	for range loginFreeAttempts + 1 {
+		throttle.fail("carol", start)
+	}
+	throttle.fail("dave", start.Add(loginMaxBackoff+time.Minute))
+	if _, ok := throttle.failures["carol"]; ok {
+		t.Error("failures of carol were kept after a quiet period")
+	}
+}
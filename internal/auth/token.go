@@ -0,0 +1,98 @@
+// Package auth provides the scoped tokens of API clients and the users of
+// the dashboard
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+// Scope is what a token grants access to, each scope including the previous
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"  // list monitors of the admin API
+	ScopeWrite Scope = "write" // manage monitors and alerts
+	ScopeAdmin Scope = "admin" // manage API tokens
+)
+
+var scopes = []Scope{ScopeRead, ScopeWrite, ScopeAdmin}
+
+// ParseScope validates a scope
+func ParseScope(s string) (Scope, error) {
+	if !slices.Contains(scopes, Scope(s)) {
+		return "", fmt.Errorf("invalid scope %q, expected read, write or admin", s)
+	}
+	return Scope(s), nil
+}
+
+// Includes reports whether the scope grants the required one
+func (s Scope) Includes(required Scope) bool {
+	return slices.Index(scopes, s) >= slices.Index(scopes, required)
+}
+
+// tokenPrefix makes tokens easy to recognize, e.g. by secret scanners
+const tokenPrefix = "beacon_"
+
+// NewToken returns a random token and its hash, only the hash is stored
+func NewToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	token = tokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hex encoded SHA-256 hash of a token. Tokens are
+// random, so they don't need a salt or a slow hash.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ErrTokenExists is returned when the name of a token is taken
+var ErrTokenExists = errors.New("a token with this name already exists")
+
+// CreateToken stores a new token and returns it, it can't be shown again
+func CreateToken(
+	ctx context.Context,
+	conn *db.Connection,
+	name string,
+	scope Scope,
+) (string, *db.ApiToken, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", nil, fmt.Errorf("name is required")
+	}
+
+	tokens, err := conn.Q.GetAPITokens(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get tokens: %w", err)
+	}
+	if slices.ContainsFunc(tokens, func(t *db.ApiToken) bool { return t.Name == name }) {
+		return "", nil, ErrTokenExists
+	}
+
+	token, hash, err := NewToken()
+	if err != nil {
+		return "", nil, err
+	}
+	stored, err := conn.Q.CreateAPIToken(ctx, &db.CreateAPITokenParams{
+		Name:      name,
+		TokenHash: hash,
+		Scope:     string(scope),
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to store token: %w", err)
+	}
+	return token, stored, nil
+}
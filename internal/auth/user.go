@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+var (
+	ErrUserExists         = errors.New("a user with this name already exists")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrTooManyAttempts    = errors.New("too many failed sign ins, try again later")
+)
+
+// CreateUser stores a dashboard user with a hashed password and a role,
+// which grants the scope of their sessions
+func CreateUser(ctx context.Context, conn *db.Connection, username, password, role string) (*db.User, error) {
+	if strings.TrimSpace(username) == "" {
+		return nil, fmt.Errorf("username is required")
+	}
+	if _, err := ParseRole(role); err != nil {
+		return nil, err
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = conn.Q.GetUserByUsername(ctx, username)
+	if err == nil {
+		return nil, ErrUserExists
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user, err := conn.Q.CreateUser(ctx, &db.CreateUserParams{
+		Username:     username,
+		PasswordHash: hash,
+		Role:         role,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store user: %w", err)
+	}
+	return user, nil
+}
+
+// Login checks the password of a user and starts a session with the scope
+// of their role, returning the session, its token and when it expires.
+// Repeated failures of a username make it wait increasingly long before the
+// next attempt.
+func Login(
+	ctx context.Context,
+	conn *db.Connection,
+	username, password string,
+	duration time.Duration,
+) (*Session, string, time.Time, error) {
+	if logins.wait(username, time.Now()) > 0 {
+		return nil, "", time.Time{}, ErrTooManyAttempts
+	}
+
+	user, err := conn.Q.GetUserByUsername(ctx, username)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, "", time.Time{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	var hash string
+	if err == nil {
+		hash = user.PasswordHash
+	}
+	if !CheckPassword(hash, password) {
+		logins.fail(username, time.Now())
+		return nil, "", time.Time{}, ErrInvalidCredentials
+	}
+	logins.reset(username)
+
+	scope, err := ParseRole(user.Role)
+	if err != nil {
+		return nil, "", time.Time{}, fmt.Errorf("user %q: %w", user.Username, err)
+	}
+	if err := conn.Q.TouchUserLogin(ctx, user.ID); err != nil {
+		return nil, "", time.Time{}, fmt.Errorf("failed to update user: %w", err)
+	}
+	session := &Session{UserID: &user.ID, Name: user.Username, Scope: scope}
+	token, expires, err := StartSession(ctx, conn, *session, duration)
+	if err != nil {
+		return nil, "", time.Time{}, err
+	}
+	return session, token, expires, nil
+}
+
+// SetPassword replaces the password of a user and ends their sessions
+func SetPassword(ctx context.Context, conn *db.Connection, userID int64, password string) error {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := conn.Q.UpdateUserPassword(ctx, &db.UpdateUserPasswordParams{
+		PasswordHash: hash,
+		ID:           userID,
+	}); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return conn.Q.DeleteUserSessions(ctx, userID)
+}
+
+// SetRole replaces the role of a user and ends their sessions, which keep the
+// scope they were started with
+func SetRole(ctx context.Context, conn *db.Connection, userID int64, role string) error {
+	if _, err := ParseRole(role); err != nil {
+		return err
+	}
+	if err := conn.Q.UpdateUserRole(ctx, &db.UpdateUserRoleParams{
+		Role: role,
+		ID:   userID,
+	}); err != nil {
+		return fmt.Errorf("failed to update role: %w", err)
+	}
+	return conn.Q.DeleteUserSessions(ctx, userID)
+}
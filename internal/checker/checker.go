@@ -57,11 +57,16 @@ func (c *Checker) Check(ctx context.Context, url string) *db.CreateCheckParams {
 	}()
 
 	code := int64(resp.StatusCode)
-	return &db.CreateCheckParams{
+	result := &db.CreateCheckParams{
 		IsUp:         resp.StatusCode >= 200 && resp.StatusCode < 400,
 		StatusCode:   code,
 		ResponseTime: ms,
 	}
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		expiresAt := resp.TLS.PeerCertificates[0].NotAfter
+		result.CertExpiresAt = &expiresAt
+	}
+	return result
 }
 
 func checkErr(err error, responseTime int64) *db.CreateCheckParams {
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mizuchilabs/beacon/internal/auth"
+	"github.com/mizuchilabs/beacon/internal/oidc"
+)
+
+// Modes of dashboard authentication
+const (
+	AuthNone  = ""
+	AuthLocal = "local" // users created with the CLI, signed in with sessions
+	AuthProxy = "proxy" // users named by a header of a trusted reverse proxy
+	AuthOIDC  = "oidc"  // users of an OpenID Connect provider, signed in with sessions
+)
+
+// oidcCallback is the path the OpenID Connect provider redirects back to
+const oidcCallback = "/api/auth/oidc/callback"
+
+// setupAuth validates the auth mode and prepares its settings
+func (cfg *Config) setupAuth() (err error) {
+	switch cfg.Auth {
+	case AuthNone, AuthLocal:
+	case AuthProxy:
+		cfg.Proxies, err = cfg.trustedProxies()
+	case AuthOIDC:
+		err = cfg.setupOIDC()
+	default:
+		err = fmt.Errorf("unknown auth mode %q, expected local, proxy or oidc", cfg.Auth)
+	}
+	return err
+}
+
+// trustedProxies returns the networks of the proxies whose headers are trusted
+func (cfg *Config) trustedProxies() ([]netip.Prefix, error) {
+	if strings.TrimSpace(cfg.AuthHeader) == "" {
+		return nil, fmt.Errorf("proxy auth requires BEACON_AUTH_HEADER")
+	}
+
+	proxies := make([]netip.Prefix, 0, len(cfg.TrustedProxies))
+	for _, v := range cfg.TrustedProxies {
+		v = strings.TrimSpace(v)
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			// Single addresses are networks of one
+			addr, addrErr := netip.ParseAddr(v)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", v, err)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+// setupOIDC creates the OpenID Connect provider and maps its groups to scopes
+func (cfg *Config) setupOIDC() error {
+	if cfg.OIDCIssuer == "" || cfg.OIDCClientID == "" {
+		return fmt.Errorf("oidc auth requires BEACON_OIDC_ISSUER and BEACON_OIDC_CLIENT_ID")
+	}
+	base, err := url.Parse(cfg.BaseURL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return fmt.Errorf("oidc auth requires BEACON_URL for the redirect URL")
+	}
+
+	cfg.OIDCScopes = make(map[string]auth.Scope, len(cfg.OIDCRoles))
+	for group, role := range cfg.OIDCRoles {
+		scope, err := auth.ParseRole(strings.TrimSpace(role))
+		if err != nil {
+			return fmt.Errorf("group %q: %w", group, err)
+		}
+		cfg.OIDCScopes[strings.TrimSpace(group)] = scope
+	}
+	if cfg.OIDCDefaultRole != "none" {
+		if _, err := auth.ParseRole(cfg.OIDCDefaultRole); err != nil {
+			return fmt.Errorf("default role: %w", err)
+		}
+	}
+
+	scopes := cfg.OIDCRequestScopes
+	if !slices.Contains(scopes, "openid") {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+	cfg.OIDC = oidc.New(oidc.Config{
+		Issuer:       cfg.OIDCIssuer,
+		ClientID:     cfg.OIDCClientID,
+		ClientSecret: cfg.OIDCClientSecret,
+		RedirectURL:  strings.TrimSuffix(cfg.BaseURL, "/") + oidcCallback,
+		Scopes:       scopes,
+	})
+	return nil
+}
+
+// OIDCScope returns the highest scope granted by the groups of a user of the
+// OpenID Connect provider, or the default role's if none is mapped
+func (cfg *Config) OIDCScope(groups []string) (auth.Scope, bool) {
+	var granted auth.Scope
+	for _, group := range groups {
+		scope, ok := cfg.OIDCScopes[group]
+		if ok && (granted == "" || scope.Includes(granted)) {
+			granted = scope
+		}
+	}
+	if granted != "" {
+		return granted, true
+	}
+	if cfg.OIDCDefaultRole == "none" {
+		return "", false
+	}
+	scope, err := auth.ParseRole(cfg.OIDCDefaultRole)
+	return scope, err == nil
+}
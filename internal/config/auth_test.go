@@ -0,0 +1,86 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/mizuchilabs/beacon/internal/auth"
+)
+
+func TestOIDCScope(t *testing.T) {
+	roles := map[string]string{"sre": "admin", " oncall ": "editor", "staff": "viewer"}
+
+	tests := []struct {
+		name        string
+		defaultRole string
+		groups      []string
+		want        auth.Scope
+		wantOK      bool
+	}{
+		{"mapped group", "viewer", []string{"oncall"}, auth.ScopeWrite, true},
+		{"highest of several", "viewer", []string{"staff", "sre", "oncall"}, auth.ScopeAdmin, true},
+		{"unmapped groups ignored", "viewer", []string{"guests", "staff"}, auth.ScopeRead, true},
+		{"default role", "editor", []string{"guests"}, auth.ScopeWrite, true},
+		{"default role without groups", "viewer", nil, auth.ScopeRead, true},
+		{"mapped group with default none", "none", []string{"staff"}, auth.ScopeRead, true},
+		{"denied with default none", "none", []string{"guests"}, "", false},
+		{"group names are exact", "none", []string{"SRE"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{EnvConfig: EnvConfig{
+				Auth:            AuthOIDC,
+				BaseURL:         "https://status.example.com",
+				OIDCIssuer:      "https://auth.example.com",
+				OIDCClientID:    "beacon",
+				OIDCRoles:       roles,
+				OIDCDefaultRole: tt.defaultRole,
+			}}
+			if err := cfg.setupAuth(); err != nil {
+				t.Fatalf("setupAuth() error = %v", err)
+			}
+			got, ok := cfg.OIDCScope(tt.groups)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("OIDCScope(%v) = %q, %v, want %q, %v", tt.groups, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSetupOIDC(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     EnvConfig
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			cfg:  EnvConfig{BaseURL: "https://status.example.com", OIDCDefaultRole: "none"},
+		},
+		{
+			name:    "unknown role",
+			cfg:     EnvConfig{BaseURL: "https://status.example.com", OIDCRoles: map[string]string{"sre": "root"}, OIDCDefaultRole: "viewer"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown default role",
+			cfg:     EnvConfig{BaseURL: "https://status.example.com", OIDCDefaultRole: "guest"},
+			wantErr: true,
+		},
+		{
+			name:    "without base URL",
+			cfg:     EnvConfig{OIDCDefaultRole: "viewer"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{EnvConfig: tt.cfg}
+			cfg.Auth = AuthOIDC
+			cfg.OIDCIssuer = "https://auth.example.com"
+			cfg.OIDCClientID = "beacon"
+			if err := cfg.setupAuth(); (err != nil) != tt.wantErr {
+				t.Errorf("setupAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,15 +3,26 @@ package config
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"log"
+	"log/slog"
+	"net/netip"
+	"slices"
 	"time"
+	_ "time/tzdata" // timezones for maintenance windows in minimal images
 
 	"github.com/caarlos0/env/v11"
+	"github.com/mizuchilabs/beacon/internal/auth"
 	"github.com/mizuchilabs/beacon/internal/checker"
 	"github.com/mizuchilabs/beacon/internal/db"
 	"github.com/mizuchilabs/beacon/internal/incidents"
+	"github.com/mizuchilabs/beacon/internal/maintenance"
 	"github.com/mizuchilabs/beacon/internal/notify"
+	"github.com/mizuchilabs/beacon/internal/oidc"
+	"github.com/mizuchilabs/beacon/internal/pubsub"
 	"github.com/mizuchilabs/beacon/internal/scheduler"
+	"github.com/mizuchilabs/beacon/internal/slo"
 	"github.com/urfave/cli/v3"
 )
 
@@ -22,6 +33,31 @@ type EnvConfig struct {
 	Insecure     bool   `env:"BEACON_INSECURE" envDefault:"false"`
 	ConfigPath   string `env:"BEACON_CONFIG"   envDefault:"config.yaml"`
 	MonitorsYAML string `env:"BEACON_MONITORS"`
+	BaseURL      string `env:"BEACON_URL"`    // public URL, used for links in notifications
+	Secret       string `env:"BEACON_SECRET"` // key for signing links, generated if empty
+
+	// API tokens as comma separated name:token pairs
+	APITokens map[string]string `env:"BEACON_API_TOKENS" envKeyValSeparator:":"`
+
+	// Dashboard authentication with "local" users, headers of a "proxy" or an
+	// "oidc" provider, disabled if empty. Private monitors are only shown to
+	// signed in users.
+	Auth            string        `env:"BEACON_AUTH"`
+	AuthHeader      string        `env:"BEACON_AUTH_HEADER"      envDefault:"X-Forwarded-User"`
+	AuthHeaderRole  string        `env:"BEACON_AUTH_HEADER_ROLE"` // viewer, editor or admin, viewer if unset
+	TrustedProxies  []string      `env:"BEACON_TRUSTED_PROXIES"  envDefault:"127.0.0.1/8,::1/128"`
+	SessionDuration time.Duration `env:"BEACON_SESSION_DURATION" envDefault:"168h"`
+
+	// OpenID Connect settings, redirecting back to BEACON_URL/api/auth/oidc/callback.
+	// Roles are comma separated group:role pairs of viewer, editor or admin.
+	OIDCIssuer        string            `env:"BEACON_OIDC_ISSUER"`
+	OIDCClientID      string            `env:"BEACON_OIDC_CLIENT_ID"`
+	OIDCClientSecret  string            `env:"BEACON_OIDC_CLIENT_SECRET"` // empty for public clients
+	OIDCRequestScopes []string          `env:"BEACON_OIDC_SCOPES"         envDefault:"openid,profile,email"`
+	OIDCNameClaim     string            `env:"BEACON_OIDC_NAME_CLAIM"     envDefault:"preferred_username"`
+	OIDCGroupsClaim   string            `env:"BEACON_OIDC_GROUPS_CLAIM"   envDefault:"groups"`
+	OIDCRoles         map[string]string `env:"BEACON_OIDC_ROLES"          envKeyValSeparator:":"`
+	OIDCDefaultRole   string            `env:"BEACON_OIDC_DEFAULT_ROLE"   envDefault:"viewer"` // of users without a mapped group, "none" denies them
 
 	// Frontend settings
 	Title       string `env:"BEACON_TITLE"       envDefault:"Beacon Dashboard"`
@@ -29,10 +65,19 @@ type EnvConfig struct {
 	Timezone    string `env:"BEACON_TIMEZONE"    envDefault:"Europe/Vienna"`
 	ChartType   string `env:"BEACON_CHART_TYPE"  envDefault:"area"` // bars or area
 
+	// Notification settings
+	Language string `env:"BEACON_LANGUAGE" envDefault:"en"` // of built-in notification messages
+
 	// Monitor settings
 	Timeout       time.Duration `env:"BEACON_TIMEOUT"        envDefault:"30s"`
 	RetentionDays int           `env:"BEACON_RETENTION_DAYS" envDefault:"30"`
 
+	// Web push settings, keys are generated on first start unless imported
+	VAPIDSubject    string `env:"BEACON_VAPID_SUBJECT"     envDefault:"mailto:beacon@mizuchi.dev"`
+	VAPIDPublicKey  string `env:"BEACON_VAPID_PUBLIC_KEY"`
+	VAPIDPrivateKey string `env:"BEACON_VAPID_PRIVATE_KEY"`
+	VAPIDKeyFile    string `env:"BEACON_VAPID_KEY_FILE"`
+
 	// Incident settings
 	RepoURL  string        `env:"BEACON_INCIDENT_REPO"`
 	RepoPath string        `env:"BEACON_INCIDENT_PATH"`
@@ -43,11 +88,22 @@ type Config struct {
 	EnvConfig
 
 	// Application settings
-	Conn      *db.Connection
-	Checker   *checker.Checker
-	Scheduler *scheduler.Scheduler
-	Notifier  *notify.Notifier
-	Incidents *incidents.IncidentManager
+	Conn        *db.Connection
+	Checker     *checker.Checker
+	Scheduler   *scheduler.Scheduler
+	Notifier    *notify.Notifier
+	Incidents   *incidents.IncidentManager
+	Maintenance *maintenance.Calendar
+	Events      *pubsub.Broker
+	SLO         *slo.Tracker
+	Location    *time.Location // of BEACON_TIMEZONE
+
+	// Networks whose auth headers are trusted
+	Proxies []netip.Prefix
+
+	// OpenID Connect provider and the scopes granted to its groups
+	OIDC       *oidc.Provider
+	OIDCScopes map[string]auth.Scope
 }
 
 // New loads configuration from environment variables
@@ -68,20 +124,115 @@ func New(ctx context.Context, cmd *cli.Command) *Config {
 		log.Fatalf("Invalid chart type: %s", cfg.ChartType)
 	}
 
+	if err := cfg.setupAuth(); err != nil {
+		log.Fatalf("Invalid auth settings: %v", err)
+	}
+
+	cfg.Location = cfg.location()
+	file, err := cfg.loadConfigFile(cfg.Location)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
 	cfg.Conn = db.NewConnection(ctx, cfg.DBPath)
+	if cfg.Secret == "" {
+		cfg.Secret, err = loadSecret(ctx, cfg.Conn)
+		if err != nil {
+			log.Fatalf("Failed to load secret: %v", err)
+		}
+	}
+
+	cfg.Language = cfg.language()
+
+	vapid, err := cfg.vapidOptions()
+	if err != nil {
+		log.Fatalf("Failed to load VAPID keys: %v", err)
+	}
+
 	cfg.Checker = checker.New(cfg.Timeout, cfg.Insecure)
-	cfg.Notifier = notify.New(ctx, cfg.Conn)
+	cfg.Incidents = incidents.New(cfg.RepoURL, cfg.RepoPath, cfg.Interval)
+	cfg.Maintenance = maintenance.New(file.Maintenance, cfg.Location, cfg.Incidents)
+	cfg.Notifier = notify.New(ctx, cfg.Conn, notify.Options{
+		Channels:    file.Channels,
+		Policies:    file.EscalationPolicies,
+		Escalations: file.escalations(),
+		BaseURL:     cfg.BaseURL,
+		Secret:      []byte(cfg.Secret),
+		Location:    cfg.Location,
+		VAPID:       vapid,
+		Language:    cfg.Language,
+		Maintenance: cfg.Maintenance,
+
+		BudgetAlerts: file.budgetAlerts(),
+	})
 
 	// Start background jobs
-	cfg.Scheduler = scheduler.New(cfg.Conn, cfg.Checker, cfg.Notifier, cfg.RetentionDays)
-	cfg.Scheduler.Start(ctx)
-	cfg.Incidents = incidents.New(cfg.RepoURL, cfg.RepoPath, cfg.Interval)
+	cfg.Notifier.Start(ctx)
+	cfg.Incidents.OnUpdate(cfg.Notifier.SendIncidentNotification)
 	cfg.Incidents.Start(ctx)
 
-	// Sync monitors to DB
-	if err := cfg.syncMonitors(ctx); err != nil {
+	// Sync monitors to DB before the scheduler loads them
+	if err := cfg.syncMonitors(ctx, file.Monitors); err != nil {
 		log.Fatalf("Failed to sync monitors to DB: %v", err)
 	}
 
+	cfg.Events = pubsub.New()
+	cfg.Scheduler = scheduler.New(
+		cfg.Conn,
+		cfg.Checker,
+		cfg.Notifier,
+		cfg.Maintenance,
+		cfg.Events,
+		cfg.RetentionDays,
+	)
+	cfg.Scheduler.Start(ctx)
+
+	objectives := file.objectives()
+	for name, o := range objectives {
+		if o.WindowDays > cfg.RetentionDays {
+			slog.Warn("SLO window is longer than checks are kept",
+				"monitor", name,
+				"window_days", o.WindowDays,
+				"retention_days", cfg.RetentionDays,
+			)
+		}
+	}
+	cfg.SLO = slo.New(cfg.Conn, cfg.Notifier, cfg.Maintenance, objectives)
+	cfg.SLO.Start(ctx)
+
 	return &cfg
 }
+
+// language returns the configured language of notifications, if supported
+func (cfg *EnvConfig) language() string {
+	if !slices.Contains(notify.ValidLanguages, cfg.Language) {
+		slog.Warn("Unsupported language, using English", "language", cfg.Language)
+		return notify.DefaultLanguage
+	}
+	return cfg.Language
+}
+
+// location returns the location of BEACON_TIMEZONE, UTC if it's invalid
+func (cfg *EnvConfig) location() *time.Location {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		slog.Warn("Invalid timezone, using UTC", "timezone", cfg.Timezone, "error", err)
+		return time.UTC
+	}
+	return location
+}
+
+// loadSecret returns the persisted signing secret, generating it on first start
+func loadSecret(ctx context.Context, conn *db.Connection) (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	if err := conn.Q.CreateSetting(ctx, &db.CreateSettingParams{
+		Key:   "secret",
+		Value: hex.EncodeToString(key),
+	}); err != nil {
+		return "", err
+	}
+	return conn.Q.GetSetting(ctx, "secret")
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/export"
+)
+
+// ExportChecks writes the checks of a monitor, given by name or ID, between
+// the RFC 3339 times from and to, either of which may be empty
+func ExportChecks(ctx context.Context, w io.Writer, monitor, format, from, to string) (int, error) {
+	f, err := export.ParseFormat(format)
+	if err != nil {
+		return 0, err
+	}
+	since, err := parseTime(from)
+	if err != nil {
+		return 0, fmt.Errorf("invalid from time: %w", err)
+	}
+	until, err := parseTime(to)
+	if err != nil {
+		return 0, fmt.Errorf("invalid to time: %w", err)
+	}
+
+	conn, err := openDB(ctx)
+	if err != nil {
+		return 0, err
+	}
+	m, err := findMonitor(ctx, conn, monitor)
+	if err != nil {
+		return 0, err
+	}
+	return export.Checks(ctx, conn, w, f, m.ID, since, until)
+}
+
+// findMonitor returns the monitor with the ID or the unique name
+func findMonitor(ctx context.Context, conn *db.Connection, monitor string) (*db.Monitor, error) {
+	monitors, err := conn.Q.GetMonitors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monitors: %w", err)
+	}
+	id, idErr := strconv.ParseInt(monitor, 10, 64)
+
+	var found *db.Monitor
+	for _, m := range monitors {
+		if idErr == nil && m.ID == id {
+			return m, nil
+		}
+		if m.Name == monitor {
+			if found != nil {
+				return nil, fmt.Errorf("several monitors are named %q, use the ID", monitor)
+			}
+			found = m
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("unknown monitor %q", monitor)
+	}
+	return found, nil
+}
+
+func parseTime(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, err
+	}
+	t = t.UTC()
+	return &t, nil
+}
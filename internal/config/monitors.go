@@ -0,0 +1,140 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+// Sources of monitors. Monitors of the config file take precedence: the
+// config file takes over monitors of the admin API with the same URL, and
+// only its own monitors are removed when they are no longer listed.
+const (
+	SourceConfig = "config"
+	SourceAPI    = "api"
+)
+
+var (
+	ErrMonitorInvalid  = errors.New("invalid monitor")
+	ErrMonitorNotFound = errors.New("monitor not found")
+	ErrMonitorReadOnly = errors.New("monitor is managed by the config file")
+	ErrMonitorExists   = errors.New("a monitor with this name or url already exists")
+)
+
+// CreateMonitor adds a monitor through the admin API and starts checking it
+func (cfg *Config) CreateMonitor(ctx context.Context, m MonitorConfig) (*db.Monitor, error) {
+	if err := cfg.validateAPIMonitor(ctx, 0, m); err != nil {
+		return nil, err
+	}
+
+	threshold, percentile, window := m.degraded()
+	monitor, err := cfg.Conn.Q.CreateMonitor(ctx, &db.CreateMonitorParams{
+		Name:               m.Name,
+		Url:                m.URL,
+		CheckInterval:      m.CheckInterval,
+		GroupName:          m.Group,
+		DegradedThreshold:  threshold,
+		DegradedPercentile: percentile,
+		DegradedWindow:     window,
+		Source:             SourceAPI,
+		Public:             m.public(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create monitor: %w", err)
+	}
+
+	slog.Info("Added monitor", "url", monitor.Url, "source", SourceAPI)
+	cfg.Scheduler.Schedule(monitor)
+	return monitor, nil
+}
+
+// UpdateMonitor replaces the settings of a monitor of the admin API and
+// restarts checking it
+func (cfg *Config) UpdateMonitor(
+	ctx context.Context,
+	id int64,
+	m MonitorConfig,
+) (*db.Monitor, error) {
+	if _, err := cfg.apiMonitor(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := cfg.validateAPIMonitor(ctx, id, m); err != nil {
+		return nil, err
+	}
+
+	threshold, percentile, window := m.degraded()
+	monitor, err := cfg.Conn.Q.UpdateMonitor(ctx, &db.UpdateMonitorParams{
+		ID:                 id,
+		Name:               m.Name,
+		Url:                m.URL,
+		CheckInterval:      m.CheckInterval,
+		GroupName:          m.Group,
+		DegradedThreshold:  threshold,
+		DegradedPercentile: percentile,
+		DegradedWindow:     window,
+		Source:             SourceAPI,
+		Public:             m.public(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update monitor: %w", err)
+	}
+
+	slog.Info("Updated monitor", "url", monitor.Url, "source", SourceAPI)
+	cfg.Scheduler.Schedule(monitor)
+	return monitor, nil
+}
+
+// DeleteMonitor stops checking a monitor of the admin API and removes it
+// along with its checks
+func (cfg *Config) DeleteMonitor(ctx context.Context, id int64) error {
+	monitor, err := cfg.apiMonitor(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	cfg.Scheduler.Unschedule(id)
+	if err := cfg.Conn.Q.DeleteMonitor(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete monitor: %w", err)
+	}
+
+	slog.Info("Removed monitor", "url", monitor.Url, "source", SourceAPI)
+	return nil
+}
+
+// apiMonitor returns a monitor that may be changed through the admin API
+func (cfg *Config) apiMonitor(ctx context.Context, id int64) (*db.Monitor, error) {
+	monitor, err := cfg.Conn.Q.GetMonitor(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMonitorNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monitor: %w", err)
+	}
+	if monitor.Source != SourceAPI {
+		return nil, ErrMonitorReadOnly
+	}
+	return monitor, nil
+}
+
+// validateAPIMonitor validates a monitor of the admin API, which must not
+// share its name or URL with another monitor
+func (cfg *Config) validateAPIMonitor(ctx context.Context, id int64, m MonitorConfig) error {
+	if err := validateMonitors([]MonitorConfig{m}); err != nil {
+		return fmt.Errorf("%w: %w", ErrMonitorInvalid, err)
+	}
+
+	monitors, err := cfg.Conn.Q.GetMonitors(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get monitors: %w", err)
+	}
+	for _, other := range monitors {
+		if other.ID != id && (other.Name == m.Name || other.Url == m.URL) {
+			return ErrMonitorExists
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/notify"
+	"github.com/urfave/cli/v3"
+)
+
+// SendTestNotification sends a test event through a channel of the config
+// file, on behalf of a configured monitor or a placeholder
+func SendTestNotification(ctx context.Context, cmd *cli.Command) error {
+	cfg, err := env.ParseAs[Config]()
+	if err != nil {
+		return fmt.Errorf("failed to parse environment variables: %w", err)
+	}
+	cfg.ConfigPath = cmd.String("config")
+
+	file, err := cfg.loadConfigFile(cfg.location())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	name := cmd.String("channel")
+	i := slices.IndexFunc(file.Channels, func(c notify.ChannelConfig) bool {
+		return c.Name == name
+	})
+	if i < 0 {
+		return fmt.Errorf("unknown channel %q", name)
+	}
+
+	var mc *MonitorConfig
+	if monitorName := cmd.String("monitor"); monitorName != "" {
+		j := slices.IndexFunc(file.Monitors, func(m MonitorConfig) bool {
+			return m.Name == monitorName
+		})
+		if j < 0 {
+			return fmt.Errorf("unknown monitor %q", monitorName)
+		}
+		mc = &file.Monitors[j]
+	} else if len(file.Monitors) > 0 {
+		mc = &file.Monitors[0]
+	}
+
+	monitor := &db.Monitor{Name: "Example", Url: "https://example.com"}
+	if mc != nil {
+		monitor = &db.Monitor{
+			Name:      mc.Name,
+			Url:       mc.URL,
+			GroupName: mc.Group,
+		}
+	}
+
+	return notify.SendTestNotification(
+		ctx,
+		file.Channels[i],
+		cfg.language(),
+		monitor,
+		cmd.String("to"),
+	)
+}
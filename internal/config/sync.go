@@ -7,30 +7,77 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/maintenance"
+	"github.com/mizuchilabs/beacon/internal/metrics"
+	"github.com/mizuchilabs/beacon/internal/notify"
+	"github.com/mizuchilabs/beacon/internal/slo"
 	"gopkg.in/yaml.v3"
 )
 
 type MonitorConfig struct {
-	Name          string `yaml:"name"`
-	URL           string `yaml:"url"`
-	CheckInterval int64  `yaml:"check_interval"`
+	Name          string   `yaml:"name"`
+	URL           string   `yaml:"url"`
+	CheckInterval int64    `yaml:"check_interval"`
+	Group         string   `yaml:"group,omitempty"`
+	Escalation    string   `yaml:"escalation,omitempty"` // escalation policy name
+	DependsOn     []string `yaml:"depends_on,omitempty"` // names of parent monitors
+	Public        *bool    `yaml:"public,omitempty"`     // false hides it from anonymous visitors
+
+	// Slower responses count as degraded, judged by the latest check or, if
+	// set, by a percentile of the checks within the window
+	DegradedThresholdMs int64         `yaml:"degraded_threshold_ms,omitempty"` // 500 by default
+	DegradedPercentile  int64         `yaml:"degraded_percentile,omitempty"`   // e.g. 95
+	DegradedWindow      time.Duration `yaml:"degraded_window,omitempty"`       // 5m by default
+
+	SLO *slo.Objective `yaml:"slo,omitempty"`
+}
+
+// Defaults of the degraded state
+const (
+	defaultDegradedThreshold = 500 // ms
+	defaultDegradedWindow    = 5 * time.Minute
+)
+
+// degraded returns the degraded settings of the monitor with defaults applied
+func (m *MonitorConfig) degraded() (threshold, percentile, window int64) {
+	threshold = m.DegradedThresholdMs
+	if threshold == 0 {
+		threshold = defaultDegradedThreshold
+	}
+	w := m.DegradedWindow
+	if w == 0 {
+		w = defaultDegradedWindow
+	}
+	return threshold, m.DegradedPercentile, int64(w.Seconds())
+}
+
+// public reports whether the monitor is shown to anonymous visitors, as by
+// default
+func (m *MonitorConfig) public() bool {
+	return m.Public == nil || *m.Public
 }
 
 type MonitorsFile struct {
-	Monitors []MonitorConfig `yaml:"monitors"`
+	Monitors           []MonitorConfig           `yaml:"monitors"`
+	Channels           []notify.ChannelConfig    `yaml:"channels"`
+	EscalationPolicies []notify.EscalationPolicy `yaml:"escalation_policies"`
+	Maintenance        []maintenance.Window      `yaml:"maintenance"`
 }
 
-func (cfg *Config) loadMonitors() ([]MonitorConfig, error) {
+// loadConfigFile loads and validates the config file, with the times of
+// maintenance windows in location
+func (cfg *Config) loadConfigFile(location *time.Location) (*MonitorsFile, error) {
 	// Inline YAML from environment
 	if cfg.MonitorsYAML != "" {
 		slog.Debug("Loading monitors from environment...")
-		monitors, err := parseMonitorsYAML([]byte(cfg.MonitorsYAML))
+		file, err := parseMonitorsYAML([]byte(cfg.MonitorsYAML))
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse BEACON_MONITORS: %w", err)
 		}
-		return monitors, validateMonitors(monitors)
+		return file, file.validate(location)
 	}
 
 	// File path
@@ -38,25 +85,176 @@ func (cfg *Config) loadMonitors() ([]MonitorConfig, error) {
 	if err != nil {
 		if os.IsNotExist(err) {
 			slog.Warn("Config file not found, using empty monitors", "path", cfg.ConfigPath)
-			return nil, nil
+			return &MonitorsFile{}, nil
 		}
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
 	slog.Debug("Loading monitors from config file", "path", cfg.ConfigPath)
-	monitors, err := parseMonitorsYAML(data)
+	file, err := parseMonitorsYAML(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
-	return monitors, validateMonitors(monitors)
+	return file, file.validate(location)
 }
 
-func parseMonitorsYAML(data []byte) ([]MonitorConfig, error) {
+func parseMonitorsYAML(data []byte) (*MonitorsFile, error) {
 	var configFile MonitorsFile
 	if err := yaml.Unmarshal(data, &configFile); err != nil {
 		return nil, err
 	}
-	return configFile.Monitors, nil
+	return &configFile, nil
+}
+
+func (f *MonitorsFile) validate(location *time.Location) error {
+	if err := validateMonitors(f.Monitors); err != nil {
+		return err
+	}
+	if err := validateChannels(f.Channels); err != nil {
+		return err
+	}
+	if err := f.validateEscalations(); err != nil {
+		return err
+	}
+	if err := f.validateDependencies(); err != nil {
+		return err
+	}
+	return f.validateMaintenance(location)
+}
+
+func (f *MonitorsFile) validateDependencies() error {
+	parents := make(map[string][]string, len(f.Monitors))
+	for _, m := range f.Monitors {
+		parents[m.Name] = m.DependsOn
+	}
+
+	for _, m := range f.Monitors {
+		for _, parent := range m.DependsOn {
+			if _, exists := parents[parent]; !exists {
+				return fmt.Errorf("monitor %q: unknown dependency %q", m.Name, parent)
+			}
+		}
+	}
+
+	// Detect cycles with a depth-first search
+	const (
+		visiting = 1
+		done     = 2
+	)
+	marks := make(map[string]int, len(f.Monitors))
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch marks[name] {
+		case visiting:
+			return fmt.Errorf("monitor %q: circular dependency", name)
+		case done:
+			return nil
+		}
+		marks[name] = visiting
+		for _, parent := range parents[name] {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+		marks[name] = done
+		return nil
+	}
+	for _, m := range f.Monitors {
+		if err := visit(m.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *MonitorsFile) validateMaintenance(location *time.Location) error {
+	seenNames := make(map[string]struct{}, len(f.Maintenance))
+	for i := range f.Maintenance {
+		w := &f.Maintenance[i]
+		if err := w.Validate(location); err != nil {
+			return fmt.Errorf("maintenance window #%d: %w", i+1, err)
+		}
+		if _, exists := seenNames[w.Name]; exists {
+			return fmt.Errorf("maintenance window #%d: duplicate name %q", i+1, w.Name)
+		}
+		seenNames[w.Name] = struct{}{}
+	}
+	return nil
+}
+
+func (f *MonitorsFile) validateEscalations() error {
+	channels := make([]string, len(f.Channels))
+	for i, c := range f.Channels {
+		channels[i] = c.Name
+	}
+
+	policies := make(map[string]struct{}, len(f.EscalationPolicies))
+	for i, p := range f.EscalationPolicies {
+		if err := p.Validate(channels); err != nil {
+			return fmt.Errorf("escalation policy #%d: %w", i+1, err)
+		}
+		if _, exists := policies[p.Name]; exists {
+			return fmt.Errorf("escalation policy #%d: duplicate name %q", i+1, p.Name)
+		}
+		policies[p.Name] = struct{}{}
+	}
+
+	for _, m := range f.Monitors {
+		if m.Escalation == "" {
+			continue
+		}
+		if _, exists := policies[m.Escalation]; !exists {
+			return fmt.Errorf("monitor %q: unknown escalation policy %q", m.Name, m.Escalation)
+		}
+	}
+	return nil
+}
+
+// escalations maps monitor names to their escalation policy
+func (f *MonitorsFile) escalations() map[string]string {
+	escalations := make(map[string]string)
+	for _, m := range f.Monitors {
+		if m.Escalation != "" {
+			escalations[m.Name] = m.Escalation
+		}
+	}
+	return escalations
+}
+
+// objectives maps monitor names to their service level objective
+func (f *MonitorsFile) objectives() map[string]*slo.Objective {
+	objectives := make(map[string]*slo.Objective)
+	for _, m := range f.Monitors {
+		if m.SLO != nil {
+			objectives[m.Name] = m.SLO
+		}
+	}
+	return objectives
+}
+
+// budgetAlerts returns the monitors whose channels are alerted by burn rates
+func (f *MonitorsFile) budgetAlerts() map[string]bool {
+	monitors := make(map[string]bool)
+	for _, m := range f.Monitors {
+		if m.SLO != nil && !m.SLO.CheckAlerts {
+			monitors[m.Name] = true
+		}
+	}
+	return monitors
+}
+
+func validateChannels(channels []notify.ChannelConfig) error {
+	seenNames := make(map[string]struct{}, len(channels))
+	for i, c := range channels {
+		if err := c.Validate(); err != nil {
+			return fmt.Errorf("channel #%d: %w", i+1, err)
+		}
+		if _, exists := seenNames[c.Name]; exists {
+			return fmt.Errorf("channel #%d: duplicate name %q", i+1, c.Name)
+		}
+		seenNames[c.Name] = struct{}{}
+	}
+	return nil
 }
 
 func validateMonitors(monitors []MonitorConfig) error {
@@ -119,17 +317,44 @@ func validateMonitors(monitors []MonitorConfig) error {
 				m.CheckInterval,
 			)
 		}
+
+		// Validate degraded state
+		if m.DegradedThresholdMs < 0 {
+			return fmt.Errorf("monitor %q: degraded_threshold_ms must not be negative", m.Name)
+		}
+		if m.DegradedPercentile < 0 || m.DegradedPercentile > 100 {
+			return fmt.Errorf("monitor %q: degraded_percentile must be between 1 and 100", m.Name)
+		}
+		if m.DegradedWindow != 0 {
+			if m.DegradedPercentile == 0 {
+				return fmt.Errorf("monitor %q: degraded_window requires degraded_percentile", m.Name)
+			}
+			if m.DegradedWindow < time.Minute || m.DegradedWindow > 24*time.Hour {
+				return fmt.Errorf("monitor %q: degraded_window must be between 1m and 24h", m.Name)
+			}
+		}
+
+		// Validate service level objective
+		if m.SLO != nil {
+			if err := m.SLO.Validate(); err != nil {
+				return fmt.Errorf("monitor %q: slo: %w", m.Name, err)
+			}
+			if m.SLO.LatencyThreshold == 0 {
+				m.SLO.LatencyThreshold, _, _ = m.degraded()
+			}
+			if m.Escalation != "" && !m.SLO.CheckAlerts {
+				return fmt.Errorf(
+					"monitor %q: escalation requires slo.check_alerts, burn rate alerts don't escalate",
+					m.Name,
+				)
+			}
+		}
 	}
 
 	return nil
 }
 
-func (cfg *Config) syncMonitors(ctx context.Context) error {
-	monitors, err := cfg.loadMonitors()
-	if err != nil {
-		return err
-	}
-
+func (cfg *Config) syncMonitors(ctx context.Context, monitors []MonitorConfig) error {
 	dbMonitors, err := cfg.Conn.Q.GetMonitors(ctx)
 	if err != nil {
 		return err
@@ -148,27 +373,49 @@ func (cfg *Config) syncMonitors(ctx context.Context) error {
 
 	// Upsert monitors from config
 	for url, configMonitor := range configMap {
+		threshold, percentile, window := configMonitor.degraded()
 		if dbMonitor, exists := dbMap[url]; exists {
 			// Only update if something changed
 			if dbMonitor.Name != configMonitor.Name ||
-				dbMonitor.CheckInterval != configMonitor.CheckInterval {
+				dbMonitor.CheckInterval != configMonitor.CheckInterval ||
+				dbMonitor.GroupName != configMonitor.Group ||
+				dbMonitor.DegradedThreshold != threshold ||
+				dbMonitor.DegradedPercentile != percentile ||
+				dbMonitor.DegradedWindow != window ||
+				dbMonitor.Public != configMonitor.public() ||
+				dbMonitor.Source != SourceConfig {
 				_, err := cfg.Conn.Q.UpdateMonitor(ctx, &db.UpdateMonitorParams{
-					ID:            dbMonitor.ID,
-					Name:          configMonitor.Name,
-					Url:           configMonitor.URL,
-					CheckInterval: configMonitor.CheckInterval,
+					ID:                 dbMonitor.ID,
+					Name:               configMonitor.Name,
+					Url:                configMonitor.URL,
+					CheckInterval:      configMonitor.CheckInterval,
+					GroupName:          configMonitor.Group,
+					DegradedThreshold:  threshold,
+					DegradedPercentile: percentile,
+					DegradedWindow:     window,
+					Source:             SourceConfig,
+					Public:             configMonitor.public(),
 				})
 				if err != nil {
 					return err
 				}
+				if dbMonitor.Source == SourceAPI {
+					slog.Warn("Monitor of the admin API is now managed by the config file", "url", url)
+				}
 				slog.Info("Updated monitor", "url", url)
 			}
 			delete(dbMap, url) // Remove from deletion list
 		} else {
 			_, err := cfg.Conn.Q.CreateMonitor(ctx, &db.CreateMonitorParams{
-				Name:          configMonitor.Name,
-				Url:           configMonitor.URL,
-				CheckInterval: configMonitor.CheckInterval,
+				Name:               configMonitor.Name,
+				Url:                configMonitor.URL,
+				CheckInterval:      configMonitor.CheckInterval,
+				GroupName:          configMonitor.Group,
+				DegradedThreshold:  threshold,
+				DegradedPercentile: percentile,
+				DegradedWindow:     window,
+				Source:             SourceConfig,
+				Public:             configMonitor.public(),
 			})
 			if err != nil {
 				return err
@@ -177,13 +424,58 @@ func (cfg *Config) syncMonitors(ctx context.Context) error {
 		}
 	}
 
-	// Delete monitors not in config
+	// Delete monitors not in config, keeping those of the admin API
+	names := make(map[string]bool, len(monitors))
+	for _, m := range monitors {
+		names[m.Name] = true
+	}
 	for url, dbMonitor := range dbMap {
+		if dbMonitor.Source == SourceAPI {
+			if names[dbMonitor.Name] {
+				slog.Warn("Monitor of the admin API has the name of one in the config file",
+					"name", dbMonitor.Name,
+					"url", url,
+				)
+			}
+			continue
+		}
 		if err := cfg.Conn.Q.DeleteMonitor(ctx, dbMonitor.ID); err != nil {
 			return err
 		}
+		metrics.RemoveMonitor(dbMonitor.ID)
 		slog.Info("Removed monitor", "url", url)
 	}
 
+	return cfg.syncDependencies(ctx, monitors)
+}
+
+func (cfg *Config) syncDependencies(ctx context.Context, monitors []MonitorConfig) error {
+	dbMonitors, err := cfg.Conn.Q.GetMonitors(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Dependencies are between monitors of the config file
+	ids := make(map[string]int64, len(dbMonitors))
+	for _, m := range dbMonitors {
+		if m.Source == SourceConfig {
+			ids[m.Name] = m.ID
+		}
+	}
+
+	for _, m := range monitors {
+		id := ids[m.Name]
+		if err := cfg.Conn.Q.DeleteMonitorDependencies(ctx, id); err != nil {
+			return err
+		}
+		for _, parent := range m.DependsOn {
+			if err := cfg.Conn.Q.CreateMonitorDependency(ctx, &db.CreateMonitorDependencyParams{
+				MonitorID: id,
+				ParentID:  ids[parent],
+			}); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
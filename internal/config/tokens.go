@@ -0,0 +1,60 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/mizuchilabs/beacon/internal/auth"
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+// CreateAPIToken creates a token of the admin API and returns it
+func CreateAPIToken(ctx context.Context, name, scope string) (string, error) {
+	s, err := auth.ParseScope(scope)
+	if err != nil {
+		return "", err
+	}
+	conn, err := openDB(ctx)
+	if err != nil {
+		return "", err
+	}
+	token, _, err := auth.CreateToken(ctx, conn, name, s)
+	return token, err
+}
+
+// APITokens returns the tokens of the admin API
+func APITokens(ctx context.Context) ([]*db.ApiToken, error) {
+	conn, err := openDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return conn.Q.GetAPITokens(ctx)
+}
+
+// RevokeAPIToken deletes a token of the admin API by its name
+func RevokeAPIToken(ctx context.Context, name string) error {
+	conn, err := openDB(ctx)
+	if err != nil {
+		return err
+	}
+	tokens, err := conn.Q.GetAPITokens(ctx)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(tokens, func(t *db.ApiToken) bool { return t.Name == name })
+	if i < 0 {
+		return fmt.Errorf("unknown token %q", name)
+	}
+	_, err = conn.Q.DeleteAPIToken(ctx, tokens[i].ID)
+	return err
+}
+
+func openDB(ctx context.Context) (*db.Connection, error) {
+	cfg, err := env.ParseAs[EnvConfig]()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse environment variables: %w", err)
+	}
+	return db.NewConnection(ctx, cfg.DBPath), nil
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mizuchilabs/beacon/internal/auth"
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+// CreateUser adds a user who can sign in to the dashboard with a role
+func CreateUser(ctx context.Context, name, password, role string) error {
+	conn, err := openDB(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = auth.CreateUser(ctx, conn, name, password, role)
+	return err
+}
+
+// Users returns the users of the dashboard
+func Users(ctx context.Context) ([]*db.User, error) {
+	conn, err := openDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return conn.Q.GetUsers(ctx)
+}
+
+// SetUserPassword replaces the password of a user, signing them out
+func SetUserPassword(ctx context.Context, name, password string) error {
+	conn, err := openDB(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := getUser(ctx, conn, name)
+	if err != nil {
+		return err
+	}
+	return auth.SetPassword(ctx, conn, user.ID, password)
+}
+
+// SetUserRole replaces the role of a user, signing them out
+func SetUserRole(ctx context.Context, name, role string) error {
+	conn, err := openDB(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := getUser(ctx, conn, name)
+	if err != nil {
+		return err
+	}
+	return auth.SetRole(ctx, conn, user.ID, role)
+}
+
+// DeleteUser removes a user of the dashboard along with their sessions
+func DeleteUser(ctx context.Context, name string) error {
+	conn, err := openDB(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := getUser(ctx, conn, name)
+	if err != nil {
+		return err
+	}
+	return conn.Q.DeleteUser(ctx, user.ID)
+}
+
+func getUser(ctx context.Context, conn *db.Connection, name string) (*db.User, error) {
+	user, err := conn.Q.GetUserByUsername(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("unknown user %q", name)
+	}
+	return user, err
+}
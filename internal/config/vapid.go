@@ -0,0 +1,65 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/notify"
+	"gopkg.in/yaml.v3"
+)
+
+// vapidKeyFile holds imported VAPID keys, as YAML or JSON
+type vapidKeyFile struct {
+	PublicKey  string `yaml:"public_key"`
+	PrivateKey string `yaml:"private_key"`
+}
+
+// vapidOptions returns the VAPID subject and the keys imported from the
+// environment or a key file
+func (cfg *EnvConfig) vapidOptions() (notify.VAPIDOptions, error) {
+	opts := notify.VAPIDOptions{
+		Subject:    cfg.VAPIDSubject,
+		PublicKey:  cfg.VAPIDPublicKey,
+		PrivateKey: cfg.VAPIDPrivateKey,
+	}
+
+	if cfg.VAPIDKeyFile != "" {
+		if opts.PublicKey != "" || opts.PrivateKey != "" {
+			return opts, fmt.Errorf("use either BEACON_VAPID_KEY_FILE or BEACON_VAPID_*_KEY, not both")
+		}
+		data, err := os.ReadFile(cfg.VAPIDKeyFile)
+		if err != nil {
+			return opts, err
+		}
+		var file vapidKeyFile
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return opts, fmt.Errorf("invalid key file: %w", err)
+		}
+		opts.PublicKey = file.PublicKey
+		opts.PrivateKey = file.PrivateKey
+	}
+
+	return opts, opts.Validate()
+}
+
+// RotateVAPIDKeys replaces the stored VAPID keys with new ones
+func RotateVAPIDKeys(ctx context.Context) (*db.VapidKey, error) {
+	cfg, err := env.ParseAs[EnvConfig]()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse environment variables: %w", err)
+	}
+
+	opts, err := cfg.vapidOptions()
+	if err != nil {
+		return nil, err
+	}
+	if opts.PublicKey != "" {
+		return nil, fmt.Errorf("VAPID keys are imported, replace them at their source instead")
+	}
+
+	conn := db.NewConnection(ctx, cfg.DBPath)
+	return notify.RotateVAPIDKeys(ctx, conn)
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// checkBatchSize is how many checks EachCheck reads at once
+const checkBatchSize = 1000
+
+// EachCheck calls fn with the checks of a monitor between since and until,
+// oldest first. Checks are read in batches, so long periods don't have to fit
+// in memory. Either time may be nil.
+func (q *Queries) EachCheck(
+	ctx context.Context,
+	monitorID int64,
+	since, until *time.Time,
+	fn func(*Check) error,
+) error {
+	params := &GetCheckBatchParams{
+		MonitorID: monitorID,
+		Since:     since,
+		Until:     until,
+		Limit:     checkBatchSize,
+	}
+	for {
+		checks, err := q.GetCheckBatch(ctx, params)
+		if err != nil {
+			return err
+		}
+		for _, c := range checks {
+			if err := fn(c); err != nil {
+				return err
+			}
+		}
+		if len(checks) < checkBatchSize {
+			return nil
+		}
+		// Checks are unique by monitor and time, the next batch starts after
+		// the last one
+		params.After = &checks[len(checks)-1].CheckedAt
+	}
+}
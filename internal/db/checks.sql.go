@@ -21,36 +21,236 @@ func (q *Queries) CleanupChecks(ctx context.Context, days *string) error {
 	return err
 }
 
-const createCheck = `-- name: CreateCheck :exec
+const createCheck = `-- name: CreateCheck :one
 INSERT INTO
   checks (
     monitor_id,
     status_code,
     response_time,
     error,
-    is_up
+    is_up,
+    state,
+    cert_expires_at
   )
 VALUES
-  (?, ?, ?, ?, ?)
+  (?, ?, ?, ?, ?, ?, ?) RETURNING checked_at
 `
 
 type CreateCheckParams struct {
-	MonitorID    int64   `json:"monitorId"`
-	StatusCode   int64   `json:"statusCode"`
-	ResponseTime int64   `json:"responseTime"`
-	Error        *string `json:"error"`
-	IsUp         bool    `json:"isUp"`
+	MonitorID     int64      `json:"monitorId"`
+	StatusCode    int64      `json:"statusCode"`
+	ResponseTime  int64      `json:"responseTime"`
+	Error         *string    `json:"error"`
+	IsUp          bool       `json:"isUp"`
+	State         *string    `json:"state"`
+	CertExpiresAt *time.Time `json:"certExpiresAt"`
 }
 
-func (q *Queries) CreateCheck(ctx context.Context, arg *CreateCheckParams) error {
-	_, err := q.exec(ctx, q.createCheckStmt, createCheck,
+func (q *Queries) CreateCheck(ctx context.Context, arg *CreateCheckParams) (time.Time, error) {
+	row := q.queryRow(ctx, q.createCheckStmt, createCheck,
 		arg.MonitorID,
 		arg.StatusCode,
 		arg.ResponseTime,
 		arg.Error,
 		arg.IsUp,
+		arg.State,
+		arg.CertExpiresAt,
 	)
-	return err
+	var checked_at time.Time
+	err := row.Scan(&checked_at)
+	return checked_at, err
+}
+
+const getCheckBatch = `-- name: GetCheckBatch :many
+SELECT
+  monitor_id, status_code, response_time, error, is_up, state, checked_at, cert_expires_at
+FROM
+  checks
+WHERE
+  monitor_id = ?1
+  AND (
+    ?2 IS NULL
+    OR checked_at >= ?2
+  )
+  AND (
+    ?3 IS NULL
+    OR checked_at < ?3
+  )
+  AND (
+    ?4 IS NULL
+    OR checked_at > ?4
+  )
+ORDER BY
+  checked_at
+LIMIT
+  ?5
+`
+
+type GetCheckBatchParams struct {
+	MonitorID int64      `json:"monitorId"`
+	Since     *time.Time `json:"since"`
+	Until     *time.Time `json:"until"`
+	After     *time.Time `json:"after"`
+	Limit     int64      `json:"limit"`
+}
+
+func (q *Queries) GetCheckBatch(ctx context.Context, arg *GetCheckBatchParams) ([]*Check, error) {
+	rows, err := q.query(ctx, q.getCheckBatchStmt, getCheckBatch, arg.MonitorID, arg.Since, arg.Until, arg.After, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Check
+	for rows.Next() {
+		var i Check
+		if err := rows.Scan(
+			&i.MonitorID,
+			&i.StatusCode,
+			&i.ResponseTime,
+			&i.Error,
+			&i.IsUp,
+			&i.State,
+			&i.CheckedAt,
+			&i.CertExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCheckCounts = `-- name: GetCheckCounts :one
+SELECT
+  COUNT(*) AS total,
+  CAST(
+    COALESCE(
+      SUM(
+        CASE
+          WHEN NOT is_up THEN 1
+          ELSE 0
+        END
+      ),
+      0
+    ) AS INTEGER
+  ) AS down_count,
+  CAST(
+    COALESCE(
+      SUM(
+        CASE
+          WHEN is_up
+          AND response_time > ?1 THEN 1
+          ELSE 0
+        END
+      ),
+      0
+    ) AS INTEGER
+  ) AS slow_count
+FROM
+  checks
+WHERE
+  monitor_id = ?2
+  AND checked_at >= ?3
+  AND state IS NOT 'maintenance'
+`
+
+type GetCheckCountsParams struct {
+	LatencyThreshold int64     `json:"latencyThreshold"`
+	MonitorID        int64     `json:"monitorId"`
+	Since            time.Time `json:"since"`
+}
+
+type GetCheckCountsRow struct {
+	Total     int64 `json:"total"`
+	DownCount int64 `json:"downCount"`
+	SlowCount int64 `json:"slowCount"`
+}
+
+func (q *Queries) GetCheckCounts(ctx context.Context, arg *GetCheckCountsParams) (*GetCheckCountsRow, error) {
+	row := q.queryRow(ctx, q.getCheckCountsStmt, getCheckCounts, arg.LatencyThreshold, arg.MonitorID, arg.Since)
+	var i GetCheckCountsRow
+	err := row.Scan(
+		&i.Total,
+		&i.DownCount,
+		&i.SlowCount,
+	)
+	return &i, err
+}
+
+const getChecks = `-- name: GetChecks :many
+SELECT
+  monitor_id, status_code, response_time, error, is_up, state, checked_at, cert_expires_at
+FROM
+  checks
+WHERE
+  monitor_id = ?1
+  AND (
+    ?2 IS NULL
+    OR checked_at >= ?2
+  )
+  AND (
+    ?3 IS NULL
+    OR checked_at < ?3
+  )
+  AND (
+    ?4 IS NULL
+    OR is_up = ?4
+  )
+  AND (
+    ?5 IS NULL
+    OR CAST(strftime('%s', checked_at) AS INTEGER) < CAST(?5 AS INTEGER)
+  )
+ORDER BY
+  checked_at DESC
+LIMIT
+  ?6
+`
+
+type GetChecksParams struct {
+	MonitorID int64      `json:"monitorId"`
+	Since     *time.Time `json:"since"`
+	Until     *time.Time `json:"until"`
+	IsUp      *bool      `json:"isUp"`
+	Before    *int64     `json:"before"`
+	Limit     int64      `json:"limit"`
+}
+
+func (q *Queries) GetChecks(ctx context.Context, arg *GetChecksParams) ([]*Check, error) {
+	rows, err := q.query(ctx, q.getChecksStmt, getChecks, arg.MonitorID, arg.Since, arg.Until, arg.IsUp, arg.Before, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Check
+	for rows.Next() {
+		var i Check
+		if err := rows.Scan(
+			&i.MonitorID,
+			&i.StatusCode,
+			&i.ResponseTime,
+			&i.Error,
+			&i.IsUp,
+			&i.State,
+			&i.CheckedAt,
+			&i.CertExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getDataPoints = `-- name: GetDataPoints :many
@@ -67,7 +267,7 @@ SELECT
         WHEN is_up
         AND (
           response_time IS NULL
-          OR response_time <= ?2
+          OR response_time <= m.degraded_threshold
         ) THEN 1
         ELSE 0
       END
@@ -77,7 +277,7 @@ SELECT
     SUM(
       CASE
         WHEN is_up
-        AND response_time > ?2 THEN 1
+        AND response_time > m.degraded_threshold THEN 1
         ELSE 0
       END
     ) AS REAL
@@ -85,16 +285,31 @@ SELECT
   CAST(
     SUM(
       CASE
-        WHEN NOT is_up THEN 1
+        WHEN NOT is_up
+        AND state IS NOT 'maintenance' THEN 1
         ELSE 0
       END
     ) AS REAL
-  ) AS down_count
+  ) AS down_count,
+  CAST(
+    SUM(
+      CASE
+        WHEN NOT is_up
+        AND state = 'maintenance' THEN 1
+        ELSE 0
+      END
+    ) AS REAL
+  ) AS maintenance_count
 FROM
   checks
+  JOIN monitors m ON m.id = checks.monitor_id
 WHERE
-  checked_at >= ?3
+  checked_at >= ?2
   AND checked_at IS NOT NULL
+  AND (
+    ?3 IS NULL
+    OR monitor_id = ?3
+  )
 GROUP BY
   monitor_id,
   bucket_ts
@@ -106,23 +321,24 @@ ORDER BY
 `
 
 type GetDataPointsParams struct {
-	BucketSize        int64     `json:"bucketSize"`
-	DegradedThreshold int64     `json:"degradedThreshold"`
-	Since             time.Time `json:"since"`
+	BucketSize int64     `json:"bucketSize"`
+	Since      time.Time `json:"since"`
+	MonitorID  *int64    `json:"monitorId"`
 }
 
 type GetDataPointsRow struct {
-	MonitorID       int64   `json:"monitorId"`
-	BucketTs        int64   `json:"bucketTs"`
-	TotalCount      int64   `json:"totalCount"`
-	AvgResponseTime int64   `json:"avgResponseTime"`
-	UpCount         float64 `json:"upCount"`
-	DegradedCount   float64 `json:"degradedCount"`
-	DownCount       float64 `json:"downCount"`
+	MonitorID        int64   `json:"monitorId"`
+	BucketTs         int64   `json:"bucketTs"`
+	TotalCount       int64   `json:"totalCount"`
+	AvgResponseTime  int64   `json:"avgResponseTime"`
+	UpCount          float64 `json:"upCount"`
+	DegradedCount    float64 `json:"degradedCount"`
+	DownCount        float64 `json:"downCount"`
+	MaintenanceCount float64 `json:"maintenanceCount"`
 }
 
 func (q *Queries) GetDataPoints(ctx context.Context, arg *GetDataPointsParams) ([]*GetDataPointsRow, error) {
-	rows, err := q.query(ctx, q.getDataPointsStmt, getDataPoints, arg.BucketSize, arg.DegradedThreshold, arg.Since)
+	rows, err := q.query(ctx, q.getDataPointsStmt, getDataPoints, arg.BucketSize, arg.Since, arg.MonitorID)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +354,7 @@ func (q *Queries) GetDataPoints(ctx context.Context, arg *GetDataPointsParams) (
 			&i.UpCount,
 			&i.DegradedCount,
 			&i.DownCount,
+			&i.MaintenanceCount,
 		); err != nil {
 			return nil, err
 		}
@@ -152,22 +369,110 @@ func (q *Queries) GetDataPoints(ctx context.Context, arg *GetDataPointsParams) (
 	return items, nil
 }
 
+const getLatestCheck = `-- name: GetLatestCheck :one
+SELECT
+  monitor_id,
+  status_code,
+  response_time,
+  error,
+  is_up,
+  state,
+  checked_at,
+  cert_expires_at
+FROM
+  checks
+WHERE
+  monitor_id = ?
+ORDER BY
+  checked_at DESC
+LIMIT
+  1
+`
+
+func (q *Queries) GetLatestCheck(ctx context.Context, monitorID int64) (*Check, error) {
+	row := q.queryRow(ctx, q.getLatestCheckStmt, getLatestCheck, monitorID)
+	var i Check
+	err := row.Scan(
+		&i.MonitorID,
+		&i.StatusCode,
+		&i.ResponseTime,
+		&i.Error,
+		&i.IsUp,
+		&i.State,
+		&i.CheckedAt,
+		&i.CertExpiresAt,
+	)
+	return &i, err
+}
+
+const getMonitorResponseTimes = `-- name: GetMonitorResponseTimes :many
+SELECT
+  response_time
+FROM
+  checks
+WHERE
+  monitor_id = ?1
+  AND checked_at >= ?2
+  AND is_up = 1
+  AND state IS NULL
+`
+
+type GetMonitorResponseTimesParams struct {
+	MonitorID int64     `json:"monitorId"`
+	Since     time.Time `json:"since"`
+}
+
+func (q *Queries) GetMonitorResponseTimes(ctx context.Context, arg *GetMonitorResponseTimesParams) ([]int64, error) {
+	rows, err := q.query(ctx, q.getMonitorResponseTimesStmt, getMonitorResponseTimes, arg.MonitorID, arg.Since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var response_time int64
+		if err := rows.Scan(&response_time); err != nil {
+			return nil, err
+		}
+		items = append(items, response_time)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getMonitorStats = `-- name: GetMonitorStats :many
 SELECT
   m.id,
   m.name,
   m.url,
   m.check_interval,
+  m.group_name,
+  m.degraded_threshold,
+  m.public,
   COUNT(c.monitor_id) AS total_checks,
   CAST(
     ROUND(
       COALESCE(
         SUM(
           CASE
-            WHEN c.is_up THEN 1
+            WHEN c.is_up
+            AND c.state IS NOT 'maintenance' THEN 1
             ELSE 0
           END
-        ) * 100.0 / COUNT(c.monitor_id),
+        ) * 100.0 / NULLIF(
+          SUM(
+            CASE
+              WHEN c.state IS NOT 'maintenance' THEN 1
+              ELSE 0
+            END
+          ),
+          0
+        ),
         100.0
       ),
       2
@@ -178,24 +483,35 @@ FROM
   monitors m
   LEFT JOIN checks c ON c.monitor_id = m.id
   AND c.checked_at >= datetime('now', '-' || ?1 || ' seconds')
+WHERE
+  ?2 IS NULL
+  OR m.id = ?2
 GROUP BY
   m.id
 ORDER BY
   m.id
 `
 
+type GetMonitorStatsParams struct {
+	Seconds   *string `json:"seconds"`
+	MonitorID *int64  `json:"monitorId"`
+}
+
 type GetMonitorStatsRow struct {
-	ID              int64   `json:"id"`
-	Name            string  `json:"name"`
-	Url             string  `json:"url"`
-	CheckInterval   int64   `json:"checkInterval"`
-	TotalChecks     int64   `json:"totalChecks"`
-	UptimePct       float64 `json:"uptimePct"`
-	AvgResponseTime int64   `json:"avgResponseTime"`
+	ID                int64   `json:"id"`
+	Name              string  `json:"name"`
+	Url               string  `json:"url"`
+	CheckInterval     int64   `json:"checkInterval"`
+	GroupName         string  `json:"groupName"`
+	DegradedThreshold int64   `json:"degradedThreshold"`
+	Public            bool    `json:"public"`
+	TotalChecks       int64   `json:"totalChecks"`
+	UptimePct         float64 `json:"uptimePct"`
+	AvgResponseTime   int64   `json:"avgResponseTime"`
 }
 
-func (q *Queries) GetMonitorStats(ctx context.Context, seconds *string) ([]*GetMonitorStatsRow, error) {
-	rows, err := q.query(ctx, q.getMonitorStatsStmt, getMonitorStats, seconds)
+func (q *Queries) GetMonitorStats(ctx context.Context, arg *GetMonitorStatsParams) ([]*GetMonitorStatsRow, error) {
+	rows, err := q.query(ctx, q.getMonitorStatsStmt, getMonitorStats, arg.Seconds, arg.MonitorID)
 	if err != nil {
 		return nil, err
 	}
@@ -208,6 +524,9 @@ func (q *Queries) GetMonitorStats(ctx context.Context, seconds *string) ([]*GetM
 			&i.Name,
 			&i.Url,
 			&i.CheckInterval,
+			&i.GroupName,
+			&i.DegradedThreshold,
+			&i.Public,
 			&i.TotalChecks,
 			&i.UptimePct,
 			&i.AvgResponseTime,
@@ -225,6 +544,48 @@ func (q *Queries) GetMonitorStats(ctx context.Context, seconds *string) ([]*GetM
 	return items, nil
 }
 
+const getRecentChecks = `-- name: GetRecentChecks :many
+SELECT
+  is_up
+FROM
+  checks
+WHERE
+  monitor_id = ?
+  AND state IS NULL
+ORDER BY
+  checked_at DESC
+LIMIT
+  ?
+`
+
+type GetRecentChecksParams struct {
+	MonitorID int64 `json:"monitorId"`
+	Limit     int64 `json:"limit"`
+}
+
+func (q *Queries) GetRecentChecks(ctx context.Context, arg *GetRecentChecksParams) ([]bool, error) {
+	rows, err := q.query(ctx, q.getRecentChecksStmt, getRecentChecks, arg.MonitorID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []bool
+	for rows.Next() {
+		var is_up bool
+		if err := rows.Scan(&is_up); err != nil {
+			return nil, err
+		}
+		items = append(items, is_up)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getResponseTimes = `-- name: GetResponseTimes :many
 SELECT
   monitor_id,
@@ -235,15 +596,24 @@ WHERE
   checked_at >= ?1
   AND is_up = 1
   AND response_time IS NOT NULL
+  AND (
+    ?2 IS NULL
+    OR monitor_id = ?2
+  )
 `
 
+type GetResponseTimesParams struct {
+	Since     time.Time `json:"since"`
+	MonitorID *int64    `json:"monitorId"`
+}
+
 type GetResponseTimesRow struct {
 	MonitorID    int64 `json:"monitorId"`
 	ResponseTime int64 `json:"responseTime"`
 }
 
-func (q *Queries) GetResponseTimes(ctx context.Context, since time.Time) ([]*GetResponseTimesRow, error) {
-	rows, err := q.query(ctx, q.getResponseTimesStmt, getResponseTimes, since)
+func (q *Queries) GetResponseTimes(ctx context.Context, arg *GetResponseTimesParams) ([]*GetResponseTimesRow, error) {
+	rows, err := q.query(ctx, q.getResponseTimesStmt, getResponseTimes, arg.Since, arg.MonitorID)
 	if err != nil {
 		return nil, err
 	}
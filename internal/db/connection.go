@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mizuchilabs/beacon/internal/metrics"
 	"github.com/mizuchilabs/sqlite-schema-diff/pkg/diff"
 	"github.com/mizuchilabs/sqlite-schema-diff/pkg/parser"
 	_ "modernc.org/sqlite"
@@ -47,7 +48,7 @@ func NewConnection(ctx context.Context, path string) *Connection {
 	migrate(db)
 	conn := &Connection{
 		db: db,
-		Q:  New(db),
+		Q:  New(timedDB{db}),
 	}
 
 	// Wait for shutdown signal
@@ -86,6 +87,30 @@ func setupSQLite(db *sql.DB) error {
 	return nil
 }
 
+// timedDB records the latency of writes and queries. Rows are read after
+// the query returns, so for queries this is the time to the first row.
+type timedDB struct {
+	*sql.DB
+}
+
+func (t timedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	defer func() { metrics.ObserveDBWrite(time.Since(start)) }()
+	return t.DB.ExecContext(ctx, query, args...)
+}
+
+func (t timedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	defer func() { metrics.ObserveDBQuery(time.Since(start)) }()
+	return t.DB.QueryContext(ctx, query, args...)
+}
+
+func (t timedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	start := time.Now()
+	defer func() { metrics.ObserveDBQuery(time.Since(start)) }()
+	return t.DB.QueryRowContext(ctx, query, args...)
+}
+
 func (c *Connection) Get() *sql.DB {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
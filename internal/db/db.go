@@ -24,54 +24,276 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.acknowledgeAlertStmt, err = db.PrepareContext(ctx, acknowledgeAlert); err != nil {
+		return nil, fmt.Errorf("error preparing query AcknowledgeAlert: %w", err)
+	}
+	if q.cleanupAlertsStmt, err = db.PrepareContext(ctx, cleanupAlerts); err != nil {
+		return nil, fmt.Errorf("error preparing query CleanupAlerts: %w", err)
+	}
 	if q.cleanupChecksStmt, err = db.PrepareContext(ctx, cleanupChecks); err != nil {
 		return nil, fmt.Errorf("error preparing query CleanupChecks: %w", err)
 	}
+	if q.cleanupEmailSubscribersStmt, err = db.PrepareContext(ctx, cleanupEmailSubscribers); err != nil {
+		return nil, fmt.Errorf("error preparing query CleanupEmailSubscribers: %w", err)
+	}
+	if q.cleanupNotificationsStmt, err = db.PrepareContext(ctx, cleanupNotifications); err != nil {
+		return nil, fmt.Errorf("error preparing query CleanupNotifications: %w", err)
+	}
+	if q.confirmEmailSubscriberStmt, err = db.PrepareContext(ctx, confirmEmailSubscriber); err != nil {
+		return nil, fmt.Errorf("error preparing query ConfirmEmailSubscriber: %w", err)
+	}
+	if q.countMonitorNotificationsStmt, err = db.PrepareContext(ctx, countMonitorNotifications); err != nil {
+		return nil, fmt.Errorf("error preparing query CountMonitorNotifications: %w", err)
+	}
+	if q.countRecentNotificationsStmt, err = db.PrepareContext(ctx, countRecentNotifications); err != nil {
+		return nil, fmt.Errorf("error preparing query CountRecentNotifications: %w", err)
+	}
+	if q.createAPITokenStmt, err = db.PrepareContext(ctx, createAPIToken); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAPIToken: %w", err)
+	}
+	if q.createAlertStmt, err = db.PrepareContext(ctx, createAlert); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAlert: %w", err)
+	}
 	if q.createCheckStmt, err = db.PrepareContext(ctx, createCheck); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateCheck: %w", err)
 	}
+	if q.createEmailSubscriberStmt, err = db.PrepareContext(ctx, createEmailSubscriber); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateEmailSubscriber: %w", err)
+	}
 	if q.createMonitorStmt, err = db.PrepareContext(ctx, createMonitor); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateMonitor: %w", err)
 	}
+	if q.createMonitorDependencyStmt, err = db.PrepareContext(ctx, createMonitorDependency); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateMonitorDependency: %w", err)
+	}
+	if q.createNotificationStmt, err = db.PrepareContext(ctx, createNotification); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateNotification: %w", err)
+	}
+	if q.createOutageStmt, err = db.PrepareContext(ctx, createOutage); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateOutage: %w", err)
+	}
 	if q.createPushSubscriptionStmt, err = db.PrepareContext(ctx, createPushSubscription); err != nil {
 		return nil, fmt.Errorf("error preparing query CreatePushSubscription: %w", err)
 	}
+	if q.createSessionStmt, err = db.PrepareContext(ctx, createSession); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSession: %w", err)
+	}
+	if q.createSettingStmt, err = db.PrepareContext(ctx, createSetting); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSetting: %w", err)
+	}
+	if q.createUserStmt, err = db.PrepareContext(ctx, createUser); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUser: %w", err)
+	}
 	if q.createVAPIDKeysStmt, err = db.PrepareContext(ctx, createVAPIDKeys); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateVAPIDKeys: %w", err)
 	}
+	if q.deleteAPITokenStmt, err = db.PrepareContext(ctx, deleteAPIToken); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAPIToken: %w", err)
+	}
+	if q.deleteEmailSubscribersByAddressStmt, err = db.PrepareContext(ctx, deleteEmailSubscribersByAddress); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteEmailSubscribersByAddress: %w", err)
+	}
+	if q.deleteExpiredSessionsStmt, err = db.PrepareContext(ctx, deleteExpiredSessions); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteExpiredSessions: %w", err)
+	}
 	if q.deleteMonitorStmt, err = db.PrepareContext(ctx, deleteMonitor); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteMonitor: %w", err)
 	}
+	if q.deleteMonitorDependenciesStmt, err = db.PrepareContext(ctx, deleteMonitorDependencies); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteMonitorDependencies: %w", err)
+	}
+	if q.deleteMonitorOutagesStmt, err = db.PrepareContext(ctx, deleteMonitorOutages); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteMonitorOutages: %w", err)
+	}
 	if q.deletePushSubscriptionStmt, err = db.PrepareContext(ctx, deletePushSubscription); err != nil {
 		return nil, fmt.Errorf("error preparing query DeletePushSubscription: %w", err)
 	}
 	if q.deletePushSubscriptionByEndpointStmt, err = db.PrepareContext(ctx, deletePushSubscriptionByEndpoint); err != nil {
 		return nil, fmt.Errorf("error preparing query DeletePushSubscriptionByEndpoint: %w", err)
 	}
+	if q.deleteSessionStmt, err = db.PrepareContext(ctx, deleteSession); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteSession: %w", err)
+	}
+	if q.deleteUserStmt, err = db.PrepareContext(ctx, deleteUser); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteUser: %w", err)
+	}
+	if q.deleteUserSessionsStmt, err = db.PrepareContext(ctx, deleteUserSessions); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteUserSessions: %w", err)
+	}
+	if q.endOutageStmt, err = db.PrepareContext(ctx, endOutage); err != nil {
+		return nil, fmt.Errorf("error preparing query EndOutage: %w", err)
+	}
+	if q.extendOutageStmt, err = db.PrepareContext(ctx, extendOutage); err != nil {
+		return nil, fmt.Errorf("error preparing query ExtendOutage: %w", err)
+	}
+	if q.failChannelNotificationsStmt, err = db.PrepareContext(ctx, failChannelNotifications); err != nil {
+		return nil, fmt.Errorf("error preparing query FailChannelNotifications: %w", err)
+	}
+	if q.getAPITokenByHashStmt, err = db.PrepareContext(ctx, getAPITokenByHash); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAPITokenByHash: %w", err)
+	}
+	if q.getAPITokensStmt, err = db.PrepareContext(ctx, getAPITokens); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAPITokens: %w", err)
+	}
+	if q.getAlertStmt, err = db.PrepareContext(ctx, getAlert); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAlert: %w", err)
+	}
+	if q.getCheckBatchStmt, err = db.PrepareContext(ctx, getCheckBatch); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCheckBatch: %w", err)
+	}
+	if q.getCheckCountsStmt, err = db.PrepareContext(ctx, getCheckCounts); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCheckCounts: %w", err)
+	}
+	if q.getChecksStmt, err = db.PrepareContext(ctx, getChecks); err != nil {
+		return nil, fmt.Errorf("error preparing query GetChecks: %w", err)
+	}
+	if q.getConfirmedEmailSubscribersStmt, err = db.PrepareContext(ctx, getConfirmedEmailSubscribers); err != nil {
+		return nil, fmt.Errorf("error preparing query GetConfirmedEmailSubscribers: %w", err)
+	}
 	if q.getDataPointsStmt, err = db.PrepareContext(ctx, getDataPoints); err != nil {
 		return nil, fmt.Errorf("error preparing query GetDataPoints: %w", err)
 	}
+	if q.getDueNotificationsStmt, err = db.PrepareContext(ctx, getDueNotifications); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDueNotifications: %w", err)
+	}
+	if q.getEmailSubscriberStmt, err = db.PrepareContext(ctx, getEmailSubscriber); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEmailSubscriber: %w", err)
+	}
+	if q.getEmailSubscriberByAddressStmt, err = db.PrepareContext(ctx, getEmailSubscriberByAddress); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEmailSubscriberByAddress: %w", err)
+	}
+	if q.getEmailSubscribersByMonitorStmt, err = db.PrepareContext(ctx, getEmailSubscribersByMonitor); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEmailSubscribersByMonitor: %w", err)
+	}
+	if q.getEscalatingAlertsStmt, err = db.PrepareContext(ctx, getEscalatingAlerts); err != nil {
+		return nil, fmt.Errorf("error preparing query GetEscalatingAlerts: %w", err)
+	}
+	if q.getLatestCheckStmt, err = db.PrepareContext(ctx, getLatestCheck); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestCheck: %w", err)
+	}
 	if q.getMonitorStmt, err = db.PrepareContext(ctx, getMonitor); err != nil {
 		return nil, fmt.Errorf("error preparing query GetMonitor: %w", err)
 	}
+	if q.getMonitorParentsStmt, err = db.PrepareContext(ctx, getMonitorParents); err != nil {
+		return nil, fmt.Errorf("error preparing query GetMonitorParents: %w", err)
+	}
+	if q.getMonitorResponseTimesStmt, err = db.PrepareContext(ctx, getMonitorResponseTimes); err != nil {
+		return nil, fmt.Errorf("error preparing query GetMonitorResponseTimes: %w", err)
+	}
 	if q.getMonitorStatsStmt, err = db.PrepareContext(ctx, getMonitorStats); err != nil {
 		return nil, fmt.Errorf("error preparing query GetMonitorStats: %w", err)
 	}
 	if q.getMonitorsStmt, err = db.PrepareContext(ctx, getMonitors); err != nil {
 		return nil, fmt.Errorf("error preparing query GetMonitors: %w", err)
 	}
+	if q.getNotificationThreadStmt, err = db.PrepareContext(ctx, getNotificationThread); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNotificationThread: %w", err)
+	}
+	if q.getNotificationsStmt, err = db.PrepareContext(ctx, getNotifications); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNotifications: %w", err)
+	}
+	if q.getOpenAlertStmt, err = db.PrepareContext(ctx, getOpenAlert); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOpenAlert: %w", err)
+	}
+	if q.getOpenAlertsStmt, err = db.PrepareContext(ctx, getOpenAlerts); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOpenAlerts: %w", err)
+	}
+	if q.getOpenOutageStmt, err = db.PrepareContext(ctx, getOpenOutage); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOpenOutage: %w", err)
+	}
+	if q.getOutagesStmt, err = db.PrepareContext(ctx, getOutages); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOutages: %w", err)
+	}
+	if q.getPendingChannelsStmt, err = db.PrepareContext(ctx, getPendingChannels); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPendingChannels: %w", err)
+	}
+	if q.getPeriodOutagesStmt, err = db.PrepareContext(ctx, getPeriodOutages); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPeriodOutages: %w", err)
+	}
+	if q.getPushSubscriptionStmt, err = db.PrepareContext(ctx, getPushSubscription); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPushSubscription: %w", err)
+	}
+	if q.getPushSubscriptionsByEndpointStmt, err = db.PrepareContext(ctx, getPushSubscriptionsByEndpoint); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPushSubscriptionsByEndpoint: %w", err)
+	}
 	if q.getPushSubscriptionsByMonitorStmt, err = db.PrepareContext(ctx, getPushSubscriptionsByMonitor); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPushSubscriptionsByMonitor: %w", err)
 	}
+	if q.getRecentChecksStmt, err = db.PrepareContext(ctx, getRecentChecks); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRecentChecks: %w", err)
+	}
+	if q.getRecentOutagesStmt, err = db.PrepareContext(ctx, getRecentOutages); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRecentOutages: %w", err)
+	}
 	if q.getResponseTimesStmt, err = db.PrepareContext(ctx, getResponseTimes); err != nil {
 		return nil, fmt.Errorf("error preparing query GetResponseTimes: %w", err)
 	}
+	if q.getSessionStmt, err = db.PrepareContext(ctx, getSession); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSession: %w", err)
+	}
+	if q.getSettingStmt, err = db.PrepareContext(ctx, getSetting); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSetting: %w", err)
+	}
+	if q.getUserByUsernameStmt, err = db.PrepareContext(ctx, getUserByUsername); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserByUsername: %w", err)
+	}
+	if q.getUsersStmt, err = db.PrepareContext(ctx, getUsers); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUsers: %w", err)
+	}
 	if q.getVAPIDKeysStmt, err = db.PrepareContext(ctx, getVAPIDKeys); err != nil {
 		return nil, fmt.Errorf("error preparing query GetVAPIDKeys: %w", err)
 	}
+	if q.markNotificationFailedStmt, err = db.PrepareContext(ctx, markNotificationFailed); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkNotificationFailed: %w", err)
+	}
+	if q.markNotificationRetryStmt, err = db.PrepareContext(ctx, markNotificationRetry); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkNotificationRetry: %w", err)
+	}
+	if q.markNotificationSentStmt, err = db.PrepareContext(ctx, markNotificationSent); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkNotificationSent: %w", err)
+	}
+	if q.markPushSubscriptionsStaleStmt, err = db.PrepareContext(ctx, markPushSubscriptionsStale); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkPushSubscriptionsStale: %w", err)
+	}
+	if q.postponeNotificationStmt, err = db.PrepareContext(ctx, postponeNotification); err != nil {
+		return nil, fmt.Errorf("error preparing query PostponeNotification: %w", err)
+	}
+	if q.resolveAlertStmt, err = db.PrepareContext(ctx, resolveAlert); err != nil {
+		return nil, fmt.Errorf("error preparing query ResolveAlert: %w", err)
+	}
+	if q.setMonitorBudgetBurnStmt, err = db.PrepareContext(ctx, setMonitorBudgetBurn); err != nil {
+		return nil, fmt.Errorf("error preparing query SetMonitorBudgetBurn: %w", err)
+	}
+	if q.setMonitorFlappingStmt, err = db.PrepareContext(ctx, setMonitorFlapping); err != nil {
+		return nil, fmt.Errorf("error preparing query SetMonitorFlapping: %w", err)
+	}
+	if q.setNotificationThreadStmt, err = db.PrepareContext(ctx, setNotificationThread); err != nil {
+		return nil, fmt.Errorf("error preparing query SetNotificationThread: %w", err)
+	}
+	if q.snoozeAlertStmt, err = db.PrepareContext(ctx, snoozeAlert); err != nil {
+		return nil, fmt.Errorf("error preparing query SnoozeAlert: %w", err)
+	}
+	if q.touchAPITokenStmt, err = db.PrepareContext(ctx, touchAPIToken); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchAPIToken: %w", err)
+	}
+	if q.touchUserLoginStmt, err = db.PrepareContext(ctx, touchUserLogin); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchUserLogin: %w", err)
+	}
+	if q.updateAlertStepStmt, err = db.PrepareContext(ctx, updateAlertStep); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateAlertStep: %w", err)
+	}
 	if q.updateMonitorStmt, err = db.PrepareContext(ctx, updateMonitor); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateMonitor: %w", err)
 	}
+	if q.updateUserPasswordStmt, err = db.PrepareContext(ctx, updateUserPassword); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserPassword: %w", err)
+	}
+	if q.updateUserRoleStmt, err = db.PrepareContext(ctx, updateUserRole); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserRole: %w", err)
+	}
+	if q.updateVAPIDKeysStmt, err = db.PrepareContext(ctx, updateVAPIDKeys); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateVAPIDKeys: %w", err)
+	}
 	if q.vAPIDKeysExistStmt, err = db.PrepareContext(ctx, vAPIDKeysExist); err != nil {
 		return nil, fmt.Errorf("error preparing query VAPIDKeysExist: %w", err)
 	}
@@ -80,36 +302,141 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 
 func (q *Queries) Close() error {
 	var err error
+	if q.acknowledgeAlertStmt != nil {
+		if cerr := q.acknowledgeAlertStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing acknowledgeAlertStmt: %w", cerr)
+		}
+	}
+	if q.cleanupAlertsStmt != nil {
+		if cerr := q.cleanupAlertsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing cleanupAlertsStmt: %w", cerr)
+		}
+	}
 	if q.cleanupChecksStmt != nil {
 		if cerr := q.cleanupChecksStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing cleanupChecksStmt: %w", cerr)
 		}
 	}
+	if q.cleanupEmailSubscribersStmt != nil {
+		if cerr := q.cleanupEmailSubscribersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing cleanupEmailSubscribersStmt: %w", cerr)
+		}
+	}
+	if q.cleanupNotificationsStmt != nil {
+		if cerr := q.cleanupNotificationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing cleanupNotificationsStmt: %w", cerr)
+		}
+	}
+	if q.confirmEmailSubscriberStmt != nil {
+		if cerr := q.confirmEmailSubscriberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing confirmEmailSubscriberStmt: %w", cerr)
+		}
+	}
+	if q.countMonitorNotificationsStmt != nil {
+		if cerr := q.countMonitorNotificationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countMonitorNotificationsStmt: %w", cerr)
+		}
+	}
+	if q.countRecentNotificationsStmt != nil {
+		if cerr := q.countRecentNotificationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countRecentNotificationsStmt: %w", cerr)
+		}
+	}
+	if q.createAPITokenStmt != nil {
+		if cerr := q.createAPITokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAPITokenStmt: %w", cerr)
+		}
+	}
+	if q.createAlertStmt != nil {
+		if cerr := q.createAlertStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAlertStmt: %w", cerr)
+		}
+	}
 	if q.createCheckStmt != nil {
 		if cerr := q.createCheckStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createCheckStmt: %w", cerr)
 		}
 	}
+	if q.createEmailSubscriberStmt != nil {
+		if cerr := q.createEmailSubscriberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createEmailSubscriberStmt: %w", cerr)
+		}
+	}
 	if q.createMonitorStmt != nil {
 		if cerr := q.createMonitorStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createMonitorStmt: %w", cerr)
 		}
 	}
+	if q.createMonitorDependencyStmt != nil {
+		if cerr := q.createMonitorDependencyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createMonitorDependencyStmt: %w", cerr)
+		}
+	}
+	if q.createNotificationStmt != nil {
+		if cerr := q.createNotificationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createNotificationStmt: %w", cerr)
+		}
+	}
+	if q.createOutageStmt != nil {
+		if cerr := q.createOutageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createOutageStmt: %w", cerr)
+		}
+	}
 	if q.createPushSubscriptionStmt != nil {
 		if cerr := q.createPushSubscriptionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createPushSubscriptionStmt: %w", cerr)
 		}
 	}
+	if q.createSessionStmt != nil {
+		if cerr := q.createSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSessionStmt: %w", cerr)
+		}
+	}
+	if q.createSettingStmt != nil {
+		if cerr := q.createSettingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSettingStmt: %w", cerr)
+		}
+	}
+	if q.createUserStmt != nil {
+		if cerr := q.createUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUserStmt: %w", cerr)
+		}
+	}
 	if q.createVAPIDKeysStmt != nil {
 		if cerr := q.createVAPIDKeysStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createVAPIDKeysStmt: %w", cerr)
 		}
 	}
+	if q.deleteAPITokenStmt != nil {
+		if cerr := q.deleteAPITokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAPITokenStmt: %w", cerr)
+		}
+	}
+	if q.deleteEmailSubscribersByAddressStmt != nil {
+		if cerr := q.deleteEmailSubscribersByAddressStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteEmailSubscribersByAddressStmt: %w", cerr)
+		}
+	}
+	if q.deleteExpiredSessionsStmt != nil {
+		if cerr := q.deleteExpiredSessionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteExpiredSessionsStmt: %w", cerr)
+		}
+	}
 	if q.deleteMonitorStmt != nil {
 		if cerr := q.deleteMonitorStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteMonitorStmt: %w", cerr)
 		}
 	}
+	if q.deleteMonitorDependenciesStmt != nil {
+		if cerr := q.deleteMonitorDependenciesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteMonitorDependenciesStmt: %w", cerr)
+		}
+	}
+	if q.deleteMonitorOutagesStmt != nil {
+		if cerr := q.deleteMonitorOutagesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteMonitorOutagesStmt: %w", cerr)
+		}
+	}
 	if q.deletePushSubscriptionStmt != nil {
 		if cerr := q.deletePushSubscriptionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deletePushSubscriptionStmt: %w", cerr)
@@ -120,16 +447,121 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deletePushSubscriptionByEndpointStmt: %w", cerr)
 		}
 	}
+	if q.deleteSessionStmt != nil {
+		if cerr := q.deleteSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteSessionStmt: %w", cerr)
+		}
+	}
+	if q.deleteUserStmt != nil {
+		if cerr := q.deleteUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUserStmt: %w", cerr)
+		}
+	}
+	if q.deleteUserSessionsStmt != nil {
+		if cerr := q.deleteUserSessionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUserSessionsStmt: %w", cerr)
+		}
+	}
+	if q.endOutageStmt != nil {
+		if cerr := q.endOutageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing endOutageStmt: %w", cerr)
+		}
+	}
+	if q.extendOutageStmt != nil {
+		if cerr := q.extendOutageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing extendOutageStmt: %w", cerr)
+		}
+	}
+	if q.failChannelNotificationsStmt != nil {
+		if cerr := q.failChannelNotificationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing failChannelNotificationsStmt: %w", cerr)
+		}
+	}
+	if q.getAPITokenByHashStmt != nil {
+		if cerr := q.getAPITokenByHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAPITokenByHashStmt: %w", cerr)
+		}
+	}
+	if q.getAPITokensStmt != nil {
+		if cerr := q.getAPITokensStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAPITokensStmt: %w", cerr)
+		}
+	}
+	if q.getAlertStmt != nil {
+		if cerr := q.getAlertStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAlertStmt: %w", cerr)
+		}
+	}
+	if q.getCheckBatchStmt != nil {
+		if cerr := q.getCheckBatchStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCheckBatchStmt: %w", cerr)
+		}
+	}
+	if q.getCheckCountsStmt != nil {
+		if cerr := q.getCheckCountsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCheckCountsStmt: %w", cerr)
+		}
+	}
+	if q.getChecksStmt != nil {
+		if cerr := q.getChecksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getChecksStmt: %w", cerr)
+		}
+	}
+	if q.getConfirmedEmailSubscribersStmt != nil {
+		if cerr := q.getConfirmedEmailSubscribersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getConfirmedEmailSubscribersStmt: %w", cerr)
+		}
+	}
 	if q.getDataPointsStmt != nil {
 		if cerr := q.getDataPointsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getDataPointsStmt: %w", cerr)
 		}
 	}
+	if q.getDueNotificationsStmt != nil {
+		if cerr := q.getDueNotificationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDueNotificationsStmt: %w", cerr)
+		}
+	}
+	if q.getEmailSubscriberStmt != nil {
+		if cerr := q.getEmailSubscriberStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEmailSubscriberStmt: %w", cerr)
+		}
+	}
+	if q.getEmailSubscriberByAddressStmt != nil {
+		if cerr := q.getEmailSubscriberByAddressStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEmailSubscriberByAddressStmt: %w", cerr)
+		}
+	}
+	if q.getEmailSubscribersByMonitorStmt != nil {
+		if cerr := q.getEmailSubscribersByMonitorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEmailSubscribersByMonitorStmt: %w", cerr)
+		}
+	}
+	if q.getEscalatingAlertsStmt != nil {
+		if cerr := q.getEscalatingAlertsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getEscalatingAlertsStmt: %w", cerr)
+		}
+	}
+	if q.getLatestCheckStmt != nil {
+		if cerr := q.getLatestCheckStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestCheckStmt: %w", cerr)
+		}
+	}
 	if q.getMonitorStmt != nil {
 		if cerr := q.getMonitorStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getMonitorStmt: %w", cerr)
 		}
 	}
+	if q.getMonitorParentsStmt != nil {
+		if cerr := q.getMonitorParentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getMonitorParentsStmt: %w", cerr)
+		}
+	}
+	if q.getMonitorResponseTimesStmt != nil {
+		if cerr := q.getMonitorResponseTimesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getMonitorResponseTimesStmt: %w", cerr)
+		}
+	}
 	if q.getMonitorStatsStmt != nil {
 		if cerr := q.getMonitorStatsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getMonitorStatsStmt: %w", cerr)
@@ -140,26 +572,186 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getMonitorsStmt: %w", cerr)
 		}
 	}
+	if q.getNotificationThreadStmt != nil {
+		if cerr := q.getNotificationThreadStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNotificationThreadStmt: %w", cerr)
+		}
+	}
+	if q.getNotificationsStmt != nil {
+		if cerr := q.getNotificationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNotificationsStmt: %w", cerr)
+		}
+	}
+	if q.getOpenAlertStmt != nil {
+		if cerr := q.getOpenAlertStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOpenAlertStmt: %w", cerr)
+		}
+	}
+	if q.getOpenAlertsStmt != nil {
+		if cerr := q.getOpenAlertsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOpenAlertsStmt: %w", cerr)
+		}
+	}
+	if q.getOpenOutageStmt != nil {
+		if cerr := q.getOpenOutageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOpenOutageStmt: %w", cerr)
+		}
+	}
+	if q.getOutagesStmt != nil {
+		if cerr := q.getOutagesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOutagesStmt: %w", cerr)
+		}
+	}
+	if q.getPendingChannelsStmt != nil {
+		if cerr := q.getPendingChannelsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPendingChannelsStmt: %w", cerr)
+		}
+	}
+	if q.getPeriodOutagesStmt != nil {
+		if cerr := q.getPeriodOutagesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPeriodOutagesStmt: %w", cerr)
+		}
+	}
+	if q.getPushSubscriptionStmt != nil {
+		if cerr := q.getPushSubscriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPushSubscriptionStmt: %w", cerr)
+		}
+	}
+	if q.getPushSubscriptionsByEndpointStmt != nil {
+		if cerr := q.getPushSubscriptionsByEndpointStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPushSubscriptionsByEndpointStmt: %w", cerr)
+		}
+	}
 	if q.getPushSubscriptionsByMonitorStmt != nil {
 		if cerr := q.getPushSubscriptionsByMonitorStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getPushSubscriptionsByMonitorStmt: %w", cerr)
 		}
 	}
+	if q.getRecentChecksStmt != nil {
+		if cerr := q.getRecentChecksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRecentChecksStmt: %w", cerr)
+		}
+	}
+	if q.getRecentOutagesStmt != nil {
+		if cerr := q.getRecentOutagesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRecentOutagesStmt: %w", cerr)
+		}
+	}
 	if q.getResponseTimesStmt != nil {
 		if cerr := q.getResponseTimesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getResponseTimesStmt: %w", cerr)
 		}
 	}
+	if q.getSessionStmt != nil {
+		if cerr := q.getSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSessionStmt: %w", cerr)
+		}
+	}
+	if q.getSettingStmt != nil {
+		if cerr := q.getSettingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSettingStmt: %w", cerr)
+		}
+	}
+	if q.getUserByUsernameStmt != nil {
+		if cerr := q.getUserByUsernameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserByUsernameStmt: %w", cerr)
+		}
+	}
+	if q.getUsersStmt != nil {
+		if cerr := q.getUsersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUsersStmt: %w", cerr)
+		}
+	}
 	if q.getVAPIDKeysStmt != nil {
 		if cerr := q.getVAPIDKeysStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getVAPIDKeysStmt: %w", cerr)
 		}
 	}
+	if q.markNotificationFailedStmt != nil {
+		if cerr := q.markNotificationFailedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markNotificationFailedStmt: %w", cerr)
+		}
+	}
+	if q.markNotificationRetryStmt != nil {
+		if cerr := q.markNotificationRetryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markNotificationRetryStmt: %w", cerr)
+		}
+	}
+	if q.markNotificationSentStmt != nil {
+		if cerr := q.markNotificationSentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markNotificationSentStmt: %w", cerr)
+		}
+	}
+	if q.markPushSubscriptionsStaleStmt != nil {
+		if cerr := q.markPushSubscriptionsStaleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markPushSubscriptionsStaleStmt: %w", cerr)
+		}
+	}
+	if q.postponeNotificationStmt != nil {
+		if cerr := q.postponeNotificationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing postponeNotificationStmt: %w", cerr)
+		}
+	}
+	if q.resolveAlertStmt != nil {
+		if cerr := q.resolveAlertStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing resolveAlertStmt: %w", cerr)
+		}
+	}
+	if q.setMonitorBudgetBurnStmt != nil {
+		if cerr := q.setMonitorBudgetBurnStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setMonitorBudgetBurnStmt: %w", cerr)
+		}
+	}
+	if q.setMonitorFlappingStmt != nil {
+		if cerr := q.setMonitorFlappingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setMonitorFlappingStmt: %w", cerr)
+		}
+	}
+	if q.setNotificationThreadStmt != nil {
+		if cerr := q.setNotificationThreadStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setNotificationThreadStmt: %w", cerr)
+		}
+	}
+	if q.snoozeAlertStmt != nil {
+		if cerr := q.snoozeAlertStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing snoozeAlertStmt: %w", cerr)
+		}
+	}
+	if q.touchAPITokenStmt != nil {
+		if cerr := q.touchAPITokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchAPITokenStmt: %w", cerr)
+		}
+	}
+	if q.touchUserLoginStmt != nil {
+		if cerr := q.touchUserLoginStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchUserLoginStmt: %w", cerr)
+		}
+	}
+	if q.updateAlertStepStmt != nil {
+		if cerr := q.updateAlertStepStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateAlertStepStmt: %w", cerr)
+		}
+	}
 	if q.updateMonitorStmt != nil {
 		if cerr := q.updateMonitorStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateMonitorStmt: %w", cerr)
 		}
 	}
+	if q.updateUserPasswordStmt != nil {
+		if cerr := q.updateUserPasswordStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserPasswordStmt: %w", cerr)
+		}
+	}
+	if q.updateUserRoleStmt != nil {
+		if cerr := q.updateUserRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserRoleStmt: %w", cerr)
+		}
+	}
+	if q.updateVAPIDKeysStmt != nil {
+		if cerr := q.updateVAPIDKeysStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateVAPIDKeysStmt: %w", cerr)
+		}
+	}
 	if q.vAPIDKeysExistStmt != nil {
 		if cerr := q.vAPIDKeysExistStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing vAPIDKeysExistStmt: %w", cerr)
@@ -204,22 +796,96 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 type Queries struct {
 	db                                   DBTX
 	tx                                   *sql.Tx
+	acknowledgeAlertStmt                 *sql.Stmt
+	cleanupAlertsStmt                    *sql.Stmt
 	cleanupChecksStmt                    *sql.Stmt
+	cleanupEmailSubscribersStmt          *sql.Stmt
+	cleanupNotificationsStmt             *sql.Stmt
+	confirmEmailSubscriberStmt           *sql.Stmt
+	countMonitorNotificationsStmt        *sql.Stmt
+	countRecentNotificationsStmt         *sql.Stmt
+	createAPITokenStmt                   *sql.Stmt
+	createAlertStmt                      *sql.Stmt
 	createCheckStmt                      *sql.Stmt
+	createEmailSubscriberStmt            *sql.Stmt
 	createMonitorStmt                    *sql.Stmt
+	createMonitorDependencyStmt          *sql.Stmt
+	createNotificationStmt               *sql.Stmt
+	createOutageStmt                     *sql.Stmt
 	createPushSubscriptionStmt           *sql.Stmt
+	createSessionStmt                    *sql.Stmt
+	createSettingStmt                    *sql.Stmt
+	createUserStmt                       *sql.Stmt
 	createVAPIDKeysStmt                  *sql.Stmt
+	deleteAPITokenStmt                   *sql.Stmt
+	deleteEmailSubscribersByAddressStmt  *sql.Stmt
+	deleteExpiredSessionsStmt            *sql.Stmt
 	deleteMonitorStmt                    *sql.Stmt
+	deleteMonitorDependenciesStmt        *sql.Stmt
+	deleteMonitorOutagesStmt             *sql.Stmt
 	deletePushSubscriptionStmt           *sql.Stmt
 	deletePushSubscriptionByEndpointStmt *sql.Stmt
+	deleteSessionStmt                    *sql.Stmt
+	deleteUserStmt                       *sql.Stmt
+	deleteUserSessionsStmt               *sql.Stmt
+	endOutageStmt                        *sql.Stmt
+	extendOutageStmt                     *sql.Stmt
+	failChannelNotificationsStmt         *sql.Stmt
+	getAPITokenByHashStmt                *sql.Stmt
+	getAPITokensStmt                     *sql.Stmt
+	getAlertStmt                         *sql.Stmt
+	getCheckBatchStmt                    *sql.Stmt
+	getCheckCountsStmt                   *sql.Stmt
+	getChecksStmt                        *sql.Stmt
+	getConfirmedEmailSubscribersStmt     *sql.Stmt
 	getDataPointsStmt                    *sql.Stmt
+	getDueNotificationsStmt              *sql.Stmt
+	getEmailSubscriberStmt               *sql.Stmt
+	getEmailSubscriberByAddressStmt      *sql.Stmt
+	getEmailSubscribersByMonitorStmt     *sql.Stmt
+	getEscalatingAlertsStmt              *sql.Stmt
+	getLatestCheckStmt                   *sql.Stmt
 	getMonitorStmt                       *sql.Stmt
+	getMonitorParentsStmt                *sql.Stmt
+	getMonitorResponseTimesStmt          *sql.Stmt
 	getMonitorStatsStmt                  *sql.Stmt
 	getMonitorsStmt                      *sql.Stmt
+	getNotificationThreadStmt            *sql.Stmt
+	getNotificationsStmt                 *sql.Stmt
+	getOpenAlertStmt                     *sql.Stmt
+	getOpenAlertsStmt                    *sql.Stmt
+	getOpenOutageStmt                    *sql.Stmt
+	getOutagesStmt                       *sql.Stmt
+	getPendingChannelsStmt               *sql.Stmt
+	getPeriodOutagesStmt                 *sql.Stmt
+	getPushSubscriptionStmt              *sql.Stmt
+	getPushSubscriptionsByEndpointStmt   *sql.Stmt
 	getPushSubscriptionsByMonitorStmt    *sql.Stmt
+	getRecentChecksStmt                  *sql.Stmt
+	getRecentOutagesStmt                 *sql.Stmt
 	getResponseTimesStmt                 *sql.Stmt
+	getSessionStmt                       *sql.Stmt
+	getSettingStmt                       *sql.Stmt
+	getUserByUsernameStmt                *sql.Stmt
+	getUsersStmt                         *sql.Stmt
 	getVAPIDKeysStmt                     *sql.Stmt
+	markNotificationFailedStmt           *sql.Stmt
+	markNotificationRetryStmt            *sql.Stmt
+	markNotificationSentStmt             *sql.Stmt
+	markPushSubscriptionsStaleStmt       *sql.Stmt
+	postponeNotificationStmt             *sql.Stmt
+	resolveAlertStmt                     *sql.Stmt
+	setMonitorBudgetBurnStmt             *sql.Stmt
+	setMonitorFlappingStmt               *sql.Stmt
+	setNotificationThreadStmt            *sql.Stmt
+	snoozeAlertStmt                      *sql.Stmt
+	touchAPITokenStmt                    *sql.Stmt
+	touchUserLoginStmt                   *sql.Stmt
+	updateAlertStepStmt                  *sql.Stmt
 	updateMonitorStmt                    *sql.Stmt
+	updateUserPasswordStmt               *sql.Stmt
+	updateUserRoleStmt                   *sql.Stmt
+	updateVAPIDKeysStmt                  *sql.Stmt
 	vAPIDKeysExistStmt                   *sql.Stmt
 }
 
@@ -227,22 +893,96 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
 		db:                                   tx,
 		tx:                                   tx,
+		acknowledgeAlertStmt:                 q.acknowledgeAlertStmt,
+		cleanupAlertsStmt:                    q.cleanupAlertsStmt,
 		cleanupChecksStmt:                    q.cleanupChecksStmt,
+		cleanupEmailSubscribersStmt:          q.cleanupEmailSubscribersStmt,
+		cleanupNotificationsStmt:             q.cleanupNotificationsStmt,
+		confirmEmailSubscriberStmt:           q.confirmEmailSubscriberStmt,
+		countMonitorNotificationsStmt:        q.countMonitorNotificationsStmt,
+		countRecentNotificationsStmt:         q.countRecentNotificationsStmt,
+		createAPITokenStmt:                   q.createAPITokenStmt,
+		createAlertStmt:                      q.createAlertStmt,
 		createCheckStmt:                      q.createCheckStmt,
+		createEmailSubscriberStmt:            q.createEmailSubscriberStmt,
 		createMonitorStmt:                    q.createMonitorStmt,
+		createMonitorDependencyStmt:          q.createMonitorDependencyStmt,
+		createNotificationStmt:               q.createNotificationStmt,
+		createOutageStmt:                     q.createOutageStmt,
 		createPushSubscriptionStmt:           q.createPushSubscriptionStmt,
+		createSessionStmt:                    q.createSessionStmt,
+		createSettingStmt:                    q.createSettingStmt,
+		createUserStmt:                       q.createUserStmt,
 		createVAPIDKeysStmt:                  q.createVAPIDKeysStmt,
+		deleteAPITokenStmt:                   q.deleteAPITokenStmt,
+		deleteEmailSubscribersByAddressStmt:  q.deleteEmailSubscribersByAddressStmt,
+		deleteExpiredSessionsStmt:            q.deleteExpiredSessionsStmt,
 		deleteMonitorStmt:                    q.deleteMonitorStmt,
+		deleteMonitorDependenciesStmt:        q.deleteMonitorDependenciesStmt,
+		deleteMonitorOutagesStmt:             q.deleteMonitorOutagesStmt,
 		deletePushSubscriptionStmt:           q.deletePushSubscriptionStmt,
 		deletePushSubscriptionByEndpointStmt: q.deletePushSubscriptionByEndpointStmt,
+		deleteSessionStmt:                    q.deleteSessionStmt,
+		deleteUserStmt:                       q.deleteUserStmt,
+		deleteUserSessionsStmt:               q.deleteUserSessionsStmt,
+		endOutageStmt:                        q.endOutageStmt,
+		extendOutageStmt:                     q.extendOutageStmt,
+		failChannelNotificationsStmt:         q.failChannelNotificationsStmt,
+		getAPITokenByHashStmt:                q.getAPITokenByHashStmt,
+		getAPITokensStmt:                     q.getAPITokensStmt,
+		getAlertStmt:                         q.getAlertStmt,
+		getCheckBatchStmt:                    q.getCheckBatchStmt,
+		getCheckCountsStmt:                   q.getCheckCountsStmt,
+		getChecksStmt:                        q.getChecksStmt,
+		getConfirmedEmailSubscribersStmt:     q.getConfirmedEmailSubscribersStmt,
 		getDataPointsStmt:                    q.getDataPointsStmt,
+		getDueNotificationsStmt:              q.getDueNotificationsStmt,
+		getEmailSubscriberStmt:               q.getEmailSubscriberStmt,
+		getEmailSubscriberByAddressStmt:      q.getEmailSubscriberByAddressStmt,
+		getEmailSubscribersByMonitorStmt:     q.getEmailSubscribersByMonitorStmt,
+		getEscalatingAlertsStmt:              q.getEscalatingAlertsStmt,
+		getLatestCheckStmt:                   q.getLatestCheckStmt,
 		getMonitorStmt:                       q.getMonitorStmt,
+		getMonitorParentsStmt:                q.getMonitorParentsStmt,
+		getMonitorResponseTimesStmt:          q.getMonitorResponseTimesStmt,
 		getMonitorStatsStmt:                  q.getMonitorStatsStmt,
 		getMonitorsStmt:                      q.getMonitorsStmt,
+		getNotificationThreadStmt:            q.getNotificationThreadStmt,
+		getNotificationsStmt:                 q.getNotificationsStmt,
+		getOpenAlertStmt:                     q.getOpenAlertStmt,
+		getOpenAlertsStmt:                    q.getOpenAlertsStmt,
+		getOpenOutageStmt:                    q.getOpenOutageStmt,
+		getOutagesStmt:                       q.getOutagesStmt,
+		getPendingChannelsStmt:               q.getPendingChannelsStmt,
+		getPeriodOutagesStmt:                 q.getPeriodOutagesStmt,
+		getPushSubscriptionStmt:              q.getPushSubscriptionStmt,
+		getPushSubscriptionsByEndpointStmt:   q.getPushSubscriptionsByEndpointStmt,
 		getPushSubscriptionsByMonitorStmt:    q.getPushSubscriptionsByMonitorStmt,
+		getRecentChecksStmt:                  q.getRecentChecksStmt,
+		getRecentOutagesStmt:                 q.getRecentOutagesStmt,
 		getResponseTimesStmt:                 q.getResponseTimesStmt,
+		getSessionStmt:                       q.getSessionStmt,
+		getSettingStmt:                       q.getSettingStmt,
+		getUserByUsernameStmt:                q.getUserByUsernameStmt,
+		getUsersStmt:                         q.getUsersStmt,
 		getVAPIDKeysStmt:                     q.getVAPIDKeysStmt,
+		markNotificationFailedStmt:           q.markNotificationFailedStmt,
+		markNotificationRetryStmt:            q.markNotificationRetryStmt,
+		markNotificationSentStmt:             q.markNotificationSentStmt,
+		markPushSubscriptionsStaleStmt:       q.markPushSubscriptionsStaleStmt,
+		postponeNotificationStmt:             q.postponeNotificationStmt,
+		resolveAlertStmt:                     q.resolveAlertStmt,
+		setMonitorBudgetBurnStmt:             q.setMonitorBudgetBurnStmt,
+		setMonitorFlappingStmt:               q.setMonitorFlappingStmt,
+		setNotificationThreadStmt:            q.setNotificationThreadStmt,
+		snoozeAlertStmt:                      q.snoozeAlertStmt,
+		touchAPITokenStmt:                    q.touchAPITokenStmt,
+		touchUserLoginStmt:                   q.touchUserLoginStmt,
+		updateAlertStepStmt:                  q.updateAlertStepStmt,
 		updateMonitorStmt:                    q.updateMonitorStmt,
+		updateUserPasswordStmt:               q.updateUserPasswordStmt,
+		updateUserRoleStmt:                   q.updateUserRoleStmt,
+		updateVAPIDKeysStmt:                  q.updateVAPIDKeysStmt,
 		vAPIDKeysExistStmt:                   q.vAPIDKeysExistStmt,
 	}
 }
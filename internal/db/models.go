@@ -8,36 +8,135 @@ import (
 	"time"
 )
 
+type Alert struct {
+	ID             int64      `json:"id"`
+	MonitorID      int64      `json:"monitorId"`
+	Policy         string     `json:"policy"`
+	Step           int64      `json:"step"`
+	Reason         string     `json:"reason"`
+	StartedAt      time.Time  `json:"startedAt"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt"`
+	AcknowledgedBy *string    `json:"acknowledgedBy"`
+	SnoozedUntil   *time.Time `json:"snoozedUntil"`
+	SnoozedBy      *string    `json:"snoozedBy"`
+	ResolvedAt     *time.Time `json:"resolvedAt"`
+}
+
+type Outage struct {
+	ID          int64      `json:"id"`
+	MonitorID   int64      `json:"monitorId"`
+	StartedAt   time.Time  `json:"startedAt"`
+	EndedAt     *time.Time `json:"endedAt"`
+	FirstError  string     `json:"firstError"`
+	StatusCodes string     `json:"statusCodes"`
+	Checks      int64      `json:"checks"`
+}
+
 type Check struct {
-	MonitorID    int64     `json:"monitorId"`
-	StatusCode   int64     `json:"statusCode"`
-	ResponseTime int64     `json:"responseTime"`
-	Error        *string   `json:"error"`
-	IsUp         bool      `json:"isUp"`
-	CheckedAt    time.Time `json:"checkedAt"`
+	MonitorID     int64      `json:"monitorId"`
+	StatusCode    int64      `json:"statusCode"`
+	ResponseTime  int64      `json:"responseTime"`
+	Error         *string    `json:"error"`
+	IsUp          bool       `json:"isUp"`
+	State         *string    `json:"state"`
+	CheckedAt     time.Time  `json:"checkedAt"`
+	CertExpiresAt *time.Time `json:"certExpiresAt"`
 }
 
 type Monitor struct {
-	ID            int64     `json:"id"`
-	Name          string    `json:"name"`
-	Url           string    `json:"url"`
-	CheckInterval int64     `json:"checkInterval"`
-	CreatedAt     time.Time `json:"createdAt"`
-	UpdatedAt     time.Time `json:"updatedAt"`
+	ID                 int64     `json:"id"`
+	Name               string    `json:"name"`
+	Url                string    `json:"url"`
+	CheckInterval      int64     `json:"checkInterval"`
+	GroupName          string    `json:"groupName"`
+	DegradedThreshold  int64     `json:"degradedThreshold"`
+	DegradedPercentile int64     `json:"degradedPercentile"`
+	DegradedWindow     int64     `json:"degradedWindow"`
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+	Source             string    `json:"source"`
+	Public             bool      `json:"public"`
+	Flapping           bool      `json:"flapping"`
+	BudgetBurn         float64   `json:"budgetBurn"`
+}
+
+type MonitorDependency struct {
+	MonitorID int64 `json:"monitorId"`
+	ParentID  int64 `json:"parentId"`
+}
+
+type Notification struct {
+	ID            int64      `json:"id"`
+	MonitorID     *int64     `json:"monitorId"`
+	Channel       string     `json:"channel"`
+	Target        string     `json:"target"`
+	EventType     string     `json:"eventType"`
+	Payload       string     `json:"payload"`
+	Status        string     `json:"status"`
+	Attempts      int64      `json:"attempts"`
+	LastError     *string    `json:"lastError"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	SentAt        *time.Time `json:"sentAt"`
+	Thread        string     `json:"thread"`
 }
 
 type PushSubscription struct {
 	ID        int64     `json:"id"`
-	MonitorID int64     `json:"monitorId"`
+	MonitorID *int64    `json:"monitorId"`
+	GroupName *string   `json:"groupName"`
 	Endpoint  string    `json:"endpoint"`
 	P256dhKey string    `json:"p256dhKey"`
 	AuthKey   string    `json:"authKey"`
+	Stale     bool      `json:"stale"`
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+type EmailSubscriber struct {
+	ID          int64      `json:"id"`
+	Email       string     `json:"email"`
+	MonitorID   *int64     `json:"monitorId"`
+	ConfirmedAt *time.Time `json:"confirmedAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
 type VapidKey struct {
 	ID         int64     `json:"id"`
 	PublicKey  string    `json:"publicKey"`
 	PrivateKey string    `json:"privateKey"`
 	CreatedAt  time.Time `json:"createdAt"`
 }
+
+type ApiToken struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"tokenHash"`
+	Scope      string     `json:"scope"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type User struct {
+	ID           int64      `json:"id"`
+	Username     string     `json:"username"`
+	PasswordHash string     `json:"passwordHash"`
+	Role         string     `json:"role"`
+	LastLoginAt  *time.Time `json:"lastLoginAt"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+type Session struct {
+	ID        int64     `json:"id"`
+	UserID    *int64    `json:"userId"`
+	Username  string    `json:"username"`
+	Scope     string    `json:"scope"`
+	TokenHash string    `json:"tokenHash"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Setting struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"createdAt"`
+}
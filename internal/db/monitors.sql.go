@@ -11,31 +11,82 @@ import (
 
 const createMonitor = `-- name: CreateMonitor :one
 INSERT INTO
-  monitors (name, url, check_interval)
+  monitors (
+    name,
+    url,
+    check_interval,
+    group_name,
+    degraded_threshold,
+    degraded_percentile,
+    degraded_window,
+    source,
+    public
+  )
 VALUES
-  (?, ?, ?) RETURNING id, name, url, check_interval, created_at, updated_at
+  (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id, name, url, check_interval, group_name, degraded_threshold, degraded_percentile, degraded_window, created_at, updated_at, source, public, flapping, budget_burn
 `
 
 type CreateMonitorParams struct {
-	Name          string `json:"name"`
-	Url           string `json:"url"`
-	CheckInterval int64  `json:"checkInterval"`
+	Name               string `json:"name"`
+	Url                string `json:"url"`
+	CheckInterval      int64  `json:"checkInterval"`
+	GroupName          string `json:"groupName"`
+	DegradedThreshold  int64  `json:"degradedThreshold"`
+	DegradedPercentile int64  `json:"degradedPercentile"`
+	DegradedWindow     int64  `json:"degradedWindow"`
+	Source             string `json:"source"`
+	Public             bool   `json:"public"`
 }
 
 func (q *Queries) CreateMonitor(ctx context.Context, arg *CreateMonitorParams) (*Monitor, error) {
-	row := q.queryRow(ctx, q.createMonitorStmt, createMonitor, arg.Name, arg.Url, arg.CheckInterval)
+	row := q.queryRow(ctx, q.createMonitorStmt, createMonitor,
+		arg.Name,
+		arg.Url,
+		arg.CheckInterval,
+		arg.GroupName,
+		arg.DegradedThreshold,
+		arg.DegradedPercentile,
+		arg.DegradedWindow,
+		arg.Source,
+		arg.Public,
+	)
 	var i Monitor
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.Url,
 		&i.CheckInterval,
+		&i.GroupName,
+		&i.DegradedThreshold,
+		&i.DegradedPercentile,
+		&i.DegradedWindow,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Source,
+		&i.Public,
+		&i.Flapping,
+		&i.BudgetBurn,
 	)
 	return &i, err
 }
 
+const createMonitorDependency = `-- name: CreateMonitorDependency :exec
+INSERT INTO
+  monitor_dependencies (monitor_id, parent_id)
+VALUES
+  (?, ?)
+`
+
+type CreateMonitorDependencyParams struct {
+	MonitorID int64 `json:"monitorId"`
+	ParentID  int64 `json:"parentId"`
+}
+
+func (q *Queries) CreateMonitorDependency(ctx context.Context, arg *CreateMonitorDependencyParams) error {
+	_, err := q.exec(ctx, q.createMonitorDependencyStmt, createMonitorDependency, arg.MonitorID, arg.ParentID)
+	return err
+}
+
 const deleteMonitor = `-- name: DeleteMonitor :exec
 DELETE FROM monitors
 WHERE
@@ -47,9 +98,20 @@ func (q *Queries) DeleteMonitor(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteMonitorDependencies = `-- name: DeleteMonitorDependencies :exec
+DELETE FROM monitor_dependencies
+WHERE
+  monitor_id = ?
+`
+
+func (q *Queries) DeleteMonitorDependencies(ctx context.Context, monitorID int64) error {
+	_, err := q.exec(ctx, q.deleteMonitorDependenciesStmt, deleteMonitorDependencies, monitorID)
+	return err
+}
+
 const getMonitor = `-- name: GetMonitor :one
 SELECT
-  id, name, url, check_interval, created_at, updated_at
+  id, name, url, check_interval, group_name, degraded_threshold, degraded_percentile, degraded_window, created_at, updated_at, source, public, flapping, budget_burn
 FROM
   monitors
 WHERE
@@ -64,15 +126,71 @@ func (q *Queries) GetMonitor(ctx context.Context, id int64) (*Monitor, error) {
 		&i.Name,
 		&i.Url,
 		&i.CheckInterval,
+		&i.GroupName,
+		&i.DegradedThreshold,
+		&i.DegradedPercentile,
+		&i.DegradedWindow,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Source,
+		&i.Public,
+		&i.Flapping,
+		&i.BudgetBurn,
 	)
 	return &i, err
 }
 
+const getMonitorParents = `-- name: GetMonitorParents :many
+SELECT
+  m.id, m.name, m.url, m.check_interval, m.group_name, m.degraded_threshold, m.degraded_percentile, m.degraded_window, m.created_at, m.updated_at, m.source, m.public, m.flapping, m.budget_burn
+FROM
+  monitors m
+  JOIN monitor_dependencies d ON d.parent_id = m.id
+WHERE
+  d.monitor_id = ?
+`
+
+func (q *Queries) GetMonitorParents(ctx context.Context, monitorID int64) ([]*Monitor, error) {
+	rows, err := q.query(ctx, q.getMonitorParentsStmt, getMonitorParents, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Monitor
+	for rows.Next() {
+		var i Monitor
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			&i.CheckInterval,
+			&i.GroupName,
+			&i.DegradedThreshold,
+			&i.DegradedPercentile,
+			&i.DegradedWindow,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Source,
+			&i.Public,
+			&i.Flapping,
+			&i.BudgetBurn,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getMonitors = `-- name: GetMonitors :many
 SELECT
-  id, name, url, check_interval, created_at, updated_at
+  id, name, url, check_interval, group_name, degraded_threshold, degraded_percentile, degraded_window, created_at, updated_at, source, public, flapping, budget_burn
 FROM
   monitors
 `
@@ -91,8 +209,16 @@ func (q *Queries) GetMonitors(ctx context.Context) ([]*Monitor, error) {
 			&i.Name,
 			&i.Url,
 			&i.CheckInterval,
+			&i.GroupName,
+			&i.DegradedThreshold,
+			&i.DegradedPercentile,
+			&i.DegradedWindow,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Source,
+			&i.Public,
+			&i.Flapping,
+			&i.BudgetBurn,
 		); err != nil {
 			return nil, err
 		}
@@ -107,21 +233,70 @@ func (q *Queries) GetMonitors(ctx context.Context) ([]*Monitor, error) {
 	return items, nil
 }
 
+const setMonitorBudgetBurn = `-- name: SetMonitorBudgetBurn :exec
+UPDATE monitors
+SET
+  budget_burn = ?
+WHERE
+  id = ?
+`
+
+type SetMonitorBudgetBurnParams struct {
+	BudgetBurn float64 `json:"budgetBurn"`
+	ID         int64   `json:"id"`
+}
+
+func (q *Queries) SetMonitorBudgetBurn(ctx context.Context, arg *SetMonitorBudgetBurnParams) error {
+	_, err := q.exec(ctx, q.setMonitorBudgetBurnStmt, setMonitorBudgetBurn, arg.BudgetBurn, arg.ID)
+	return err
+}
+
+const setMonitorFlapping = `-- name: SetMonitorFlapping :exec
+UPDATE monitors
+SET
+  flapping = ?
+WHERE
+  id = ?
+`
+
+type SetMonitorFlappingParams struct {
+	Flapping bool  `json:"flapping"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) SetMonitorFlapping(ctx context.Context, arg *SetMonitorFlappingParams) error {
+	_, err := q.exec(ctx, q.setMonitorFlappingStmt, setMonitorFlapping, arg.Flapping, arg.ID)
+	return err
+}
+
 const updateMonitor = `-- name: UpdateMonitor :one
 UPDATE monitors
 SET
   name = COALESCE(?, name),
   url = COALESCE(?, url),
-  check_interval = COALESCE(?, check_interval)
+  check_interval = COALESCE(?, check_interval),
+  group_name = COALESCE(?, group_name),
+  degraded_threshold = COALESCE(?, degraded_threshold),
+  degraded_percentile = COALESCE(?, degraded_percentile),
+  degraded_window = COALESCE(?, degraded_window),
+  source = COALESCE(?, source),
+  public = COALESCE(?, public),
+  updated_at = CURRENT_TIMESTAMP
 WHERE
-  id = ? RETURNING id, name, url, check_interval, created_at, updated_at
+  id = ? RETURNING id, name, url, check_interval, group_name, degraded_threshold, degraded_percentile, degraded_window, created_at, updated_at, source, public, flapping, budget_burn
 `
 
 type UpdateMonitorParams struct {
-	Name          string `json:"name"`
-	Url           string `json:"url"`
-	CheckInterval int64  `json:"checkInterval"`
-	ID            int64  `json:"id"`
+	Name               string `json:"name"`
+	Url                string `json:"url"`
+	CheckInterval      int64  `json:"checkInterval"`
+	GroupName          string `json:"groupName"`
+	DegradedThreshold  int64  `json:"degradedThreshold"`
+	DegradedPercentile int64  `json:"degradedPercentile"`
+	DegradedWindow     int64  `json:"degradedWindow"`
+	Source             string `json:"source"`
+	Public             bool   `json:"public"`
+	ID                 int64  `json:"id"`
 }
 
 func (q *Queries) UpdateMonitor(ctx context.Context, arg *UpdateMonitorParams) (*Monitor, error) {
@@ -129,6 +304,12 @@ func (q *Queries) UpdateMonitor(ctx context.Context, arg *UpdateMonitorParams) (
 		arg.Name,
 		arg.Url,
 		arg.CheckInterval,
+		arg.GroupName,
+		arg.DegradedThreshold,
+		arg.DegradedPercentile,
+		arg.DegradedWindow,
+		arg.Source,
+		arg.Public,
 		arg.ID,
 	)
 	var i Monitor
@@ -137,8 +318,16 @@ func (q *Queries) UpdateMonitor(ctx context.Context, arg *UpdateMonitorParams) (
 		&i.Name,
 		&i.Url,
 		&i.CheckInterval,
+		&i.GroupName,
+		&i.DegradedThreshold,
+		&i.DegradedPercentile,
+		&i.DegradedWindow,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Source,
+		&i.Public,
+		&i.Flapping,
+		&i.BudgetBurn,
 	)
 	return &i, err
 }
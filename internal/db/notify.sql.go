@@ -9,23 +9,82 @@ import (
 	"context"
 )
 
+const cleanupEmailSubscribers = `-- name: CleanupEmailSubscribers :exec
+DELETE FROM email_subscribers
+WHERE
+  confirmed_at IS NULL
+  AND created_at < datetime('now', '-7 days')
+`
+
+func (q *Queries) CleanupEmailSubscribers(ctx context.Context) error {
+	_, err := q.exec(ctx, q.cleanupEmailSubscribersStmt, cleanupEmailSubscribers)
+	return err
+}
+
+const confirmEmailSubscriber = `-- name: ConfirmEmailSubscriber :exec
+UPDATE email_subscribers
+SET
+  confirmed_at = CURRENT_TIMESTAMP
+WHERE
+  id = ?
+  AND confirmed_at IS NULL
+`
+
+func (q *Queries) ConfirmEmailSubscriber(ctx context.Context, id int64) error {
+	_, err := q.exec(ctx, q.confirmEmailSubscriberStmt, confirmEmailSubscriber, id)
+	return err
+}
+
+const createEmailSubscriber = `-- name: CreateEmailSubscriber :one
+INSERT INTO
+  email_subscribers (email, monitor_id)
+VALUES
+  (?, ?) RETURNING id, email, monitor_id, confirmed_at, created_at
+`
+
+type CreateEmailSubscriberParams struct {
+	Email     string `json:"email"`
+	MonitorID *int64 `json:"monitorId"`
+}
+
+func (q *Queries) CreateEmailSubscriber(ctx context.Context, arg *CreateEmailSubscriberParams) (*EmailSubscriber, error) {
+	row := q.queryRow(ctx, q.createEmailSubscriberStmt, createEmailSubscriber, arg.Email, arg.MonitorID)
+	var i EmailSubscriber
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.MonitorID,
+		&i.ConfirmedAt,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
 const createPushSubscription = `-- name: CreatePushSubscription :exec
 INSERT INTO
-  push_subscriptions (monitor_id, endpoint, p256dh_key, auth_key)
+  push_subscriptions (
+    monitor_id,
+    group_name,
+    endpoint,
+    p256dh_key,
+    auth_key
+  )
 VALUES
-  (?, ?, ?, ?)
+  (?, ?, ?, ?, ?)
 `
 
 type CreatePushSubscriptionParams struct {
-	MonitorID int64  `json:"monitorId"`
-	Endpoint  string `json:"endpoint"`
-	P256dhKey string `json:"p256dhKey"`
-	AuthKey   string `json:"authKey"`
+	MonitorID *int64  `json:"monitorId"`
+	GroupName *string `json:"groupName"`
+	Endpoint  string  `json:"endpoint"`
+	P256dhKey string  `json:"p256dhKey"`
+	AuthKey   string  `json:"authKey"`
 }
 
 func (q *Queries) CreatePushSubscription(ctx context.Context, arg *CreatePushSubscriptionParams) error {
 	_, err := q.exec(ctx, q.createPushSubscriptionStmt, createPushSubscription,
 		arg.MonitorID,
+		arg.GroupName,
 		arg.Endpoint,
 		arg.P256dhKey,
 		arg.AuthKey,
@@ -50,6 +109,17 @@ func (q *Queries) CreateVAPIDKeys(ctx context.Context, arg *CreateVAPIDKeysParam
 	return err
 }
 
+const deleteEmailSubscribersByAddress = `-- name: DeleteEmailSubscribersByAddress :exec
+DELETE FROM email_subscribers
+WHERE
+  email = ?
+`
+
+func (q *Queries) DeleteEmailSubscribersByAddress(ctx context.Context, email string) error {
+	_, err := q.exec(ctx, q.deleteEmailSubscribersByAddressStmt, deleteEmailSubscribersByAddress, email)
+	return err
+}
+
 const deletePushSubscription = `-- name: DeletePushSubscription :exec
 DELETE FROM push_subscriptions
 WHERE
@@ -59,7 +129,7 @@ WHERE
 
 type DeletePushSubscriptionParams struct {
 	Endpoint  string `json:"endpoint"`
-	MonitorID int64  `json:"monitorId"`
+	MonitorID *int64 `json:"monitorId"`
 }
 
 func (q *Queries) DeletePushSubscription(ctx context.Context, arg *DeletePushSubscriptionParams) error {
@@ -78,22 +148,259 @@ func (q *Queries) DeletePushSubscriptionByEndpoint(ctx context.Context, endpoint
 	return err
 }
 
+const getConfirmedEmailSubscribers = `-- name: GetConfirmedEmailSubscribers :many
+SELECT
+  s.email,
+  m.name AS monitor_name
+FROM
+  email_subscribers s
+  LEFT JOIN monitors m ON m.id = s.monitor_id
+WHERE
+  s.confirmed_at IS NOT NULL
+`
+
+type GetConfirmedEmailSubscribersRow struct {
+	Email       string  `json:"email"`
+	MonitorName *string `json:"monitorName"`
+}
+
+func (q *Queries) GetConfirmedEmailSubscribers(ctx context.Context) ([]*GetConfirmedEmailSubscribersRow, error) {
+	rows, err := q.query(ctx, q.getConfirmedEmailSubscribersStmt, getConfirmedEmailSubscribers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*GetConfirmedEmailSubscribersRow
+	for rows.Next() {
+		var i GetConfirmedEmailSubscribersRow
+		if err := rows.Scan(
+			&i.Email,
+			&i.MonitorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEmailSubscriber = `-- name: GetEmailSubscriber :one
+SELECT
+  id, email, monitor_id, confirmed_at, created_at
+FROM
+  email_subscribers
+WHERE
+  id = ?
+`
+
+func (q *Queries) GetEmailSubscriber(ctx context.Context, id int64) (*EmailSubscriber, error) {
+	row := q.queryRow(ctx, q.getEmailSubscriberStmt, getEmailSubscriber, id)
+	var i EmailSubscriber
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.MonitorID,
+		&i.ConfirmedAt,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
+const getEmailSubscriberByAddress = `-- name: GetEmailSubscriberByAddress :one
+SELECT
+  id, email, monitor_id, confirmed_at, created_at
+FROM
+  email_subscribers
+WHERE
+  email = ?
+  AND monitor_id IS ?
+`
+
+type GetEmailSubscriberByAddressParams struct {
+	Email     string `json:"email"`
+	MonitorID *int64 `json:"monitorId"`
+}
+
+func (q *Queries) GetEmailSubscriberByAddress(ctx context.Context, arg *GetEmailSubscriberByAddressParams) (*EmailSubscriber, error) {
+	row := q.queryRow(ctx, q.getEmailSubscriberByAddressStmt, getEmailSubscriberByAddress, arg.Email, arg.MonitorID)
+	var i EmailSubscriber
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.MonitorID,
+		&i.ConfirmedAt,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
+const getEmailSubscribersByMonitor = `-- name: GetEmailSubscribersByMonitor :many
+SELECT DISTINCT
+  email
+FROM
+  email_subscribers
+WHERE
+  confirmed_at IS NOT NULL
+  AND (
+    monitor_id = ?1
+    OR (
+      monitor_id IS NULL
+      AND EXISTS (
+        SELECT
+          1
+        FROM
+          monitors
+        WHERE
+          id = ?1
+          AND public
+      )
+    )
+  )
+`
+
+func (q *Queries) GetEmailSubscribersByMonitor(ctx context.Context, monitorID *int64) ([]string, error) {
+	rows, err := q.query(ctx, q.getEmailSubscribersByMonitorStmt, getEmailSubscribersByMonitor, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		items = append(items, email)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPushSubscription = `-- name: GetPushSubscription :one
+SELECT
+  id,
+  monitor_id,
+  group_name,
+  endpoint,
+  p256dh_key,
+  auth_key,
+  stale,
+  created_at
+FROM
+  push_subscriptions
+WHERE
+  endpoint = ?
+ORDER BY
+  id DESC
+LIMIT
+  1
+`
+
+func (q *Queries) GetPushSubscription(ctx context.Context, endpoint string) (*PushSubscription, error) {
+	row := q.queryRow(ctx, q.getPushSubscriptionStmt, getPushSubscription, endpoint)
+	var i PushSubscription
+	err := row.Scan(
+		&i.ID,
+		&i.MonitorID,
+		&i.GroupName,
+		&i.Endpoint,
+		&i.P256dhKey,
+		&i.AuthKey,
+		&i.Stale,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
+const getPushSubscriptionsByEndpoint = `-- name: GetPushSubscriptionsByEndpoint :many
+SELECT
+  id,
+  monitor_id,
+  group_name,
+  endpoint,
+  p256dh_key,
+  auth_key,
+  stale,
+  created_at
+FROM
+  push_subscriptions
+WHERE
+  endpoint = ?
+ORDER BY
+  id
+`
+
+func (q *Queries) GetPushSubscriptionsByEndpoint(ctx context.Context, endpoint string) ([]*PushSubscription, error) {
+	rows, err := q.query(ctx, q.getPushSubscriptionsByEndpointStmt, getPushSubscriptionsByEndpoint, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*PushSubscription
+	for rows.Next() {
+		var i PushSubscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.MonitorID,
+			&i.GroupName,
+			&i.Endpoint,
+			&i.P256dhKey,
+			&i.AuthKey,
+			&i.Stale,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPushSubscriptionsByMonitor = `-- name: GetPushSubscriptionsByMonitor :many
 SELECT
   id,
   monitor_id,
+  group_name,
   endpoint,
   p256dh_key,
   auth_key,
+  stale,
   created_at
 FROM
   push_subscriptions
 WHERE
-  monitor_id = ?
+  NOT stale
+  AND (
+    monitor_id = ?1
+    OR group_name = '*'
+    OR group_name = ?2
+  )
 `
 
-func (q *Queries) GetPushSubscriptionsByMonitor(ctx context.Context, monitorID int64) ([]*PushSubscription, error) {
-	rows, err := q.query(ctx, q.getPushSubscriptionsByMonitorStmt, getPushSubscriptionsByMonitor, monitorID)
+type GetPushSubscriptionsByMonitorParams struct {
+	MonitorID *int64  `json:"monitorId"`
+	GroupName *string `json:"groupName"`
+}
+
+func (q *Queries) GetPushSubscriptionsByMonitor(ctx context.Context, arg *GetPushSubscriptionsByMonitorParams) ([]*PushSubscription, error) {
+	rows, err := q.query(ctx, q.getPushSubscriptionsByMonitorStmt, getPushSubscriptionsByMonitor, arg.MonitorID, arg.GroupName)
 	if err != nil {
 		return nil, err
 	}
@@ -104,9 +411,11 @@ func (q *Queries) GetPushSubscriptionsByMonitor(ctx context.Context, monitorID i
 		if err := rows.Scan(
 			&i.ID,
 			&i.MonitorID,
+			&i.GroupName,
 			&i.Endpoint,
 			&i.P256dhKey,
 			&i.AuthKey,
+			&i.Stale,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -146,6 +455,37 @@ func (q *Queries) GetVAPIDKeys(ctx context.Context) (*VapidKey, error) {
 	return &i, err
 }
 
+const markPushSubscriptionsStale = `-- name: MarkPushSubscriptionsStale :exec
+UPDATE push_subscriptions
+SET
+  stale = TRUE
+`
+
+func (q *Queries) MarkPushSubscriptionsStale(ctx context.Context) error {
+	_, err := q.exec(ctx, q.markPushSubscriptionsStaleStmt, markPushSubscriptionsStale)
+	return err
+}
+
+const updateVAPIDKeys = `-- name: UpdateVAPIDKeys :exec
+UPDATE vapid_keys
+SET
+  public_key = ?,
+  private_key = ?,
+  created_at = CURRENT_TIMESTAMP
+WHERE
+  id = 1
+`
+
+type UpdateVAPIDKeysParams struct {
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey"`
+}
+
+func (q *Queries) UpdateVAPIDKeys(ctx context.Context, arg *UpdateVAPIDKeysParams) error {
+	_, err := q.exec(ctx, q.updateVAPIDKeysStmt, updateVAPIDKeys, arg.PublicKey, arg.PrivateKey)
+	return err
+}
+
 const vAPIDKeysExist = `-- name: VAPIDKeysExist :one
 SELECT
   COUNT(*) as count
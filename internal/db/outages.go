@@ -0,0 +1,21 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// SettingOutagesDerived is the setting marking that outages were derived from
+// the checks stored before outages were recorded
+const SettingOutagesDerived = "outages_derived"
+
+// OutagesDerived reports whether the outages of the checks stored before
+// outages were recorded have been derived, so all outages are recorded
+func (q *Queries) OutagesDerived(ctx context.Context) (bool, error) {
+	_, err := q.GetSetting(ctx, SettingOutagesDerived)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
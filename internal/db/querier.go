@@ -10,22 +10,96 @@ import (
 )
 
 type Querier interface {
+	AcknowledgeAlert(ctx context.Context, arg *AcknowledgeAlertParams) (*Alert, error)
+	CleanupAlerts(ctx context.Context, days *string) error
 	CleanupChecks(ctx context.Context, days *string) error
-	CreateCheck(ctx context.Context, arg *CreateCheckParams) error
+	CleanupEmailSubscribers(ctx context.Context) error
+	CleanupNotifications(ctx context.Context, days *string) error
+	ConfirmEmailSubscriber(ctx context.Context, id int64) error
+	CountMonitorNotifications(ctx context.Context, arg *CountMonitorNotificationsParams) (int64, error)
+	CountRecentNotifications(ctx context.Context, arg *CountRecentNotificationsParams) (int64, error)
+	CreateAPIToken(ctx context.Context, arg *CreateAPITokenParams) (*ApiToken, error)
+	CreateAlert(ctx context.Context, arg *CreateAlertParams) (*Alert, error)
+	CreateCheck(ctx context.Context, arg *CreateCheckParams) (time.Time, error)
+	CreateEmailSubscriber(ctx context.Context, arg *CreateEmailSubscriberParams) (*EmailSubscriber, error)
 	CreateMonitor(ctx context.Context, arg *CreateMonitorParams) (*Monitor, error)
+	CreateMonitorDependency(ctx context.Context, arg *CreateMonitorDependencyParams) error
+	CreateNotification(ctx context.Context, arg *CreateNotificationParams) error
+	CreateOutage(ctx context.Context, arg *CreateOutageParams) error
 	CreatePushSubscription(ctx context.Context, arg *CreatePushSubscriptionParams) error
+	CreateSession(ctx context.Context, arg *CreateSessionParams) error
+	CreateSetting(ctx context.Context, arg *CreateSettingParams) error
+	CreateUser(ctx context.Context, arg *CreateUserParams) (*User, error)
 	CreateVAPIDKeys(ctx context.Context, arg *CreateVAPIDKeysParams) error
+	DeleteAPIToken(ctx context.Context, id int64) (int64, error)
+	DeleteEmailSubscribersByAddress(ctx context.Context, email string) error
+	DeleteExpiredSessions(ctx context.Context, expiresAt time.Time) error
 	DeleteMonitor(ctx context.Context, id int64) error
+	DeleteMonitorDependencies(ctx context.Context, monitorID int64) error
+	DeleteMonitorOutages(ctx context.Context, monitorID int64) error
 	DeletePushSubscription(ctx context.Context, arg *DeletePushSubscriptionParams) error
 	DeletePushSubscriptionByEndpoint(ctx context.Context, endpoint string) error
+	DeleteSession(ctx context.Context, tokenHash string) error
+	DeleteUser(ctx context.Context, id int64) error
+	DeleteUserSessions(ctx context.Context, userID int64) error
+	EndOutage(ctx context.Context, arg *EndOutageParams) error
+	ExtendOutage(ctx context.Context, arg *ExtendOutageParams) error
+	FailChannelNotifications(ctx context.Context, arg *FailChannelNotificationsParams) error
+	GetAPITokenByHash(ctx context.Context, tokenHash string) (*ApiToken, error)
+	GetAPITokens(ctx context.Context) ([]*ApiToken, error)
+	GetAlert(ctx context.Context, id int64) (*Alert, error)
+	GetCheckBatch(ctx context.Context, arg *GetCheckBatchParams) ([]*Check, error)
+	GetCheckCounts(ctx context.Context, arg *GetCheckCountsParams) (*GetCheckCountsRow, error)
+	GetChecks(ctx context.Context, arg *GetChecksParams) ([]*Check, error)
+	GetConfirmedEmailSubscribers(ctx context.Context) ([]*GetConfirmedEmailSubscribersRow, error)
 	GetDataPoints(ctx context.Context, arg *GetDataPointsParams) ([]*GetDataPointsRow, error)
+	GetDueNotifications(ctx context.Context, arg *GetDueNotificationsParams) ([]*Notification, error)
+	GetEmailSubscriber(ctx context.Context, id int64) (*EmailSubscriber, error)
+	GetEmailSubscriberByAddress(ctx context.Context, arg *GetEmailSubscriberByAddressParams) (*EmailSubscriber, error)
+	GetEmailSubscribersByMonitor(ctx context.Context, monitorID *int64) ([]string, error)
+	GetEscalatingAlerts(ctx context.Context) ([]*Alert, error)
+	GetLatestCheck(ctx context.Context, monitorID int64) (*Check, error)
 	GetMonitor(ctx context.Context, id int64) (*Monitor, error)
-	GetMonitorStats(ctx context.Context, seconds *string) ([]*GetMonitorStatsRow, error)
+	GetMonitorParents(ctx context.Context, monitorID int64) ([]*Monitor, error)
+	GetMonitorResponseTimes(ctx context.Context, arg *GetMonitorResponseTimesParams) ([]int64, error)
+	GetMonitorStats(ctx context.Context, arg *GetMonitorStatsParams) ([]*GetMonitorStatsRow, error)
 	GetMonitors(ctx context.Context) ([]*Monitor, error)
-	GetPushSubscriptionsByMonitor(ctx context.Context, monitorID int64) ([]*PushSubscription, error)
-	GetResponseTimes(ctx context.Context, since time.Time) ([]*GetResponseTimesRow, error)
+	GetNotificationThread(ctx context.Context, arg *GetNotificationThreadParams) (string, error)
+	GetNotifications(ctx context.Context, arg *GetNotificationsParams) ([]*Notification, error)
+	GetOpenAlert(ctx context.Context, monitorID int64) (*Alert, error)
+	GetOpenAlerts(ctx context.Context) ([]*Alert, error)
+	GetOpenOutage(ctx context.Context, monitorID int64) (*Outage, error)
+	GetOutages(ctx context.Context, arg *GetOutagesParams) ([]*Outage, error)
+	GetPendingChannels(ctx context.Context) ([]string, error)
+	GetPeriodOutages(ctx context.Context, arg *GetPeriodOutagesParams) ([]*Outage, error)
+	GetPushSubscription(ctx context.Context, endpoint string) (*PushSubscription, error)
+	GetPushSubscriptionsByEndpoint(ctx context.Context, endpoint string) ([]*PushSubscription, error)
+	GetPushSubscriptionsByMonitor(ctx context.Context, arg *GetPushSubscriptionsByMonitorParams) ([]*PushSubscription, error)
+	GetRecentChecks(ctx context.Context, arg *GetRecentChecksParams) ([]bool, error)
+	GetRecentOutages(ctx context.Context, arg *GetRecentOutagesParams) ([]*GetRecentOutagesRow, error)
+	GetResponseTimes(ctx context.Context, arg *GetResponseTimesParams) ([]*GetResponseTimesRow, error)
+	GetSession(ctx context.Context, tokenHash string) (*GetSessionRow, error)
+	GetSetting(ctx context.Context, key string) (string, error)
+	GetUserByUsername(ctx context.Context, username string) (*User, error)
+	GetUsers(ctx context.Context) ([]*User, error)
 	GetVAPIDKeys(ctx context.Context) (*VapidKey, error)
+	MarkNotificationFailed(ctx context.Context, arg *MarkNotificationFailedParams) error
+	MarkNotificationRetry(ctx context.Context, arg *MarkNotificationRetryParams) error
+	MarkNotificationSent(ctx context.Context, id int64) error
+	MarkPushSubscriptionsStale(ctx context.Context) error
+	PostponeNotification(ctx context.Context, arg *PostponeNotificationParams) error
+	ResolveAlert(ctx context.Context, monitorID int64) (*Alert, error)
+	SetMonitorBudgetBurn(ctx context.Context, arg *SetMonitorBudgetBurnParams) error
+	SetMonitorFlapping(ctx context.Context, arg *SetMonitorFlappingParams) error
+	SetNotificationThread(ctx context.Context, arg *SetNotificationThreadParams) error
+	SnoozeAlert(ctx context.Context, arg *SnoozeAlertParams) (*Alert, error)
+	TouchAPIToken(ctx context.Context, id int64) error
+	TouchUserLogin(ctx context.Context, id int64) error
+	UpdateAlertStep(ctx context.Context, arg *UpdateAlertStepParams) error
 	UpdateMonitor(ctx context.Context, arg *UpdateMonitorParams) (*Monitor, error)
+	UpdateUserPassword(ctx context.Context, arg *UpdateUserPasswordParams) error
+	UpdateUserRole(ctx context.Context, arg *UpdateUserRoleParams) error
+	UpdateVAPIDKeys(ctx context.Context, arg *UpdateVAPIDKeysParams) error
 	VAPIDKeysExist(ctx context.Context) (int64, error)
 }
 
@@ -0,0 +1,87 @@
+-- name: CreateAlert :one
+INSERT INTO
+  alerts (monitor_id, policy, reason)
+VALUES
+  (?, ?, ?) RETURNING *;
+
+-- name: GetAlert :one
+SELECT
+  *
+FROM
+  alerts
+WHERE
+  id = ?;
+
+-- name: GetOpenAlert :one
+SELECT
+  *
+FROM
+  alerts
+WHERE
+  monitor_id = ?
+  AND resolved_at IS NULL
+ORDER BY
+  id DESC
+LIMIT
+  1;
+
+-- name: GetEscalatingAlerts :many
+SELECT
+  *
+FROM
+  alerts
+WHERE
+  resolved_at IS NULL
+  AND acknowledged_at IS NULL
+  AND policy != ''
+  AND (
+    snoozed_until IS NULL
+    OR snoozed_until <= datetime('now')
+  );
+
+-- name: GetOpenAlerts :many
+SELECT
+  *
+FROM
+  alerts
+WHERE
+  resolved_at IS NULL;
+
+-- name: UpdateAlertStep :exec
+UPDATE alerts
+SET
+  step = ?
+WHERE
+  id = ?;
+
+-- name: AcknowledgeAlert :one
+UPDATE alerts
+SET
+  acknowledged_at = CURRENT_TIMESTAMP,
+  acknowledged_by = ?
+WHERE
+  id = ?
+  AND resolved_at IS NULL
+  AND acknowledged_at IS NULL RETURNING *;
+
+-- name: SnoozeAlert :one
+UPDATE alerts
+SET
+  snoozed_until = datetime(CAST(sqlc.arg (until) AS INTEGER), 'unixepoch'),
+  snoozed_by = sqlc.arg (snoozed_by)
+WHERE
+  id = sqlc.arg (id)
+  AND resolved_at IS NULL RETURNING *;
+
+-- name: ResolveAlert :one
+UPDATE alerts
+SET
+  resolved_at = CURRENT_TIMESTAMP
+WHERE
+  monitor_id = ?
+  AND resolved_at IS NULL RETURNING *;
+
+-- name: CleanupAlerts :exec
+DELETE FROM alerts
+WHERE
+  resolved_at < datetime('now', '-' || sqlc.arg (days) || ' days');
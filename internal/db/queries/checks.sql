@@ -1,14 +1,16 @@
--- name: CreateCheck :exec
+-- name: CreateCheck :one
 INSERT INTO
   checks (
     monitor_id,
     status_code,
     response_time,
     error,
-    is_up
+    is_up,
+    state,
+    cert_expires_at
   )
 VALUES
-  (?, ?, ?, ?, ?);
+  (?, ?, ?, ?, ?, ?, ?) RETURNING checked_at;
 
 -- name: CleanupChecks :exec
 DELETE FROM checks
@@ -21,16 +23,28 @@ SELECT
   m.name,
   m.url,
   m.check_interval,
+  m.group_name,
+  m.degraded_threshold,
+  m.public,
   COUNT(c.monitor_id) AS total_checks,
   CAST(
     ROUND(
       COALESCE(
         SUM(
           CASE
-            WHEN c.is_up THEN 1
+            WHEN c.is_up
+            AND c.state IS NOT 'maintenance' THEN 1
             ELSE 0
           END
-        ) * 100.0 / COUNT(c.monitor_id),
+        ) * 100.0 / NULLIF(
+          SUM(
+            CASE
+              WHEN c.state IS NOT 'maintenance' THEN 1
+              ELSE 0
+            END
+          ),
+          0
+        ),
         100.0
       ),
       2
@@ -41,6 +55,9 @@ FROM
   monitors m
   LEFT JOIN checks c ON c.monitor_id = m.id
   AND c.checked_at >= datetime('now', '-' || sqlc.arg (seconds) || ' seconds')
+WHERE
+  sqlc.narg (monitor_id) IS NULL
+  OR m.id = sqlc.narg (monitor_id)
 GROUP BY
   m.id
 ORDER BY
@@ -60,7 +77,7 @@ SELECT
         WHEN is_up
         AND (
           response_time IS NULL
-          OR response_time <= sqlc.arg (degraded_threshold)
+          OR response_time <= m.degraded_threshold
         ) THEN 1
         ELSE 0
       END
@@ -70,7 +87,7 @@ SELECT
     SUM(
       CASE
         WHEN is_up
-        AND response_time > sqlc.arg (degraded_threshold) THEN 1
+        AND response_time > m.degraded_threshold THEN 1
         ELSE 0
       END
     ) AS REAL
@@ -78,16 +95,31 @@ SELECT
   CAST(
     SUM(
       CASE
-        WHEN NOT is_up THEN 1
+        WHEN NOT is_up
+        AND state IS NOT 'maintenance' THEN 1
+        ELSE 0
+      END
+    ) AS REAL
+  ) AS down_count,
+  CAST(
+    SUM(
+      CASE
+        WHEN NOT is_up
+        AND state = 'maintenance' THEN 1
         ELSE 0
       END
     ) AS REAL
-  ) AS down_count
+  ) AS maintenance_count
 FROM
   checks
+  JOIN monitors m ON m.id = checks.monitor_id
 WHERE
   checked_at >= sqlc.arg (since)
   AND checked_at IS NOT NULL
+  AND (
+    sqlc.narg (monitor_id) IS NULL
+    OR monitor_id = sqlc.narg (monitor_id)
+  )
 GROUP BY
   monitor_id,
   bucket_ts
@@ -97,6 +129,93 @@ ORDER BY
   monitor_id,
   bucket_ts;
 
+-- name: GetLatestCheck :one
+SELECT
+  *
+FROM
+  checks
+WHERE
+  monitor_id = ?
+ORDER BY
+  checked_at DESC
+LIMIT
+  1;
+
+-- name: GetChecks :many
+SELECT
+  *
+FROM
+  checks
+WHERE
+  monitor_id = sqlc.arg (monitor_id)
+  AND (
+    sqlc.narg (since) IS NULL
+    OR checked_at >= sqlc.narg (since)
+  )
+  AND (
+    sqlc.narg (until) IS NULL
+    OR checked_at < sqlc.narg (until)
+  )
+  AND (
+    sqlc.narg (is_up) IS NULL
+    OR is_up = sqlc.narg (is_up)
+  )
+  AND (
+    sqlc.narg (before) IS NULL
+    OR CAST(strftime('%s', checked_at) AS INTEGER) < CAST(sqlc.narg (before) AS INTEGER)
+  )
+ORDER BY
+  checked_at DESC
+LIMIT
+  sqlc.arg (limit);
+
+-- name: GetCheckBatch :many
+SELECT
+  *
+FROM
+  checks
+WHERE
+  monitor_id = sqlc.arg (monitor_id)
+  AND (
+    sqlc.narg (since) IS NULL
+    OR checked_at >= sqlc.narg (since)
+  )
+  AND (
+    sqlc.narg (until) IS NULL
+    OR checked_at < sqlc.narg (until)
+  )
+  AND (
+    sqlc.narg (after) IS NULL
+    OR checked_at > sqlc.narg (after)
+  )
+ORDER BY
+  checked_at
+LIMIT
+  sqlc.arg (limit);
+
+-- name: GetRecentChecks :many
+SELECT
+  is_up
+FROM
+  checks
+WHERE
+  monitor_id = ?
+  AND state IS NULL
+ORDER BY
+  checked_at DESC
+LIMIT
+  ?;
+
+-- name: GetMonitorResponseTimes :many
+SELECT
+  response_time
+FROM
+  checks
+WHERE
+  monitor_id = sqlc.arg (monitor_id)
+  AND checked_at >= sqlc.arg (since)
+  AND is_up = 1
+  AND state IS NULL;
 
 -- name: GetResponseTimes :many
 SELECT
@@ -107,4 +226,41 @@ FROM
 WHERE
   checked_at >= sqlc.arg (since)
   AND is_up = 1
-  AND response_time IS NOT NULL;
+  AND response_time IS NOT NULL
+  AND (
+    sqlc.narg (monitor_id) IS NULL
+    OR monitor_id = sqlc.narg (monitor_id)
+  );
+
+-- name: GetCheckCounts :one
+SELECT
+  COUNT(*) AS total,
+  CAST(
+    COALESCE(
+      SUM(
+        CASE
+          WHEN NOT is_up THEN 1
+          ELSE 0
+        END
+      ),
+      0
+    ) AS INTEGER
+  ) AS down_count,
+  CAST(
+    COALESCE(
+      SUM(
+        CASE
+          WHEN is_up
+          AND response_time > sqlc.arg (latency_threshold) THEN 1
+          ELSE 0
+        END
+      ),
+      0
+    ) AS INTEGER
+  ) AS slow_count
+FROM
+  checks
+WHERE
+  monitor_id = sqlc.arg (monitor_id)
+  AND checked_at >= sqlc.arg (since)
+  AND state IS NOT 'maintenance';
@@ -1,8 +1,18 @@
 -- name: CreateMonitor :one
 INSERT INTO
-  monitors (name, url, check_interval)
+  monitors (
+    name,
+    url,
+    check_interval,
+    group_name,
+    degraded_threshold,
+    degraded_percentile,
+    degraded_window,
+    source,
+    public
+  )
 VALUES
-  (?, ?, ?) RETURNING *;
+  (?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING *;
 
 -- name: GetMonitor :one
 SELECT
@@ -23,11 +33,52 @@ UPDATE monitors
 SET
   name = COALESCE(?, name),
   url = COALESCE(?, url),
-  check_interval = COALESCE(?, check_interval)
+  check_interval = COALESCE(?, check_interval),
+  group_name = COALESCE(?, group_name),
+  degraded_threshold = COALESCE(?, degraded_threshold),
+  degraded_percentile = COALESCE(?, degraded_percentile),
+  degraded_window = COALESCE(?, degraded_window),
+  source = COALESCE(?, source),
+  public = COALESCE(?, public),
+  updated_at = CURRENT_TIMESTAMP
 WHERE
   id = ? RETURNING *;
 
+-- name: SetMonitorFlapping :exec
+UPDATE monitors
+SET
+  flapping = ?
+WHERE
+  id = ?;
+
+-- name: SetMonitorBudgetBurn :exec
+UPDATE monitors
+SET
+  budget_burn = ?
+WHERE
+  id = ?;
+
 -- name: DeleteMonitor :exec
 DELETE FROM monitors
 WHERE
   id = ?;
+
+-- name: GetMonitorParents :many
+SELECT
+  m.*
+FROM
+  monitors m
+  JOIN monitor_dependencies d ON d.parent_id = m.id
+WHERE
+  d.monitor_id = ?;
+
+-- name: CreateMonitorDependency :exec
+INSERT INTO
+  monitor_dependencies (monitor_id, parent_id)
+VALUES
+  (?, ?);
+
+-- name: DeleteMonitorDependencies :exec
+DELETE FROM monitor_dependencies
+WHERE
+  monitor_id = ?;
@@ -1,8 +1,14 @@
 -- name: CreatePushSubscription :exec
 INSERT INTO
-  push_subscriptions (monitor_id, endpoint, p256dh_key, auth_key)
+  push_subscriptions (
+    monitor_id,
+    group_name,
+    endpoint,
+    p256dh_key,
+    auth_key
+  )
 VALUES
-  (?, ?, ?, ?);
+  (?, ?, ?, ?, ?);
 
 -- name: DeletePushSubscription :exec
 DELETE FROM push_subscriptions
@@ -14,14 +20,38 @@ WHERE
 SELECT
   id,
   monitor_id,
+  group_name,
   endpoint,
   p256dh_key,
   auth_key,
+  stale,
   created_at
 FROM
   push_subscriptions
 WHERE
-  monitor_id = ?;
+  NOT stale
+  AND (
+    monitor_id = sqlc.arg (monitor_id)
+    OR group_name = '*'
+    OR group_name = sqlc.arg (group_name)
+  );
+
+-- name: GetPushSubscriptionsByEndpoint :many
+SELECT
+  id,
+  monitor_id,
+  group_name,
+  endpoint,
+  p256dh_key,
+  auth_key,
+  stale,
+  created_at
+FROM
+  push_subscriptions
+WHERE
+  endpoint = ?
+ORDER BY
+  id;
 
 -- name: DeletePushSubscriptionByEndpoint :exec
 DELETE FROM push_subscriptions
@@ -45,6 +75,20 @@ INSERT INTO
 VALUES
   (1, ?, ?);
 
+-- name: UpdateVAPIDKeys :exec
+UPDATE vapid_keys
+SET
+  public_key = ?,
+  private_key = ?,
+  created_at = CURRENT_TIMESTAMP
+WHERE
+  id = 1;
+
+-- name: MarkPushSubscriptionsStale :exec
+UPDATE push_subscriptions
+SET
+  stale = TRUE;
+
 -- name: VAPIDKeysExist :one
 SELECT
   COUNT(*) as count
@@ -52,3 +96,99 @@ FROM
   vapid_keys
 WHERE
   id = 1;
+
+-- name: GetPushSubscription :one
+SELECT
+  id,
+  monitor_id,
+  group_name,
+  endpoint,
+  p256dh_key,
+  auth_key,
+  stale,
+  created_at
+FROM
+  push_subscriptions
+WHERE
+  endpoint = ?
+ORDER BY
+  id DESC
+LIMIT
+  1;
+
+-- name: CreateEmailSubscriber :one
+INSERT INTO
+  email_subscribers (email, monitor_id)
+VALUES
+  (?, ?)
+RETURNING
+  *;
+
+-- name: GetEmailSubscriber :one
+SELECT
+  *
+FROM
+  email_subscribers
+WHERE
+  id = ?;
+
+-- name: GetEmailSubscriberByAddress :one
+SELECT
+  *
+FROM
+  email_subscribers
+WHERE
+  email = ?
+  AND monitor_id IS ?;
+
+-- name: ConfirmEmailSubscriber :exec
+UPDATE email_subscribers
+SET
+  confirmed_at = CURRENT_TIMESTAMP
+WHERE
+  id = ?
+  AND confirmed_at IS NULL;
+
+-- name: DeleteEmailSubscribersByAddress :exec
+DELETE FROM email_subscribers
+WHERE
+  email = ?;
+
+-- name: GetEmailSubscribersByMonitor :many
+SELECT DISTINCT
+  email
+FROM
+  email_subscribers
+WHERE
+  confirmed_at IS NOT NULL
+  AND (
+    monitor_id = sqlc.arg (monitor_id)
+    OR (
+      monitor_id IS NULL
+      AND EXISTS (
+        SELECT
+          1
+        FROM
+          monitors
+        WHERE
+          id = sqlc.arg (monitor_id)
+          AND public
+      )
+    )
+  );
+
+-- name: GetConfirmedEmailSubscribers :many
+SELECT
+  s.email,
+  m.name AS monitor_name
+FROM
+  email_subscribers s
+  LEFT JOIN monitors m ON m.id = s.monitor_id
+WHERE
+  s.confirmed_at IS NOT NULL;
+
+-- name: CleanupEmailSubscribers :exec
+DELETE FROM email_subscribers
+WHERE
+  confirmed_at IS NULL
+  AND created_at < datetime('now', '-7 days');
@@ -0,0 +1,98 @@
+-- name: CreateOutage :exec
+INSERT INTO
+  outages (
+    monitor_id,
+    started_at,
+    ended_at,
+    first_error,
+    status_codes,
+    checks
+  )
+VALUES
+  (?, ?, ?, ?, ?, ?);
+
+-- name: GetOpenOutage :one
+SELECT
+  *
+FROM
+  outages
+WHERE
+  monitor_id = ?
+  AND ended_at IS NULL
+ORDER BY
+  id DESC
+LIMIT
+  1;
+
+-- name: ExtendOutage :exec
+UPDATE outages
+SET
+  checks = checks + 1,
+  status_codes = ?
+WHERE
+  id = ?;
+
+-- name: EndOutage :exec
+UPDATE outages
+SET
+  ended_at = ?
+WHERE
+  id = ?;
+
+-- name: DeleteMonitorOutages :exec
+DELETE FROM outages
+WHERE
+  monitor_id = ?;
+
+-- name: GetOutages :many
+SELECT
+  *
+FROM
+  outages
+WHERE
+  monitor_id = sqlc.arg (monitor_id)
+  AND (
+    ended_at IS NULL
+    OR ended_at >= sqlc.arg (since)
+  )
+ORDER BY
+  started_at DESC
+LIMIT
+  sqlc.arg (limit);
+
+-- name: GetRecentOutages :many
+SELECT
+  o.*,
+  m.name AS monitor_name,
+  m.public
+FROM
+  outages o
+  JOIN monitors m ON m.id = o.monitor_id
+WHERE
+  (
+    o.ended_at IS NULL
+    OR o.ended_at >= sqlc.arg (since)
+  )
+  AND (
+    m.public
+    OR CAST(sqlc.arg (private) AS BOOLEAN)
+  )
+ORDER BY
+  o.started_at DESC
+LIMIT
+  sqlc.arg (limit);
+
+-- name: GetPeriodOutages :many
+SELECT
+  *
+FROM
+  outages
+WHERE
+  monitor_id = sqlc.arg (monitor_id)
+  AND started_at < sqlc.arg (until)
+  AND (
+    ended_at IS NULL
+    OR ended_at > sqlc.arg (since)
+  )
+ORDER BY
+  started_at;
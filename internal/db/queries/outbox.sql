@@ -0,0 +1,165 @@
+-- name: CreateNotification :exec
+INSERT INTO
+  notifications (
+    monitor_id,
+    channel,
+    target,
+    event_type,
+    payload,
+    next_attempt_at
+  )
+VALUES
+  (
+    sqlc.arg (monitor_id),
+    sqlc.arg (channel),
+    sqlc.arg (target),
+    sqlc.arg (event_type),
+    sqlc.arg (payload),
+    datetime(CAST(sqlc.arg (send_at) AS INTEGER), 'unixepoch')
+  );
+
+-- name: CountMonitorNotifications :one
+SELECT
+  COUNT(*)
+FROM
+  notifications
+WHERE
+  monitor_id = sqlc.arg (monitor_id)
+  AND event_type = sqlc.arg (event_type)
+  AND created_at > datetime(CAST(sqlc.arg (since) AS INTEGER), 'unixepoch');
+
+-- name: CountRecentNotifications :one
+SELECT
+  COUNT(*)
+FROM
+  notifications
+WHERE
+  channel = sqlc.arg (channel)
+  AND target = sqlc.arg (target)
+  AND event_type = sqlc.arg (event_type)
+  AND created_at > datetime(CAST(sqlc.arg (since) AS INTEGER), 'unixepoch');
+
+-- name: GetDueNotifications :many
+SELECT
+  *
+FROM
+  notifications
+WHERE
+  channel = sqlc.arg (channel)
+  AND status = 'pending'
+  AND next_attempt_at <= datetime('now')
+ORDER BY
+  id
+LIMIT
+  sqlc.arg (limit);
+
+-- name: MarkNotificationSent :exec
+UPDATE notifications
+SET
+  status = 'sent',
+  attempts = attempts + 1,
+  last_error = NULL,
+  sent_at = CURRENT_TIMESTAMP
+WHERE
+  id = ?;
+
+-- name: SetNotificationThread :exec
+UPDATE notifications
+SET
+  thread = ?
+WHERE
+  id = ?;
+
+-- name: GetNotificationThread :one
+SELECT
+  thread
+FROM
+  notifications
+WHERE
+  channel = sqlc.arg (channel)
+  AND monitor_id = sqlc.arg (monitor_id)
+  AND event_type = 'down'
+  AND thread != ''
+  AND id < sqlc.arg (id)
+  AND id > (
+    SELECT
+      COALESCE(MAX(id), 0)
+    FROM
+      notifications
+    WHERE
+      channel = sqlc.arg (channel)
+      AND monitor_id = sqlc.arg (monitor_id)
+      AND event_type = 'up'
+      AND id < sqlc.arg (id)
+  )
+ORDER BY
+  id DESC
+LIMIT
+  1;
+
+-- name: MarkNotificationRetry :exec
+UPDATE notifications
+SET
+  attempts = attempts + 1,
+  last_error = sqlc.arg (last_error),
+  next_attempt_at = datetime('now', '+' || sqlc.arg (delay) || ' seconds')
+WHERE
+  id = sqlc.arg (id);
+
+-- name: MarkNotificationFailed :exec
+UPDATE notifications
+SET
+  status = 'failed',
+  attempts = attempts + 1,
+  last_error = ?
+WHERE
+  id = ?;
+
+-- name: GetNotifications :many
+SELECT
+  *
+FROM
+  notifications
+WHERE
+  (
+    sqlc.narg (monitor_id) IS NULL
+    OR monitor_id = sqlc.narg (monitor_id)
+  )
+  AND (
+    sqlc.narg (status) IS NULL
+    OR status = sqlc.narg (status)
+  )
+ORDER BY
+  id DESC
+LIMIT
+  sqlc.arg (limit);
+
+-- name: PostponeNotification :exec
+UPDATE notifications
+SET
+  next_attempt_at = datetime('now', '+' || sqlc.arg (delay) || ' seconds')
+WHERE
+  id = sqlc.arg (id);
+
+-- name: GetPendingChannels :many
+SELECT DISTINCT
+  channel
+FROM
+  notifications
+WHERE
+  status = 'pending';
+
+-- name: FailChannelNotifications :exec
+UPDATE notifications
+SET
+  status = 'failed',
+  last_error = ?
+WHERE
+  channel = ?
+  AND status = 'pending';
+
+-- name: CleanupNotifications :exec
+DELETE FROM notifications
+WHERE
+  status != 'pending'
+  AND created_at < datetime('now', '-' || sqlc.arg (days) || ' days');
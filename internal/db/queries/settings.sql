@@ -0,0 +1,13 @@
+-- name: GetSetting :one
+SELECT
+  value
+FROM
+  settings
+WHERE
+  key = ?;
+
+-- name: CreateSetting :exec
+INSERT INTO
+  settings (key, value)
+VALUES
+  (?, ?) ON CONFLICT (key) DO NOTHING;
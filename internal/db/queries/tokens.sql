@@ -0,0 +1,33 @@
+-- name: CreateAPIToken :one
+INSERT INTO
+  api_tokens (name, token_hash, scope)
+VALUES
+  (?, ?, ?) RETURNING *;
+
+-- name: GetAPITokenByHash :one
+SELECT
+  *
+FROM
+  api_tokens
+WHERE
+  token_hash = ?;
+
+-- name: GetAPITokens :many
+SELECT
+  *
+FROM
+  api_tokens
+ORDER BY
+  name;
+
+-- name: TouchAPIToken :exec
+UPDATE api_tokens
+SET
+  last_used_at = CURRENT_TIMESTAMP
+WHERE
+  id = ?;
+
+-- name: DeleteAPIToken :execrows
+DELETE FROM api_tokens
+WHERE
+  id = ?;
@@ -0,0 +1,81 @@
+-- name: CreateUser :one
+INSERT INTO
+  users (username, password_hash, role)
+VALUES
+  (?, ?, ?) RETURNING *;
+
+-- name: GetUserByUsername :one
+SELECT
+  *
+FROM
+  users
+WHERE
+  username = ?;
+
+-- name: GetUsers :many
+SELECT
+  *
+FROM
+  users
+ORDER BY
+  username;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET
+  password_hash = ?
+WHERE
+  id = ?;
+
+-- name: UpdateUserRole :exec
+UPDATE users
+SET
+  role = ?
+WHERE
+  id = ?;
+
+-- name: TouchUserLogin :exec
+UPDATE users
+SET
+  last_login_at = CURRENT_TIMESTAMP
+WHERE
+  id = ?;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE
+  id = ?;
+
+-- name: CreateSession :exec
+INSERT INTO
+  sessions (user_id, username, scope, token_hash, expires_at)
+VALUES
+  (?, ?, ?, ?, ?);
+
+-- name: GetSession :one
+SELECT
+  s.id,
+  s.user_id,
+  COALESCE(u.username, s.username) AS username,
+  s.scope,
+  s.expires_at
+FROM
+  sessions s
+  LEFT JOIN users u ON u.id = s.user_id
+WHERE
+  s.token_hash = ?;
+
+-- name: DeleteSession :exec
+DELETE FROM sessions
+WHERE
+  token_hash = ?;
+
+-- name: DeleteUserSessions :exec
+DELETE FROM sessions
+WHERE
+  user_id = ?;
+
+-- name: DeleteExpiredSessions :exec
+DELETE FROM sessions
+WHERE
+  expires_at < ?;
@@ -3,8 +3,16 @@ CREATE TABLE monitors (
   name TEXT NOT NULL,
   url TEXT NOT NULL UNIQUE,
   check_interval INTEGER NOT NULL DEFAULT 60, -- in seconds
+  group_name TEXT NOT NULL DEFAULT '',
+  degraded_threshold INTEGER NOT NULL DEFAULT 500, -- in ms
+  degraded_percentile INTEGER NOT NULL DEFAULT 0, -- 0 judges the latest check only
+  degraded_window INTEGER NOT NULL DEFAULT 300, -- in seconds, for degraded_percentile
   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
-  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  source TEXT NOT NULL DEFAULT 'config', -- "config" file or admin "api"
+  public BOOLEAN NOT NULL DEFAULT TRUE, -- private monitors are only shown to signed in users
+  flapping BOOLEAN NOT NULL DEFAULT FALSE, -- notifications are suppressed while flapping
+  budget_burn REAL NOT NULL DEFAULT 0 -- burn rate of the firing SLO alert, 0 if none fires
 );
 
 CREATE TABLE checks (
@@ -13,18 +21,41 @@ CREATE TABLE checks (
   response_time INTEGER NOT NULL, -- in ms
   error TEXT,
   is_up BOOLEAN NOT NULL,
+  state TEXT, -- NULL for regular checks, "maintenance" or "dependency_down"
   checked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  cert_expires_at TIMESTAMP, -- of the TLS certificate, NULL for plain HTTP
   PRIMARY KEY (monitor_id, checked_at),
   FOREIGN KEY (monitor_id) REFERENCES monitors (id) ON DELETE CASCADE
 );
 
+-- Monitors that are only reachable while their parents are up
+CREATE TABLE monitor_dependencies (
+  monitor_id INTEGER NOT NULL,
+  parent_id INTEGER NOT NULL,
+  PRIMARY KEY (monitor_id, parent_id),
+  FOREIGN KEY (monitor_id) REFERENCES monitors (id) ON DELETE CASCADE,
+  FOREIGN KEY (parent_id) REFERENCES monitors (id) ON DELETE CASCADE
+);
+
 -- Browser notification subscriptions
 CREATE TABLE push_subscriptions (
   id INTEGER PRIMARY KEY AUTOINCREMENT,
-  monitor_id INTEGER NOT NULL,
+  monitor_id INTEGER, -- NULL for group subscriptions
+  group_name TEXT, -- monitor group, "*" for all monitors
   endpoint TEXT NOT NULL,
   p256dh_key TEXT NOT NULL, -- encryption key
   auth_key TEXT NOT NULL, -- authentication secret
+  stale BOOLEAN NOT NULL DEFAULT FALSE, -- created with rotated VAPID keys
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (monitor_id) REFERENCES monitors (id) ON DELETE CASCADE
+);
+
+-- Email subscribers of the status page, double opt-in
+CREATE TABLE email_subscribers (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  email TEXT NOT NULL,
+  monitor_id INTEGER, -- NULL for all monitors and incidents
+  confirmed_at TIMESTAMP,
   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
   FOREIGN KEY (monitor_id) REFERENCES monitors (id) ON DELETE CASCADE
 );
@@ -37,10 +68,114 @@ CREATE TABLE vapid_keys (
   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Outgoing notifications, delivered by a background worker
+CREATE TABLE notifications (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  monitor_id INTEGER, -- NULL for incident updates
+  channel TEXT NOT NULL, -- channel name or "webpush"
+  target TEXT NOT NULL DEFAULT '', -- push endpoint or email subscriber address
+  event_type TEXT NOT NULL,
+  payload TEXT NOT NULL, -- JSON encoded event
+  status TEXT NOT NULL DEFAULT 'pending', -- pending, sent or failed
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  sent_at TIMESTAMP,
+  thread TEXT NOT NULL DEFAULT '', -- chat message that recoveries reply to or edit
+  FOREIGN KEY (monitor_id) REFERENCES monitors (id) ON DELETE CASCADE
+);
+
+-- Outages that subscribers were alerted about
+CREATE TABLE alerts (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  monitor_id INTEGER NOT NULL,
+  policy TEXT NOT NULL DEFAULT '', -- escalation policy name
+  step INTEGER NOT NULL DEFAULT 0, -- escalation steps already notified
+  reason TEXT NOT NULL DEFAULT '',
+  started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  acknowledged_at TIMESTAMP,
+  acknowledged_by TEXT,
+  snoozed_until TIMESTAMP,
+  snoozed_by TEXT,
+  resolved_at TIMESTAMP,
+  FOREIGN KEY (monitor_id) REFERENCES monitors (id) ON DELETE CASCADE
+);
+
+-- Outages of monitors, runs of consecutive failed checks outside maintenance
+CREATE TABLE outages (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  monitor_id INTEGER NOT NULL,
+  started_at TIMESTAMP NOT NULL, -- of the first failed check
+  ended_at TIMESTAMP, -- of the next successful check, NULL while ongoing
+  first_error TEXT NOT NULL DEFAULT '',
+  status_codes TEXT NOT NULL DEFAULT '', -- comma separated, in the order first seen
+  checks INTEGER NOT NULL DEFAULT 1, -- failed checks
+  FOREIGN KEY (monitor_id) REFERENCES monitors (id) ON DELETE CASCADE
+);
+
+-- Bearer tokens of the admin API, stored hashed
+CREATE TABLE api_tokens (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL UNIQUE,
+  token_hash TEXT NOT NULL UNIQUE, -- hex encoded SHA-256
+  scope TEXT NOT NULL, -- read, write or admin, each including the previous
+  last_used_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Users of the dashboard
+CREATE TABLE users (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  username TEXT NOT NULL UNIQUE,
+  password_hash TEXT NOT NULL, -- bcrypt
+  role TEXT NOT NULL DEFAULT 'viewer', -- viewer, editor or admin
+  last_login_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Sessions of signed in users, stored hashed
+CREATE TABLE sessions (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER, -- NULL for users of the OpenID Connect provider
+  username TEXT NOT NULL DEFAULT '', -- of OpenID Connect users, local ones are joined
+  scope TEXT NOT NULL DEFAULT 'read', -- granted by the role of the user
+  token_hash TEXT NOT NULL UNIQUE, -- hex encoded SHA-256
+  expires_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- Application settings (key/value)
+CREATE TABLE settings (
+  key TEXT PRIMARY KEY,
+  value TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE INDEX idx_checks_checked_at ON checks (checked_at);
 
 CREATE INDEX idx_checks_monitor_time_up ON checks (monitor_id, checked_at, is_up, response_time);
 
-CREATE UNIQUE INDEX idx_push_sub_endpoint ON push_subscriptions (endpoint, monitor_id);
+-- A subscription is either to a monitor or to a group, NULLs would be distinct
+CREATE UNIQUE INDEX idx_push_sub_endpoint_monitor ON push_subscriptions (endpoint, monitor_id)
+WHERE
+  monitor_id IS NOT NULL;
+
+CREATE UNIQUE INDEX idx_push_sub_endpoint_group ON push_subscriptions (endpoint, group_name)
+WHERE
+  group_name IS NOT NULL;
 
 CREATE INDEX idx_push_sub_monitor ON push_subscriptions (monitor_id);
+
+CREATE INDEX idx_email_subscribers_email ON email_subscribers (email, monitor_id);
+
+CREATE INDEX idx_notifications_due ON notifications (channel, status, next_attempt_at);
+
+CREATE INDEX idx_notifications_monitor ON notifications (monitor_id, created_at);
+
+CREATE INDEX idx_alerts_monitor ON alerts (monitor_id, resolved_at);
+
+CREATE INDEX idx_outages_monitor ON outages (monitor_id, started_at);
+
+CREATE INDEX idx_outages_ended_at ON outages (ended_at);
@@ -0,0 +1,181 @@
+// Package export writes the checks of a monitor as CSV, JSON or NDJSON
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+// Format of an export
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatJSON   Format = "json"   // an array of checks
+	FormatNDJSON Format = "ndjson" // a check per line
+)
+
+var formats = []Format{FormatCSV, FormatJSON, FormatNDJSON}
+
+// ParseFormat validates a format
+func ParseFormat(s string) (Format, error) {
+	if !slices.Contains(formats, Format(s)) {
+		return "", fmt.Errorf("invalid format %q, expected csv, json or ndjson", s)
+	}
+	return Format(s), nil
+}
+
+// ContentType returns the media type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	}
+	return "application/json"
+}
+
+// Check is an exported check
+type Check struct {
+	CheckedAt     time.Time  `json:"checked_at"`
+	IsUp          bool       `json:"is_up"`
+	StatusCode    int64      `json:"status_code"`
+	ResponseTime  int64      `json:"response_time"` // in ms
+	Error         *string    `json:"error,omitempty"`
+	State         *string    `json:"state,omitempty"` // maintenance or dependency_down
+	CertExpiresAt *time.Time `json:"cert_expires_at,omitempty"`
+}
+
+var csvHeader = []string{
+	"checked_at",
+	"is_up",
+	"status_code",
+	"response_time_ms",
+	"error",
+	"state",
+	"cert_expires_at",
+}
+
+// Checks writes the checks of a monitor between from and to, oldest first,
+// and returns how many were written. Either time may be nil.
+func Checks(
+	ctx context.Context,
+	conn *db.Connection,
+	w io.Writer,
+	format Format,
+	monitorID int64,
+	from, to *time.Time,
+) (int, error) {
+	enc := newEncoder(w, format)
+	if err := enc.begin(); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := conn.Q.EachCheck(ctx, monitorID, from, to, func(c *db.Check) error {
+		count++
+		return enc.write(Check{
+			CheckedAt:     c.CheckedAt.UTC(),
+			IsUp:          c.IsUp,
+			StatusCode:    c.StatusCode,
+			ResponseTime:  c.ResponseTime,
+			Error:         c.Error,
+			State:         c.State,
+			CertExpiresAt: c.CertExpiresAt,
+		})
+	})
+	if err != nil {
+		return count, fmt.Errorf("failed to export checks: %w", err)
+	}
+	return count, enc.end()
+}
+
+// encoder writes checks in a format
+type encoder struct {
+	w      io.Writer
+	format Format
+	csv    *csv.Writer
+	json   *json.Encoder
+	first  bool
+}
+
+func newEncoder(w io.Writer, format Format) *encoder {
+	enc := &encoder{w: w, format: format, first: true}
+	switch format {
+	case FormatCSV:
+		enc.csv = csv.NewWriter(w)
+	default:
+		enc.json = json.NewEncoder(w)
+	}
+	return enc
+}
+
+func (e *encoder) begin() error {
+	switch e.format {
+	case FormatCSV:
+		return e.csv.Write(csvHeader)
+	case FormatJSON:
+		_, err := io.WriteString(e.w, "[\n")
+		return err
+	}
+	return nil
+}
+
+func (e *encoder) write(c Check) error {
+	switch e.format {
+	case FormatCSV:
+		return e.csv.Write([]string{
+			c.CheckedAt.Format(time.RFC3339),
+			strconv.FormatBool(c.IsUp),
+			strconv.FormatInt(c.StatusCode, 10),
+			strconv.FormatInt(c.ResponseTime, 10),
+			deref(c.Error),
+			deref(c.State),
+			formatTime(c.CertExpiresAt),
+		})
+	case FormatJSON:
+		// Encode ends each check with a newline, separate them by commas
+		if !e.first {
+			if _, err := io.WriteString(e.w, ","); err != nil {
+				return err
+			}
+		}
+		e.first = false
+	}
+	return e.json.Encode(c)
+}
+
+func (e *encoder) end() error {
+	switch e.format {
+	case FormatCSV:
+		e.csv.Flush()
+		return e.csv.Error()
+	case FormatJSON:
+		_, err := io.WriteString(e.w, "]\n")
+		return err
+	}
+	return nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,122 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+var testChecks = []Check{
+	{
+		CheckedAt:    time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC),
+		IsUp:         true,
+		StatusCode:   200,
+		ResponseTime: 120,
+	},
+	{
+		CheckedAt:     time.Date(2025, 2, 1, 12, 1, 0, 0, time.UTC),
+		StatusCode:    502,
+		ResponseTime:  3000,
+		Error:         ptr("upstream said \"no\",\nthen hung up"),
+		State:         ptr("dependency_down"),
+		CertExpiresAt: ptr(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)),
+	},
+}
+
+// csvRows are the testChecks in CSV
+var csvRows = [][]string{
+	{"2025-02-01T12:00:00Z", "true", "200", "120", "", "", ""},
+	{"2025-02-01T12:01:00Z", "false", "502", "3000", "upstream said \"no\",\nthen hung up", "dependency_down", "2025-03-01T00:00:00Z"},
+}
+
+func encode(t *testing.T, format Format, checks []Check) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := newEncoder(&buf, format)
+	if err := enc.begin(); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range checks {
+		if err := enc.write(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := enc.end(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestEncoder(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []Check
+	}{
+		{"empty", nil},
+		{"single", testChecks[:1]},
+		{"several", testChecks},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Run("csv", func(t *testing.T) {
+				records, err := csv.NewReader(bytes.NewReader(encode(t, FormatCSV, tt.checks))).ReadAll()
+				if err != nil {
+					t.Fatalf("invalid CSV: %v", err)
+				}
+				if len(records) != len(tt.checks)+1 || !reflect.DeepEqual(records[0], csvHeader) {
+					t.Fatalf("CSV = %q, want the header and %d rows", records, len(tt.checks))
+				}
+				for i := range tt.checks {
+					if !reflect.DeepEqual(records[i+1], csvRows[i]) {
+						t.Errorf("row %d = %q, want %q", i, records[i+1], csvRows[i])
+					}
+				}
+			})
+
+			t.Run("json", func(t *testing.T) {
+				var got []Check
+				if err := json.Unmarshal(encode(t, FormatJSON, tt.checks), &got); err != nil {
+					t.Fatalf("invalid JSON: %v", err)
+				}
+				if len(got) != len(tt.checks) || (len(got) > 0 && !reflect.DeepEqual(got, tt.checks)) {
+					t.Errorf("JSON = %+v, want %+v", got, tt.checks)
+				}
+			})
+
+			t.Run("ndjson", func(t *testing.T) {
+				var got []Check
+				scanner := bufio.NewScanner(bytes.NewReader(encode(t, FormatNDJSON, tt.checks)))
+				for scanner.Scan() {
+					var c Check
+					if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+						t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+					}
+					got = append(got, c)
+				}
+				if len(got) != len(tt.checks) || (len(got) > 0 && !reflect.DeepEqual(got, tt.checks)) {
+					t.Errorf("NDJSON = %+v, want %+v", got, tt.checks)
+				}
+			})
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, s := range []string{"csv", "json", "ndjson"} {
+		if f, err := ParseFormat(s); err != nil || string(f) != s {
+			t.Errorf("ParseFormat(%q) = %q, %v", s, f, err)
+		}
+	}
+	for _, s := range []string{"", "CSV", "xml"} {
+		if _, err := ParseFormat(s); err == nil {
+			t.Errorf("ParseFormat(%q) succeeded, want an error", s)
+		}
+	}
+}
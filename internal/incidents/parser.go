@@ -98,3 +98,14 @@ func (i *Incident) Validate() error {
 	}
 	return nil
 }
+
+// LatestUpdate returns the most recent update of the incident, if any
+func (i *Incident) LatestUpdate() *IncidentUpdate {
+	var latest *IncidentUpdate
+	for j := range i.Updates {
+		if latest == nil || i.Updates[j].CreatedAt.After(latest.CreatedAt) {
+			latest = &i.Updates[j]
+		}
+	}
+	return latest
+}
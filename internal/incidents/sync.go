@@ -16,6 +16,8 @@ type IncidentManager struct {
 	Interval  time.Duration
 	mu        sync.RWMutex
 	incidents []Incident
+	loaded    bool
+	onUpdate  []func(ctx context.Context, incident Incident)
 }
 
 func New(repoURL, repoPath string, interval time.Duration) *IncidentManager {
@@ -48,32 +50,43 @@ func (i *IncidentManager) Start(ctx context.Context) {
 	}
 
 	// Initial parse
-	if err := i.loadIncidents(); err != nil {
+	if err := i.loadIncidents(ctx); err != nil {
 		slog.Warn("Failed to load incidents", "error", err)
 	}
 
-	// Periodic sync only if using git
-	if i.RepoURL != "" {
+	// Periodic sync, local directories are reloaded to pick up edits
+	go func() {
 		ticker := time.NewTicker(i.Interval)
 		defer ticker.Stop()
 
-		go func() {
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				case <-ticker.C:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if i.RepoURL != "" {
 					if err := i.syncRepo(); err != nil {
 						slog.Error("Failed to sync incidents repo", "error", err)
 						continue
 					}
-					if err := i.loadIncidents(); err != nil {
-						slog.Error("Failed to reload incidents", "error", err)
-					}
+				}
+				if err := i.loadIncidents(ctx); err != nil {
+					slog.Error("Failed to reload incidents", "error", err)
 				}
 			}
-		}()
+		}
+	}()
+}
+
+// OnUpdate registers fn to be called for new incidents and for incidents
+// whose status changed or that got new updates after the initial load.
+func (i *IncidentManager) OnUpdate(fn func(ctx context.Context, incident Incident)) {
+	if i == nil {
+		return
 	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.onUpdate = append(i.onUpdate, fn)
 }
 
 func (i *IncidentManager) syncRepo() error {
@@ -88,15 +101,35 @@ func (i *IncidentManager) syncRepo() error {
 	return cmd.Run()
 }
 
-func (i *IncidentManager) loadIncidents() error {
+func (i *IncidentManager) loadIncidents(ctx context.Context) error {
 	incidents, err := ParseIncidentsDir(i.RepoPath)
 	if err != nil {
 		return err
 	}
 
 	i.mu.Lock()
+	previous := make(map[string]Incident, len(i.incidents))
+	for _, incident := range i.incidents {
+		previous[incident.ID] = incident
+	}
+	loaded := i.loaded
 	i.incidents = incidents
+	i.loaded = true
+	callbacks := i.onUpdate
 	i.mu.Unlock()
+
+	if !loaded {
+		return nil
+	}
+	for _, incident := range incidents {
+		old, ok := previous[incident.ID]
+		if ok && old.Status == incident.Status && len(old.Updates) == len(incident.Updates) {
+			continue
+		}
+		for _, fn := range callbacks {
+			fn(ctx, incident)
+		}
+	}
 	return nil
 }
 
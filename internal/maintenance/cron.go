@@ -0,0 +1,166 @@
+package maintenance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var cronAliases = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+var cronFields = [5]struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // 0 and 7 are both Sunday
+}
+
+// cron is a parsed standard 5-field cron expression
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+func parseCron(expr string) (*cron, error) {
+	if alias, ok := cronAliases[expr]; ok {
+		expr = alias
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("expected %d fields, got %d", len(cronFields), len(fields))
+	}
+
+	var bits [5]uint64
+	for i, field := range fields {
+		b, err := parseCronField(field, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cronFields[i].name, err)
+		}
+		bits[i] = b
+	}
+
+	// Sunday can be written as 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return &cron{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField supports *, single values, ranges, lists and steps
+func parseCronField(field string, lo, hi int) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(field, ",") {
+		expr, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+
+		start, end := lo, hi
+		if expr != "*" {
+			from, to, isRange := strings.Cut(expr, "-")
+			var err error
+			if start, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("invalid value %q", from)
+			}
+			switch {
+			case isRange:
+				if end, err = strconv.Atoi(to); err != nil {
+					return 0, fmt.Errorf("invalid value %q", to)
+				}
+			case !hasStep:
+				end = start
+			}
+		}
+		if start < lo || end > hi || start > end {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, lo, hi)
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// matches reports whether the schedule fires in the minute of t
+func (c *cron) matches(t time.Time) bool {
+	return c.minute&(1<<t.Minute()) != 0 &&
+		c.hour&(1<<t.Hour()) != 0 &&
+		c.matchesDay(t)
+}
+
+// matchesDay reports whether the schedule fires on the day of t
+func (c *cron) matchesDay(t time.Time) bool {
+	if c.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+
+	// Like cron, restricting both day fields matches either of them
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<int(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// prev returns the last minute the schedule fires in at or before t, if it
+// is after since. It skips days and hours that don't match instead of
+// visiting every minute, stepping back in elapsed time so that the wall
+// clock of t's location is followed across daylight saving time.
+func (c *cron) prev(t, since time.Time) (time.Time, bool) {
+	t = t.Truncate(time.Minute)
+	for t.After(since) {
+		switch {
+		case !c.matchesDay(t):
+			// Back to the last minute of the previous day
+			midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+			if prev := midnight.Add(-time.Minute); prev.Before(t) {
+				t = prev
+			} else {
+				t = t.Add(-time.Duration(t.Minute()+1) * time.Minute)
+			}
+		case c.hour&(1<<t.Hour()) == 0:
+			// Back to the last minute of the previous hour
+			t = t.Add(-time.Duration(t.Minute()+1) * time.Minute)
+		default:
+			// The latest minute of this hour up to t's, if any
+			for m := t.Minute(); m >= 0; m-- {
+				if c.minute&(1<<m) != 0 {
+					if t = t.Add(-time.Duration(t.Minute()-m) * time.Minute); t.After(since) {
+						return t, true
+					}
+					return time.Time{}, false
+				}
+			}
+			t = t.Add(-time.Duration(t.Minute()+1) * time.Minute)
+		}
+	}
+	return time.Time{}, false
+}
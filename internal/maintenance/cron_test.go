@@ -0,0 +1,119 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"1-a * * * *",
+		"@often",
+	}
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := parseCron(expr); err == nil {
+				t.Errorf("parseCron(%q) succeeded, want error", expr)
+			}
+		})
+	}
+}
+
+func TestCronMatches(t *testing.T) {
+	// Saturday, March 1st 2025
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		expr string
+		t    time.Time
+		want bool
+	}{
+		{"* * * * *", at(1, 13, 37), true},
+		{"0 2 * * *", at(1, 2, 0), true},
+		{"0 2 * * *", at(1, 2, 1), false},
+		{"*/15 * * * *", at(1, 9, 45), true},
+		{"*/15 * * * *", at(1, 9, 50), false},
+		{"5/20 * * * *", at(1, 9, 25), true},
+		{"0 9-17 * * *", at(1, 17, 0), true},
+		{"0 9-17 * * *", at(1, 18, 0), false},
+		{"0 0,12 * * *", at(1, 12, 0), true},
+		{"0 0 * * 6", at(1, 0, 0), true},
+		{"0 0 * * 0", at(2, 0, 0), true},
+		{"0 0 * * 7", at(2, 0, 0), true}, // Sunday written as 7
+		{"0 0 * * 1-5", at(1, 0, 0), false},
+		{"0 0 1 * *", at(1, 0, 0), true},
+		{"0 0 1 2 *", at(1, 0, 0), false},
+		{"@daily", at(1, 0, 0), true},
+		{"@hourly", at(1, 7, 30), false},
+		// Restricting both day fields matches either of them
+		{"0 0 15 * 6", at(1, 0, 0), true},
+		{"0 0 15 * 1", at(1, 0, 0), false},
+		// A wildcard day field requires the other one to match
+		{"0 0 15 * *", at(1, 0, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr+" "+tt.t.Format(time.DateTime), func(t *testing.T) {
+			c, err := parseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("parseCron(%q): %v", tt.expr, err)
+			}
+			if got := c.matches(tt.t); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronPrev(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no timezone database:", err)
+	}
+	exprs := []string{
+		"* * * * *", "0 2 * * *", "30 2 * * *", "*/15 * * * *", "0 9-17 * * 1-5",
+		"0 0 1 * *", "0 0 15 * 6", "45 23 * * 0", "0 0 29 2 *", "@weekly",
+	}
+	// Around the daylight saving time changes of Berlin
+	starts := []time.Time{
+		time.Date(2025, 3, 30, 4, 0, 0, 0, berlin),
+		time.Date(2025, 10, 26, 3, 30, 0, 0, berlin),
+		time.Date(2025, 3, 3, 12, 7, 0, 0, time.UTC),
+	}
+
+	for _, expr := range exprs {
+		c, err := parseCron(expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", expr, err)
+		}
+		for _, start := range starts {
+			for _, d := range []time.Duration{time.Minute, 90 * time.Minute, 7 * 24 * time.Hour} {
+				// Against visiting every minute
+				var want time.Time
+				since := start.Add(-d)
+				for t := start.Truncate(time.Minute); t.After(since); t = t.Add(-time.Minute) {
+					if c.matches(t) {
+						want = t
+						break
+					}
+				}
+				got, ok := c.prev(start, since)
+				if ok != !want.IsZero() || !got.Equal(want) {
+					t.Errorf("%q.prev(%v, %v) = %v, %v, want %v", expr, start, since, got, ok, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package maintenance provides scheduled maintenance windows for monitors
+package maintenance
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/incidents"
+)
+
+// maxDuration bounds recurring windows
+const maxDuration = 7 * 24 * time.Hour
+
+// Window is a one-off or recurring period in which monitors are under maintenance
+type Window struct {
+	Name     string        `yaml:"name"`
+	Schedule string        `yaml:"schedule,omitempty"` // cron expression, for recurring windows
+	Duration time.Duration `yaml:"duration,omitempty"` // length of recurring windows
+	Start    string        `yaml:"start,omitempty"`    // one-off windows, see parseTime
+	End      string        `yaml:"end,omitempty"`
+	Monitors []string      `yaml:"monitors,omitempty"` // monitor names, "*" for all
+	Groups   []string      `yaml:"groups,omitempty"`
+
+	cron       *cron
+	start, end time.Time
+}
+
+// timeLayouts are accepted for the start and end of one-off windows
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// parseTime parses a time of a one-off window, in location unless it has an
+// offset
+func parseTime(value string, location *time.Location) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected e.g. 2025-03-01T02:00:00", value)
+}
+
+// Validate checks the window and parses its schedule, or its start and end in
+// location
+func (w *Window) Validate(location *time.Location) error {
+	if w.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(w.Monitors) == 0 && len(w.Groups) == 0 {
+		return fmt.Errorf("at least one monitor or group is required")
+	}
+
+	switch {
+	case w.Schedule != "" && (w.Start != "" || w.End != ""):
+		return fmt.Errorf("use either schedule or start/end, not both")
+	case w.Schedule != "":
+		c, err := parseCron(w.Schedule)
+		if err != nil {
+			return fmt.Errorf("invalid schedule %q: %w", w.Schedule, err)
+		}
+		if w.Duration < time.Minute || w.Duration > maxDuration {
+			return fmt.Errorf("duration must be between 1m and %s", maxDuration)
+		}
+		w.cron = c
+	case w.Start != "" && w.End != "":
+		var err error
+		if w.start, err = parseTime(w.Start, location); err != nil {
+			return fmt.Errorf("start: %w", err)
+		}
+		if w.end, err = parseTime(w.End, location); err != nil {
+			return fmt.Errorf("end: %w", err)
+		}
+		if !w.end.After(w.start) {
+			return fmt.Errorf("end must be after start")
+		}
+	default:
+		return fmt.Errorf("either schedule or start and end are required")
+	}
+	return nil
+}
+
+// applies reports whether the window covers the monitor
+func (w *Window) applies(name, group string) bool {
+	return slices.Contains(w.Monitors, "*") ||
+		slices.Contains(w.Monitors, name) ||
+		(group != "" && slices.Contains(w.Groups, group))
+}
+
+// active reports whether now falls into the window
+func (w *Window) active(now time.Time, location *time.Location) bool {
+	if !w.start.IsZero() {
+		return !now.Before(w.start) && now.Before(w.end)
+	}
+	if w.cron == nil {
+		return false
+	}
+
+	// Look for a start of the window within the last duration
+	now = now.In(location)
+	_, ok := w.cron.prev(now, now.Add(-w.Duration))
+	return ok
+}
+
+// Calendar decides which monitors are currently under maintenance
+type Calendar struct {
+	windows   []Window
+	location  *time.Location
+	incidents *incidents.IncidentManager
+}
+
+// New creates a calendar from validated windows. Unresolved incidents with
+// severity "maintenance" count as windows for their affected monitors.
+func New(
+	windows []Window,
+	location *time.Location,
+	manager *incidents.IncidentManager,
+) *Calendar {
+	if location == nil {
+		location = time.UTC
+	}
+	return &Calendar{
+		windows:   windows,
+		location:  location,
+		incidents: manager,
+	}
+}
+
+// Active returns the name of the maintenance window the monitor is in, if any
+func (c *Calendar) Active(name, group string, now time.Time) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	for i := range c.windows {
+		w := &c.windows[i]
+		if w.applies(name, group) && w.active(now, c.location) {
+			return w.Name, true
+		}
+	}
+
+	if c.incidents == nil {
+		return "", false
+	}
+	for _, incident := range c.incidents.GetIncidents() {
+		if incident.Severity != "maintenance" || incident.Status == "resolved" {
+			continue
+		}
+		if now.Before(incident.StartedAt) ||
+			(incident.ResolvedAt != nil && !now.Before(*incident.ResolvedAt)) {
+			continue
+		}
+		if slices.Contains(incident.AffectedMonitors, name) {
+			return incident.Title, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,110 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  Window
+		wantErr bool
+	}{
+		{"recurring", Window{Name: "w", Schedule: "0 2 * * 0", Duration: time.Hour, Groups: []string{"db"}}, false},
+		{"one-off", Window{Name: "w", Start: "2025-02-01T22:00:00", End: "2025-02-02T04:00", Monitors: []string{"*"}}, false},
+		{"no name", Window{Schedule: "@daily", Duration: time.Hour, Monitors: []string{"*"}}, true},
+		{"no monitors", Window{Name: "w", Schedule: "@daily", Duration: time.Hour}, true},
+		{"both kinds", Window{Name: "w", Schedule: "@daily", Duration: time.Hour, Start: "2025-02-01T22:00:00", Monitors: []string{"*"}}, true},
+		{"neither kind", Window{Name: "w", Monitors: []string{"*"}}, true},
+		{"start only", Window{Name: "w", Start: "2025-02-01T22:00:00", Monitors: []string{"*"}}, true},
+		{"invalid schedule", Window{Name: "w", Schedule: "0 25 * * *", Duration: time.Hour, Monitors: []string{"*"}}, true},
+		{"no duration", Window{Name: "w", Schedule: "@daily", Monitors: []string{"*"}}, true},
+		{"duration below a minute", Window{Name: "w", Schedule: "@daily", Duration: 30 * time.Second, Monitors: []string{"*"}}, true},
+		{"duration above a week", Window{Name: "w", Schedule: "@daily", Duration: 8 * 24 * time.Hour, Monitors: []string{"*"}}, true},
+		{"invalid start", Window{Name: "w", Start: "tomorrow", End: "2025-02-02T04:00:00", Monitors: []string{"*"}}, true},
+		{"end before start", Window{Name: "w", Start: "2025-02-02T04:00:00", End: "2025-02-01T22:00:00", Monitors: []string{"*"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.Validate(time.UTC)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	vienna, err := time.LoadLocation("Europe/Vienna")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		// Without an offset in the configured location, UTC+1 in February
+		{"2025-02-01T22:00:00", time.Date(2025, 2, 1, 21, 0, 0, 0, time.UTC)},
+		{"2025-02-01T22:00", time.Date(2025, 2, 1, 21, 0, 0, 0, time.UTC)},
+		{"2025-02-01 22:00:00", time.Date(2025, 2, 1, 21, 0, 0, 0, time.UTC)},
+		{"2025-02-01 22:00", time.Date(2025, 2, 1, 21, 0, 0, 0, time.UTC)},
+		// An offset wins
+		{"2025-02-01T22:00:00Z", time.Date(2025, 2, 1, 22, 0, 0, 0, time.UTC)},
+		{"2025-02-01T22:00:00+03:00", time.Date(2025, 2, 1, 19, 0, 0, 0, time.UTC)},
+		{"2025-02-01 22:00:00+03:00", time.Date(2025, 2, 1, 19, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseTime(tt.value, vienna)
+			if err != nil {
+				t.Fatalf("parseTime(%q): %v", tt.value, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseTime(%q) = %v, want %v", tt.value, got.UTC(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendarActive(t *testing.T) {
+	windows := []Window{
+		{Name: "patching", Schedule: "0 2 * * 0", Duration: 2 * time.Hour, Groups: []string{"db"}},
+		{Name: "move", Start: "2025-03-05T22:00:00", End: "2025-03-06T04:00:00", Monitors: []string{"API"}},
+		{Name: "everything", Start: "2025-03-10T00:00:00", End: "2025-03-10T00:30:00", Monitors: []string{"*"}},
+	}
+	for i := range windows {
+		if err := windows[i].Validate(time.UTC); err != nil {
+			t.Fatalf("window %q: %v", windows[i].Name, err)
+		}
+	}
+	c := New(windows, time.UTC, nil)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name, monitor, group string
+		now                  time.Time
+		want                 string
+	}{
+		{"recurring start", "Postgres", "db", at(2, 2, 0), "patching"},
+		{"recurring end", "Postgres", "db", at(2, 3, 59), "patching"},
+		{"after recurring", "Postgres", "db", at(2, 4, 0), ""},
+		{"before recurring", "Postgres", "db", at(2, 1, 59), ""},
+		{"other group", "Redis", "cache", at(2, 2, 30), ""},
+		{"one-off start", "API", "", at(5, 22, 0), "move"},
+		{"one-off end", "API", "", at(6, 4, 0), ""},
+		{"other monitor", "Web", "", at(5, 23, 0), ""},
+		{"wildcard", "Web", "", at(10, 0, 15), "everything"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := c.Active(tt.monitor, tt.group, tt.now)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("Active() = %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+// Package metrics exposes metrics of monitors and of Beacon itself in the
+// Prometheus text format
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"net/http"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Upper bounds of histogram buckets, in seconds
+var (
+	responseTimeBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+	dbBuckets           = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 1}
+)
+
+// Check is the outcome of a check of a monitor
+type Check struct {
+	Up           bool
+	StatusCode   int64
+	ResponseTime time.Duration
+	CertExpiry   *time.Time // of the TLS certificate, if any
+}
+
+// monitor holds the metrics of a single monitor
+type monitor struct {
+	labels        string // monitor name, url and group
+	up            bool
+	statusCode    int64
+	responseTime  time.Duration
+	certExpiry    *time.Time
+	checks        uint64
+	failures      uint64
+	responseTimes *histogram
+}
+
+// delivery identifies a counter of notification deliveries
+type delivery struct {
+	channel, status string
+}
+
+var (
+	mu         sync.Mutex
+	monitors   = make(map[int64]*monitor)
+	deliveries = make(map[delivery]uint64)
+	dbWrites   = newHistogram(dbBuckets)
+	dbQueries  = newHistogram(dbBuckets)
+
+	// Monitors checked by the scheduler
+	scheduled atomic.Int64
+)
+
+// ObserveCheck records a check of a monitor
+func ObserveCheck(monitorID int64, name, url, group string, check Check) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	m, ok := monitors[monitorID]
+	if !ok {
+		m = &monitor{responseTimes: newHistogram(responseTimeBuckets)}
+		monitors[monitorID] = m
+	}
+	m.labels = labels("monitor", name, "url", url, "group", group)
+	m.up = check.Up
+	m.statusCode = check.StatusCode
+	m.responseTime = check.ResponseTime
+	m.certExpiry = check.CertExpiry
+	m.checks++
+	if !check.Up {
+		m.failures++
+	}
+	m.responseTimes.observe(check.ResponseTime.Seconds())
+}
+
+// RemoveMonitor drops the metrics of a deleted monitor
+func RemoveMonitor(monitorID int64) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(monitors, monitorID)
+}
+
+// ObserveDelivery counts a notification delivery, its status being sent,
+// retried or failed
+func ObserveDelivery(channel, status string) {
+	mu.Lock()
+	defer mu.Unlock()
+	deliveries[delivery{channel, status}]++
+}
+
+// ObserveDBWrite records the latency of a database write
+func ObserveDBWrite(d time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	dbWrites.observe(d.Seconds())
+}
+
+// ObserveDBQuery records the latency of a database query
+func ObserveDBQuery(d time.Duration) {
+	mu.Lock()
+	defer mu.Unlock()
+	dbQueries.observe(d.Seconds())
+}
+
+// MonitorStarted counts a monitor the scheduler started checking
+func MonitorStarted() {
+	scheduled.Add(1)
+}
+
+// MonitorStopped counts a monitor the scheduler stopped checking
+func MonitorStopped() {
+	scheduled.Add(-1)
+}
+
+// Handler serves all metrics
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		b := bufio.NewWriter(w)
+		write(b)
+		_ = b.Flush()
+	})
+}
+
+func write(w *bufio.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	ids := slices.Sorted(maps.Keys(monitors))
+	family := func(name, kind, help string, value func(m *monitor) (float64, bool)) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for _, id := range ids {
+			m := monitors[id]
+			if v, ok := value(m); ok {
+				fmt.Fprintf(w, "%s %s\n", series(name, m.labels), formatFloat(v))
+			}
+		}
+	}
+
+	family("beacon_monitor_up", "gauge", "Whether the last check of the monitor succeeded.",
+		func(m *monitor) (float64, bool) { return boolFloat(m.up), true })
+	family("beacon_monitor_last_response_time_seconds", "gauge", "Response time of the last check.",
+		func(m *monitor) (float64, bool) { return m.responseTime.Seconds(), true })
+	family("beacon_monitor_status_code", "gauge", "HTTP status code of the last check, 0 if the request failed.",
+		func(m *monitor) (float64, bool) { return float64(m.statusCode), true })
+	family("beacon_monitor_cert_expiry_timestamp_seconds", "gauge", "Expiry of the TLS certificate as Unix time.",
+		func(m *monitor) (float64, bool) {
+			if m.certExpiry == nil {
+				return 0, false
+			}
+			return float64(m.certExpiry.Unix()), true
+		})
+	family("beacon_monitor_checks_total", "counter", "Checks of the monitor.",
+		func(m *monitor) (float64, bool) { return float64(m.checks), true })
+	family("beacon_monitor_failures_total", "counter", "Failed checks of the monitor.",
+		func(m *monitor) (float64, bool) { return float64(m.failures), true })
+
+	name := "beacon_monitor_response_time_seconds"
+	fmt.Fprintf(w, "# HELP %s Response times of checks.\n# TYPE %s histogram\n", name, name)
+	for _, id := range ids {
+		monitors[id].responseTimes.write(w, name, monitors[id].labels)
+	}
+
+	name = "beacon_notifications_total"
+	fmt.Fprintf(w, "# HELP %s Notification delivery attempts by outcome.\n# TYPE %s counter\n", name, name)
+	keys := slices.SortedFunc(maps.Keys(deliveries), func(a, b delivery) int {
+		return strings.Compare(a.channel+"\x00"+a.status, b.channel+"\x00"+b.status)
+	})
+	for _, d := range keys {
+		fmt.Fprintf(w, "%s %d\n", series(name, labels("channel", d.channel, "status", d.status)), deliveries[d])
+	}
+
+	name = "beacon_db_write_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s Latency of database writes.\n# TYPE %s histogram\n", name, name)
+	dbWrites.write(w, name, "")
+
+	name = "beacon_db_query_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s Latency of database queries.\n# TYPE %s histogram\n", name, name)
+	dbQueries.write(w, name, "")
+
+	fmt.Fprintf(w, "# HELP beacon_scheduler_monitors Monitors checked by the scheduler.\n")
+	fmt.Fprintf(w, "# TYPE beacon_scheduler_monitors gauge\nbeacon_scheduler_monitors %d\n", scheduled.Load())
+	fmt.Fprintf(w, "# HELP go_goroutines Number of goroutines that currently exist.\n")
+	fmt.Fprintf(w, "# TYPE go_goroutines gauge\ngo_goroutines %d\n", runtime.NumGoroutine())
+}
+
+// histogram counts observations into buckets
+type histogram struct {
+	bounds []float64
+	counts []uint64 // per bucket, the last one for observations above all bounds
+	sum    float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+}
+
+func (h *histogram) observe(v float64) {
+	i, _ := slices.BinarySearch(h.bounds, v)
+	h.counts[i]++
+	h.sum += v
+}
+
+// write renders the cumulative buckets, sum and count of the histogram
+func (h *histogram) write(w *bufio.Writer, name, labels string) {
+	sep := ""
+	if labels != "" {
+		sep = ","
+	}
+
+	var count uint64
+	for i, bound := range h.bounds {
+		count += h.counts[i]
+		fmt.Fprintf(w, "%s_bucket{%s%sle=\"%s\"} %d\n", name, labels, sep, formatFloat(bound), count)
+	}
+	count += h.counts[len(h.bounds)]
+	fmt.Fprintf(w, "%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, sep, count)
+	fmt.Fprintf(w, "%s %s\n", series(name+"_sum", labels), formatFloat(h.sum))
+	fmt.Fprintf(w, "%s %d\n", series(name+"_count", labels), count)
+}
+
+// series returns the name of a time series with its labels
+func series(name, labels string) string {
+	if labels == "" {
+		return name
+	}
+	return name + "{" + labels + "}"
+}
+
+// labels renders name and value pairs as a label set
+func labels(pairs ...string) string {
+	var b strings.Builder
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", pairs[i], labelEscaper.Replace(pairs[i+1]))
+	}
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+// certWarnings are the times before a TLS certificate expires at which its
+// monitor's channels are warned, in descending order
+var certWarnings = []time.Duration{14 * 24 * time.Hour, 7 * 24 * time.Hour, 24 * time.Hour}
+
+// certWarningSince returns when the current warning period of a certificate
+// began, if it expires soon but hasn't expired yet
+func certWarningSince(expiresAt, now time.Time) (time.Time, bool) {
+	left := expiresAt.Sub(now)
+	if left <= 0 || left > certWarnings[0] {
+		return time.Time{}, false
+	}
+
+	since := expiresAt.Add(-certWarnings[0])
+	for _, d := range certWarnings[1:] {
+		if left <= d {
+			since = expiresAt.Add(-d)
+		}
+	}
+	return since, true
+}
+
+// SendCertExpiringNotification warns the channels of a monitor that its TLS
+// certificate expires soon, once per warning period. Queued warnings are
+// looked up, so restarts don't repeat them.
+func (n *Notifier) SendCertExpiringNotification(
+	ctx context.Context,
+	monitor *db.Monitor,
+	expiresAt time.Time,
+) error {
+	if monitor == nil {
+		return nil
+	}
+	channels := n.channelsFor(monitor)
+	if len(channels) == 0 {
+		return nil
+	}
+	since, ok := certWarningSince(expiresAt, time.Now())
+	if !ok {
+		return nil
+	}
+
+	sent, err := n.conn.Q.CountMonitorNotifications(ctx, &db.CountMonitorNotificationsParams{
+		MonitorID: &monitor.ID,
+		EventType: string(EventCertExpiring),
+		Since:     since.Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count certificate warnings: %w", err)
+	}
+	if sent > 0 {
+		return nil
+	}
+
+	event := n.monitorEvent(ctx, EventCertExpiring, monitor)
+	event.ExpiresAt = &expiresAt
+	event.DaysLeft = int(math.Ceil(time.Until(expiresAt).Hours() / 24))
+	return n.notifyChannels(ctx, event, channels)
+}
@@ -0,0 +1,38 @@
+package notify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCertWarningSince(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		left   time.Duration
+		want   time.Duration // before expiry
+		wantOK bool
+	}{
+		{"far away", 30 * day, 0, false},
+		{"first warning", 14 * day, 14 * day, true},
+		{"within two weeks", 10 * day, 14 * day, true},
+		{"within a week", 7 * day, 7 * day, true},
+		{"within days", 3 * day, 7 * day, true},
+		{"last day", 5 * time.Hour, day, true},
+		{"expired", -time.Hour, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expiresAt := now.Add(tt.left)
+			got, ok := certWarningSince(expiresAt, now)
+			if ok != tt.wantOK {
+				t.Fatalf("certWarningSince() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !got.Equal(expiresAt.Add(-tt.want)) {
+				t.Errorf("certWarningSince() = %v, want %v", got, expiresAt.Add(-tt.want))
+			}
+		})
+	}
+}
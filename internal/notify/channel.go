@@ -0,0 +1,260 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+// Channel delivers events to an external service such as a chat room.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, event *Event) error
+}
+
+// ChannelConfig describes a notification channel in the config file.
+type ChannelConfig struct {
+	Name      string   `yaml:"name"`
+	Type      string   `yaml:"type"`                 // telegram, matrix or smtp
+	Monitors  []string `yaml:"monitors,omitempty"`   // empty means all monitors
+	Recovery  string   `yaml:"recovery,omitempty"`   // reply (default) or edit
+	RateLimit int      `yaml:"rate_limit,omitempty"` // messages per minute
+
+	// Message contents
+	Language  string                       `yaml:"language,omitempty"`  // BEACON_LANGUAGE by default
+	Templates map[EventType]TemplateConfig `yaml:"templates,omitempty"` // per event type
+
+	// Delivery schedule
+	Digest     time.Duration `yaml:"digest,omitempty"`      // batch events into one message per interval
+	QuietHours []string      `yaml:"quiet_hours,omitempty"` // e.g. "22:00-07:00", in BEACON_TIMEZONE
+
+	// Telegram
+	BotToken string `yaml:"bot_token,omitempty"`
+	ChatID   string `yaml:"chat_id,omitempty"`
+
+	// Matrix
+	Homeserver  string `yaml:"homeserver,omitempty"`
+	AccessToken string `yaml:"access_token,omitempty"`
+	RoomID      string `yaml:"room_id,omitempty"`
+
+	// SMTP
+	Host     string   `yaml:"host,omitempty"`
+	Port     int      `yaml:"port,omitempty"` // 587 (STARTTLS) by default, 465 for implicit TLS
+	Username string   `yaml:"username,omitempty"`
+	Password string   `yaml:"password,omitempty"`
+	From     string   `yaml:"from,omitempty"`
+	To       []string `yaml:"to,omitempty"`
+
+	// Deliver status page email subscriptions through this channel
+	Subscribers         bool `yaml:"subscribers,omitempty"`
+	SubscriberRateLimit int  `yaml:"subscriber_rate_limit,omitempty"` // emails per hour and address
+}
+
+// Valid values for enums
+var (
+	ValidChannelTypes = []string{"telegram", "matrix", "smtp"}
+	ValidRecoveries   = []string{"reply", "edit"}
+)
+
+// Validate checks that all fields required by the channel type are set
+func (c *ChannelConfig) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if c.Name == WebPush {
+		return fmt.Errorf("name %q is reserved", WebPush)
+	}
+	if !slices.Contains(ValidChannelTypes, c.Type) {
+		return fmt.Errorf("invalid type '%s': must be one of %v", c.Type, ValidChannelTypes)
+	}
+	if c.Recovery != "" && !slices.Contains(ValidRecoveries, c.Recovery) {
+		return fmt.Errorf("invalid recovery '%s': must be one of %v", c.Recovery, ValidRecoveries)
+	}
+
+	if c.Language != "" && !slices.Contains(ValidLanguages, c.Language) {
+		return fmt.Errorf("invalid language '%s': must be one of %v", c.Language, ValidLanguages)
+	}
+	if _, err := NewMessages(DefaultLanguage, c.Templates); err != nil {
+		return err
+	}
+
+	if c.Digest < 0 || (c.Digest > 0 && c.Digest < time.Minute) {
+		return fmt.Errorf("digest must be at least 1m")
+	}
+	for _, q := range c.QuietHours {
+		if _, err := parseQuietHours(q); err != nil {
+			return err
+		}
+	}
+
+	switch c.Type {
+	case "telegram":
+		if c.BotToken == "" || c.ChatID == "" {
+			return fmt.Errorf("telegram channel requires bot_token and chat_id")
+		}
+		// Chats are addressed by ID, public channels also by @username
+		chatID := os.ExpandEnv(c.ChatID)
+		if _, err := strconv.ParseInt(chatID, 10, 64); err != nil && !strings.HasPrefix(chatID, "@") {
+			return fmt.Errorf("invalid chat_id %q: must be a chat ID or @username", chatID)
+		}
+	case "matrix":
+		if c.Homeserver == "" || c.AccessToken == "" || c.RoomID == "" {
+			return fmt.Errorf("matrix channel requires homeserver, access_token and room_id")
+		}
+	case "smtp":
+		if c.Host == "" || c.From == "" {
+			return fmt.Errorf("smtp channel requires host and from")
+		}
+		if _, err := mail.ParseAddress(os.ExpandEnv(c.From)); err != nil {
+			return fmt.Errorf("invalid from %q: %w", c.From, err)
+		}
+		if len(c.To) == 0 && !c.Subscribers {
+			return fmt.Errorf("smtp channel requires to or subscribers")
+		}
+		if c.Port < 0 || c.Port > 65535 {
+			return fmt.Errorf("invalid port %d", c.Port)
+		}
+	}
+
+	if c.Subscribers && c.Type != "smtp" {
+		return fmt.Errorf("subscribers requires an smtp channel")
+	}
+	if c.SubscriberRateLimit < 0 {
+		return fmt.Errorf("subscriber_rate_limit must not be negative")
+	}
+	return nil
+}
+
+// recipientChannel delivers events to individual recipients, e.g. email subscribers
+type recipientChannel interface {
+	SendTo(ctx context.Context, event *Event, to string) error
+}
+
+// route pairs a channel with the monitors it should receive events for
+type route struct {
+	channel  Channel
+	monitors []string
+}
+
+func (r *route) matches(monitor *db.Monitor) bool {
+	return len(r.monitors) == 0 || slices.Contains(r.monitors, monitor.Name)
+}
+
+func newChannel(cfg ChannelConfig) (Channel, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("channel %q: %w", cfg.Name, err)
+	}
+
+	edit := cfg.Recovery == "edit"
+
+	// Allow secrets to be injected from the environment, e.g. "${TELEGRAM_TOKEN}"
+	switch cfg.Type {
+	case "telegram":
+		return newTelegram(cfg.Name, os.ExpandEnv(cfg.BotToken), os.ExpandEnv(cfg.ChatID), edit), nil
+	case "matrix":
+		return newMatrix(
+			cfg.Name,
+			os.ExpandEnv(cfg.Homeserver),
+			os.ExpandEnv(cfg.AccessToken),
+			os.ExpandEnv(cfg.RoomID),
+			edit,
+		), nil
+	case "smtp":
+		to := make([]string, len(cfg.To))
+		for i, addr := range cfg.To {
+			to[i] = os.ExpandEnv(addr)
+		}
+		from, err := mail.ParseAddress(os.ExpandEnv(cfg.From))
+		if err != nil {
+			return nil, fmt.Errorf("channel %q: invalid from: %w", cfg.Name, err)
+		}
+		return newSMTP(
+			cfg.Name,
+			os.ExpandEnv(cfg.Host),
+			cfg.Port,
+			os.ExpandEnv(cfg.Username),
+			os.ExpandEnv(cfg.Password),
+			from,
+			to,
+		), nil
+	}
+	return nil, fmt.Errorf("channel %q: unsupported type %q", cfg.Name, cfg.Type)
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// doJSON sends body as JSON and decodes the JSON response into out
+func doJSON(
+	ctx context.Context,
+	method, endpoint string,
+	headers map[string]string,
+	body, out any,
+) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Beacon/1.0")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		// Don't leak tokens embedded in the request URL into the logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("service returned status %d: %s", resp.StatusCode, respBody)
+		if permanentStatus(resp.StatusCode) {
+			return fmt.Errorf("%w: %v", errPermanent, err)
+		}
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// permanentStatus reports whether a request rejected with status code would
+// be rejected again, unlike timeouts, rate limits and server errors
+func permanentStatus(code int) bool {
+	return code >= 400 && code < 500 &&
+		code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
+}
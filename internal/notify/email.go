@@ -0,0 +1,192 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/incidents"
+	"github.com/mizuchilabs/beacon/internal/util"
+)
+
+const (
+	// defaultSubscriberRate limits the emails a single subscriber receives per hour
+	defaultSubscriberRate = 10
+
+	// confirmInterval is the minimum time between two confirmation emails to
+	// the same address, so the subscribe form can't be used to flood inboxes
+	confirmInterval = 10 * time.Minute
+)
+
+// ErrEmailDisabled is returned when no channel delivers to email subscribers
+var ErrEmailDisabled = errors.New("email subscriptions are disabled")
+
+// EmailEnabled reports whether visitors can subscribe by email
+func (n *Notifier) EmailEnabled() bool {
+	return n.emailChannel != "" && n.baseURL != ""
+}
+
+// SubscribeEmail registers an address for updates of a monitor, or of all
+// monitors and incidents if monitorID is nil, and mails it a confirmation
+// link. Confirmed subscriptions are left as they are.
+func (n *Notifier) SubscribeEmail(ctx context.Context, email string, monitorID *int64) error {
+	if !n.EmailEnabled() {
+		return ErrEmailDisabled
+	}
+
+	var monitor *db.Monitor
+	if monitorID != nil {
+		var err error
+		if monitor, err = n.conn.Q.GetMonitor(ctx, *monitorID); err != nil {
+			return fmt.Errorf("failed to get monitor: %w", err)
+		}
+	}
+
+	sub, err := n.conn.Q.GetEmailSubscriberByAddress(ctx, &db.GetEmailSubscriberByAddressParams{
+		Email:     email,
+		MonitorID: monitorID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		sub, err = n.conn.Q.CreateEmailSubscriber(ctx, &db.CreateEmailSubscriberParams{
+			Email:     email,
+			MonitorID: monitorID,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save subscriber: %w", err)
+	}
+	if sub.ConfirmedAt != nil {
+		return nil
+	}
+
+	recent, err := n.conn.Q.CountRecentNotifications(ctx, &db.CountRecentNotificationsParams{
+		Channel:   n.emailChannel,
+		Target:    email,
+		EventType: string(EventConfirm),
+		Since:     time.Now().Add(-confirmInterval).Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count confirmations: %w", err)
+	}
+	if recent > 0 {
+		slog.Debug("Skipping confirmation email, one was sent recently")
+		return nil
+	}
+
+	event := &Event{
+		Type:    EventConfirm,
+		Monitor: monitor,
+		ConfirmURL: fmt.Sprintf(
+			"%s/api/email/confirm?id=%d&token=%s",
+			n.baseURL,
+			sub.ID,
+			util.Sign(n.secret, confirmMessage(sub.ID)),
+		),
+		UnsubscribeURL: n.unsubscribeURL(email),
+	}
+	return n.enqueueEmail(ctx, event, email)
+}
+
+// VerifyConfirmToken reports whether token is a valid signature for the subscriber
+func (n *Notifier) VerifyConfirmToken(subscriberID int64, token string) bool {
+	return util.Verify(n.secret, confirmMessage(subscriberID), token)
+}
+
+// VerifyUnsubscribeToken reports whether token is a valid signature for the address
+func (n *Notifier) VerifyUnsubscribeToken(email, token string) bool {
+	return util.Verify(n.secret, unsubscribeMessage(email), token)
+}
+
+func confirmMessage(subscriberID int64) string {
+	return "email-confirm:" + strconv.FormatInt(subscriberID, 10)
+}
+
+func unsubscribeMessage(email string) string {
+	return "email-unsubscribe:" + strings.ToLower(email)
+}
+
+func (n *Notifier) unsubscribeURL(email string) string {
+	return fmt.Sprintf(
+		"%s/api/email/unsubscribe?email=%s&token=%s",
+		n.baseURL,
+		url.QueryEscape(email),
+		util.Sign(n.secret, unsubscribeMessage(email)),
+	)
+}
+
+// SendIncidentNotification mails an incident update to all subscribers of
+// the status page and of the affected monitors
+func (n *Notifier) SendIncidentNotification(ctx context.Context, incident incidents.Incident) {
+	if !n.EmailEnabled() {
+		return
+	}
+
+	subscribers, err := n.conn.Q.GetConfirmedEmailSubscribers(ctx)
+	if err != nil {
+		slog.Error("Failed to get email subscribers", "error", err)
+		return
+	}
+
+	event := &Event{Type: EventIncident, Incident: &incident, Time: incident.StartedAt}
+	if latest := incident.LatestUpdate(); latest != nil {
+		event.Time = latest.CreatedAt
+	}
+
+	var emails []string
+	for _, sub := range subscribers {
+		if sub.MonitorName == nil || slices.Contains(incident.AffectedMonitors, *sub.MonitorName) {
+			emails = append(emails, sub.Email)
+		}
+	}
+	if err := n.notifySubscribers(ctx, event, emails); err != nil {
+		slog.Error("Failed to queue incident notification", "incident", incident.ID, "error", err)
+	}
+}
+
+// notifyEmailSubscribers queues outage updates of a monitor for its email subscribers
+func (n *Notifier) notifyEmailSubscribers(ctx context.Context, event *Event) error {
+	if !n.EmailEnabled() || event.Monitor == nil {
+		return nil
+	}
+
+	emails, err := n.conn.Q.GetEmailSubscribersByMonitor(ctx, &event.Monitor.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get email subscribers: %w", err)
+	}
+	return n.notifySubscribers(ctx, event, emails)
+}
+
+func (n *Notifier) notifySubscribers(ctx context.Context, event *Event, emails []string) error {
+	var errs []error
+	seen := make(map[string]bool, len(emails))
+	for _, email := range emails {
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+
+		// Acknowledgement links are meant for the team only
+		e := *event
+		e.AckURL = ""
+		e.UnsubscribeURL = n.unsubscribeURL(email)
+		errs = append(errs, n.enqueueEmail(ctx, &e, email))
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) enqueueEmail(ctx context.Context, event *Event, email string) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return n.enqueue(ctx, event, n.emailChannel, email, payload)
+}
@@ -0,0 +1,244 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/util"
+)
+
+const escalationInterval = 15 * time.Second
+
+// State of checks that failed because a parent monitor is down, as stored by
+// the scheduler
+const checkStateDependencyDown = "dependency_down"
+
+// EscalationPolicy notifies more channels the longer an alert stays unacknowledged
+type EscalationPolicy struct {
+	Name  string           `yaml:"name"`
+	Steps []EscalationStep `yaml:"steps"`
+}
+
+type EscalationStep struct {
+	After    time.Duration `yaml:"after"`
+	Channels []string      `yaml:"channels"`
+}
+
+// Validate checks that the steps are ordered and only use known channels
+func (p *EscalationPolicy) Validate(channels []string) error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(p.Steps) == 0 {
+		return fmt.Errorf("at least one step is required")
+	}
+	for i, step := range p.Steps {
+		if step.After < 0 {
+			return fmt.Errorf("step #%d: after must not be negative", i+1)
+		}
+		if i > 0 && step.After < p.Steps[i-1].After {
+			return fmt.Errorf("step #%d: steps must be ordered by after", i+1)
+		}
+		if len(step.Channels) == 0 {
+			return fmt.Errorf("step #%d: at least one channel is required", i+1)
+		}
+		for _, channel := range step.Channels {
+			if !slices.Contains(channels, channel) {
+				return fmt.Errorf("step #%d: unknown channel %q", i+1, channel)
+			}
+		}
+	}
+	return nil
+}
+
+// channels returns the distinct channels of the first n steps
+func (p *EscalationPolicy) channels(n int) []string {
+	var channels []string
+	for _, step := range p.Steps[:min(n, len(p.Steps))] {
+		for _, channel := range step.Channels {
+			if !slices.Contains(channels, channel) {
+				channels = append(channels, channel)
+			}
+		}
+	}
+	return channels
+}
+
+// Acknowledge stops further escalation of an open alert
+func (n *Notifier) Acknowledge(ctx context.Context, alertID int64, by string) (*db.Alert, error) {
+	return n.conn.Q.AcknowledgeAlert(ctx, &db.AcknowledgeAlertParams{
+		AcknowledgedBy: &by,
+		ID:             alertID,
+	})
+}
+
+// AcknowledgeMonitor acknowledges the open alert of a monitor
+func (n *Notifier) AcknowledgeMonitor(
+	ctx context.Context,
+	monitorID int64,
+	by string,
+) (*db.Alert, error) {
+	alert, err := n.conn.Q.GetOpenAlert(ctx, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	return n.Acknowledge(ctx, alert.ID, by)
+}
+
+// SnoozeMonitor pauses the escalation of a monitor's open alert until the given time
+func (n *Notifier) SnoozeMonitor(
+	ctx context.Context,
+	monitorID int64,
+	until time.Time,
+	by string,
+) (*db.Alert, error) {
+	alert, err := n.conn.Q.GetOpenAlert(ctx, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	return n.conn.Q.SnoozeAlert(ctx, &db.SnoozeAlertParams{
+		Until:     until.Unix(),
+		SnoozedBy: &by,
+		ID:        alert.ID,
+	})
+}
+
+// VerifyAlertToken reports whether token is a valid signature for the alert
+func (n *Notifier) VerifyAlertToken(alertID int64, token string) bool {
+	return util.Verify(n.secret, alertMessage(alertID), token)
+}
+
+func (n *Notifier) alertToken(alertID int64) string {
+	return util.Sign(n.secret, alertMessage(alertID))
+}
+
+func alertMessage(alertID int64) string {
+	return "alert:" + strconv.FormatInt(alertID, 10)
+}
+
+// escalate notifies all steps of the policy that are due but weren't notified yet
+func (n *Notifier) escalate(
+	ctx context.Context,
+	alert *db.Alert,
+	policy *EscalationPolicy,
+	monitor *db.Monitor,
+) error {
+	elapsed := escalationElapsed(alert, policy, time.Now())
+	step := int(alert.Step)
+	for step < len(policy.Steps) && policy.Steps[step].After <= elapsed {
+		step++
+	}
+	if step == int(alert.Step) {
+		return nil
+	}
+
+	slog.Info("Escalating alert",
+		"alert_id", alert.ID,
+		"monitor_id", monitor.ID,
+		"policy", policy.Name,
+		"step", step,
+	)
+
+	event := n.alertEvent(ctx, EventDown, monitor, alert)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	for _, s := range policy.Steps[alert.Step:step] {
+		for _, channel := range s.Channels {
+			if err := n.enqueue(ctx, event, channel, "", payload); err != nil {
+				return err
+			}
+		}
+	}
+
+	return n.conn.Q.UpdateAlertStep(ctx, &db.UpdateAlertStepParams{
+		Step: int64(step),
+		ID:   alert.ID,
+	})
+}
+
+// escalationElapsed returns how long an alert has been escalating at now. A
+// snooze pauses the clock, so once it's over escalation resumes from the last
+// notified step instead of catching up on all steps due in the meantime.
+func escalationElapsed(alert *db.Alert, policy *EscalationPolicy, now time.Time) time.Duration {
+	elapsed := now.Sub(alert.StartedAt)
+	if alert.SnoozedUntil == nil || alert.SnoozedUntil.After(now) {
+		return elapsed
+	}
+
+	var resumed time.Duration
+	if alert.Step > 0 {
+		resumed = policy.Steps[min(int(alert.Step), len(policy.Steps))-1].After
+	}
+	return min(elapsed, resumed+now.Sub(*alert.SnoozedUntil))
+}
+
+// escalationPaused reports whether the alert of a monitor must not escalate
+// for now. Planned work shouldn't page anyone, and neither should a monitor
+// that only fails because a dependency is down or that keeps flapping.
+// Escalation goes on if the monitor is still down afterwards.
+func (n *Notifier) escalationPaused(ctx context.Context, monitor *db.Monitor) bool {
+	if monitor.Flapping {
+		return true
+	}
+	if _, ok := n.maintenance.Active(monitor.Name, monitor.GroupName, time.Now()); ok {
+		return true
+	}
+	check, err := n.conn.Q.GetLatestCheck(ctx, monitor.ID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Error("Failed to get latest check", "monitor_id", monitor.ID, "error", err)
+		}
+		return false
+	}
+	return check.State != nil && *check.State == checkStateDependencyDown
+}
+
+// escalationJob periodically escalates alerts nobody has acknowledged yet
+func (n *Notifier) escalationJob(ctx context.Context) {
+	ticker := time.NewTicker(escalationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			alerts, err := n.conn.Q.GetEscalatingAlerts(ctx)
+			if err != nil {
+				slog.Error("Failed to get escalating alerts", "error", err)
+				continue
+			}
+
+			for _, alert := range alerts {
+				policy, ok := n.policies[alert.Policy]
+				if !ok {
+					continue // policy was removed from the config
+				}
+				monitor, err := n.conn.Q.GetMonitor(ctx, alert.MonitorID)
+				if errors.Is(err, sql.ErrNoRows) {
+					continue
+				}
+				if err != nil {
+					slog.Error("Failed to get monitor", "monitor_id", alert.MonitorID, "error", err)
+					continue
+				}
+				if n.escalationPaused(ctx, monitor) {
+					continue
+				}
+				if err := n.escalate(ctx, alert, policy, monitor); err != nil {
+					slog.Error("Failed to escalate alert", "alert_id", alert.ID, "error", err)
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
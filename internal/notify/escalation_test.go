@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+func TestEscalationElapsed(t *testing.T) {
+	start := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	policy := &EscalationPolicy{Steps: []EscalationStep{
+		{After: 0},
+		{After: 10 * time.Minute},
+		{After: 30 * time.Minute},
+	}}
+	at := func(d time.Duration) *time.Time {
+		t := start.Add(d)
+		return &t
+	}
+
+	tests := []struct {
+		name    string
+		step    int64
+		snoozed *time.Time
+		now     time.Duration
+		want    time.Duration
+	}{
+		{"not snoozed", 1, nil, 12 * time.Minute, 12 * time.Minute},
+		{"still snoozed", 1, at(time.Hour), 40 * time.Minute, 40 * time.Minute},
+		{"resumes from first step", 1, at(time.Hour), 65 * time.Minute, 5 * time.Minute},
+		{"resumes from second step", 2, at(time.Hour), 61 * time.Minute, 11 * time.Minute},
+		{"before any step", 0, at(5 * time.Minute), 7 * time.Minute, 2 * time.Minute},
+		{"never beyond start", 1, at(-time.Hour), 3 * time.Minute, 3 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := &db.Alert{StartedAt: start, Step: tt.step, SnoozedUntil: tt.snoozed}
+			if got := escalationElapsed(alert, policy, start.Add(tt.now)); got != tt.want {
+				t.Errorf("escalationElapsed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/incidents"
+)
+
+type EventType string
+
+const (
+	EventDown     EventType = "down"
+	EventUp       EventType = "up"
+	EventFlapping EventType = "flapping"
+	EventStable   EventType = "stable"
+	EventDegraded EventType = "degraded" // up, but slower than its threshold
+	EventNormal   EventType = "normal"   // responding in time again
+	EventDigest   EventType = "digest"
+	EventIncident EventType = "incident"
+	EventConfirm  EventType = "confirm" // email subscription confirmation
+	EventTest     EventType = "test"    // sent on demand to verify delivery
+
+	// Error budget of a service level objective burns too fast, or not anymore
+	EventBudgetBurn      EventType = "budget_burn"
+	EventBudgetRecovered EventType = "budget_recovered"
+
+	// TLS certificate of a monitor expires soon
+	EventCertExpiring EventType = "cert_expiring"
+)
+
+// Event describes a monitor state change that subscribers should hear about
+type Event struct {
+	Type    EventType   `json:"type"`
+	Monitor *db.Monitor `json:"monitor"`
+	Reason  string      `json:"reason,omitempty"`
+	Time    time.Time   `json:"time"`
+	AlertID int64       `json:"alert_id,omitempty"`
+	AckURL  string      `json:"ack_url,omitempty"`
+	Events  []*Event    `json:"events,omitempty"` // summarized events of a digest
+
+	// Incident updates have no monitor
+	Incident *incidents.Incident `json:"incident,omitempty"`
+
+	// Latest check of the monitor when the event happened
+	Check *db.Check `json:"check,omitempty"`
+
+	// Expiry of the TLS certificate and the days left until then
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	DaysLeft  int        `json:"days_left,omitempty"`
+
+	// Links for email subscribers
+	ConfirmURL     string `json:"confirm_url,omitempty"`
+	UnsubscribeURL string `json:"unsubscribe_url,omitempty"`
+
+	// messages renders the event, set by the channel delivering it
+	messages *Messages
+
+	// thread references the chat message of the down alert that a recovery
+	// replies to or edits. Channels set it to the message a down alert was
+	// posted as.
+	thread string
+}
+
+// Title renders the title of the event in the messages of its channel
+func (e *Event) Title() string {
+	return e.msgs().Title(e)
+}
+
+// Body renders the body of the event in the messages of its channel
+func (e *Event) Body() string {
+	return e.msgs().Body(e)
+}
+
+func (e *Event) msgs() *Messages {
+	if e.messages != nil {
+		return e.messages
+	}
+	return defaultMessages
+}
+
+// defaultMessages renders events that weren't assigned to a channel
+var defaultMessages = func() *Messages {
+	m, err := NewMessages(DefaultLanguage, nil)
+	if err != nil {
+		panic(err) // built-in templates must parse
+	}
+	return m
+}()
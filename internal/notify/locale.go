@@ -0,0 +1,235 @@
+package notify
+
+// ValidLanguages lists the languages notifications are translated to
+var ValidLanguages = []string{"en", "de", "fr"}
+
+// translation holds the built-in messages of a language
+type translation struct {
+	templates   map[EventType]TemplateConfig
+	statuses    map[string]string // incident statuses
+	unsubscribe string
+}
+
+func (t *translation) status(s string) string {
+	if translated, ok := t.statuses[s]; ok {
+		return translated
+	}
+	return capitalize(s)
+}
+
+// Bodies are trimmed after rendering, so templates can end with a newline
+const digestBody = `{{range .Events}}{{.Time.Format "15:04"}} {{title .}}
+{{end}}`
+
+// incidentBody shows the latest update of an incident, or its description
+func incidentBody(colon string) string {
+	return `{{with .Incident.LatestUpdate}}{{status .Status}}` + colon +
+		` {{.Message}}{{else}}{{.Incident.Description}}{{end}}`
+}
+
+var translations = map[string]*translation{
+	"en": {
+		templates: map[EventType]TemplateConfig{
+			EventDown: {
+				Title: "🔴 {{.Monitor.Name}} is Down",
+				Body: `{{.Monitor.Url}} is currently unreachable. Reason: {{.Reason}}
+{{with .AckURL}}Acknowledge: {{.}}{{end}}`,
+			},
+			EventUp: {
+				Title: "✅ {{.Monitor.Name}} is Back Up",
+				Body:  "{{.Monitor.Url}} is now responding normally.",
+			},
+			EventFlapping: {
+				Title: "⚠️ {{.Monitor.Name}} is Flapping",
+				Body:  "{{.Monitor.Url}} keeps changing between up and down ({{.Reason}}). Further up/down notifications are paused until it stabilizes.",
+			},
+			EventStable: {
+				Title: "✅ {{.Monitor.Name}} is Stable",
+				Body:  "{{.Monitor.Url}} stopped flapping and is responding normally.",
+			},
+			EventDegraded: {
+				Title: "🐢 {{.Monitor.Name}} is Degraded",
+				Body:  "{{.Monitor.Url}} is responding slowly: {{.Reason}}.",
+			},
+			EventNormal: {
+				Title: "✅ {{.Monitor.Name}} is Back to Normal",
+				Body:  "{{.Monitor.Url}} is responding in time again.",
+			},
+			EventCertExpiring: {
+				Title: "🔒 Certificate of {{.Monitor.Name}} Expires {{if le .DaysLeft 1}}Within a Day{{else}}in {{.DaysLeft}} Days{{end}}",
+				Body:  `The TLS certificate of {{.Monitor.Url}} expires on {{.ExpiresAt.UTC.Format "2006-01-02 15:04"}} UTC. Renew it to keep the monitor reachable.`,
+			},
+			EventBudgetBurn: {
+				Title: "🔥 {{.Monitor.Name}} is Burning its Error Budget",
+				Body:  "{{.Reason}}.",
+			},
+			EventBudgetRecovered: {
+				Title: "✅ {{.Monitor.Name}} Stopped Burning its Error Budget",
+				Body:  "{{.Monitor.Url}} no longer spends its error budget faster than sustainable.",
+			},
+			EventDigest: {
+				Title: "📋 {{len .Events}} Monitor Updates",
+				Body:  digestBody,
+			},
+			EventIncident: {
+				Title: `{{if eq .Incident.Status "resolved"}}✅ Resolved: {{else}}📢 {{end}}{{.Incident.Title}}`,
+				Body: incidentBody(":") + `
+{{with .Incident.AffectedMonitors}}Affected: {{join . ", "}}{{end}}`,
+			},
+			EventConfirm: {
+				Title: "Confirm your subscription",
+				Body: `Please confirm that you want to receive status updates for {{with .Monitor}}{{.Name}}{{else}}all monitors{{end}}:
+{{.ConfirmURL}}
+
+If you didn't subscribe, you can ignore this email.`,
+			},
+			EventTest: {
+				Title: "🔔 Test notification for {{.Monitor.Name}}",
+				Body:  "This is a test notification from Beacon. If you can read this, notifications for {{.Monitor.Name}} are delivered.",
+			},
+		},
+		statuses: map[string]string{
+			"investigating": "Investigating",
+			"identified":    "Identified",
+			"monitoring":    "Monitoring",
+			"resolved":      "Resolved",
+		},
+		unsubscribe: "Unsubscribe",
+	},
+	"de": {
+		templates: map[EventType]TemplateConfig{
+			EventDown: {
+				Title: "🔴 {{.Monitor.Name}} ist nicht erreichbar",
+				Body: `{{.Monitor.Url}} ist derzeit nicht erreichbar. Grund: {{.Reason}}
+{{with .AckURL}}Bestätigen: {{.}}{{end}}`,
+			},
+			EventUp: {
+				Title: "✅ {{.Monitor.Name}} ist wieder erreichbar",
+				Body:  "{{.Monitor.Url}} antwortet wieder normal.",
+			},
+			EventFlapping: {
+				Title: "⚠️ {{.Monitor.Name}} ist instabil",
+				Body:  "{{.Monitor.Url}} wechselt ständig zwischen erreichbar und nicht erreichbar ({{.Reason}}). Weitere Benachrichtigungen werden pausiert, bis der Zustand stabil ist.",
+			},
+			EventStable: {
+				Title: "✅ {{.Monitor.Name}} ist stabil",
+				Body:  "{{.Monitor.Url}} ist wieder stabil und antwortet normal.",
+			},
+			EventDegraded: {
+				Title: "🐢 {{.Monitor.Name}} ist beeinträchtigt",
+				Body:  "{{.Monitor.Url}} antwortet langsam: {{.Reason}}.",
+			},
+			EventNormal: {
+				Title: "✅ {{.Monitor.Name}} antwortet wieder normal",
+				Body:  "{{.Monitor.Url}} antwortet wieder rechtzeitig.",
+			},
+			EventCertExpiring: {
+				Title: "🔒 Zertifikat von {{.Monitor.Name}} läuft {{if le .DaysLeft 1}}innerhalb eines Tages{{else}}in {{.DaysLeft}} Tagen{{end}} ab",
+				Body:  `Das TLS-Zertifikat von {{.Monitor.Url}} läuft am {{.ExpiresAt.UTC.Format "02.01.2006 15:04"}} UTC ab. Erneuere es, damit der Monitor erreichbar bleibt.`,
+			},
+			EventBudgetBurn: {
+				Title: "🔥 {{.Monitor.Name}} verbraucht sein Fehlerbudget zu schnell",
+				Body:  "{{.Reason}}.",
+			},
+			EventBudgetRecovered: {
+				Title: "✅ Fehlerbudget von {{.Monitor.Name}} wird nicht mehr zu schnell verbraucht",
+				Body:  "{{.Monitor.Url}} verbraucht sein Fehlerbudget nicht mehr schneller als vertretbar.",
+			},
+			EventDigest: {
+				Title: "📋 {{len .Events}} Statusmeldungen",
+				Body:  digestBody,
+			},
+			EventIncident: {
+				Title: `{{if eq .Incident.Status "resolved"}}✅ Behoben: {{else}}📢 {{end}}{{.Incident.Title}}`,
+				Body: incidentBody(":") + `
+{{with .Incident.AffectedMonitors}}Betroffen: {{join . ", "}}{{end}}`,
+			},
+			EventConfirm: {
+				Title: "Bestätige dein Abonnement",
+				Body: `Bitte bestätige, dass du Statusmeldungen für {{with .Monitor}}{{.Name}}{{else}}alle Monitore{{end}} erhalten möchtest:
+{{.ConfirmURL}}
+
+Falls du dich nicht angemeldet hast, kannst du diese E-Mail ignorieren.`,
+			},
+			EventTest: {
+				Title: "🔔 Testbenachrichtigung für {{.Monitor.Name}}",
+				Body:  "Dies ist eine Testbenachrichtigung von Beacon. Wenn du sie lesen kannst, werden Benachrichtigungen für {{.Monitor.Name}} zugestellt.",
+			},
+		},
+		statuses: map[string]string{
+			"investigating": "Wird untersucht",
+			"identified":    "Ursache gefunden",
+			"monitoring":    "Wird beobachtet",
+			"resolved":      "Behoben",
+		},
+		unsubscribe: "Abmelden",
+	},
+	"fr": {
+		templates: map[EventType]TemplateConfig{
+			EventDown: {
+				Title: "🔴 {{.Monitor.Name}} est hors service",
+				Body: `{{.Monitor.Url}} est actuellement injoignable. Raison : {{.Reason}}
+{{with .AckURL}}Acquitter : {{.}}{{end}}`,
+			},
+			EventUp: {
+				Title: "✅ {{.Monitor.Name}} est rétabli",
+				Body:  "{{.Monitor.Url}} répond de nouveau normalement.",
+			},
+			EventFlapping: {
+				Title: "⚠️ {{.Monitor.Name}} est instable",
+				Body:  "{{.Monitor.Url}} alterne sans cesse entre disponible et indisponible ({{.Reason}}). Les notifications sont suspendues jusqu'à sa stabilisation.",
+			},
+			EventStable: {
+				Title: "✅ {{.Monitor.Name}} est stable",
+				Body:  "{{.Monitor.Url}} est de nouveau stable et répond normalement.",
+			},
+			EventDegraded: {
+				Title: "🐢 {{.Monitor.Name}} est dégradé",
+				Body:  "{{.Monitor.Url}} répond lentement : {{.Reason}}.",
+			},
+			EventNormal: {
+				Title: "✅ {{.Monitor.Name}} est revenu à la normale",
+				Body:  "{{.Monitor.Url}} répond de nouveau dans les temps.",
+			},
+			EventCertExpiring: {
+				Title: "🔒 Le certificat de {{.Monitor.Name}} expire {{if le .DaysLeft 1}}dans moins d'un jour{{else}}dans {{.DaysLeft}} jours{{end}}",
+				Body:  `Le certificat TLS de {{.Monitor.Url}} expire le {{.ExpiresAt.UTC.Format "02/01/2006 15:04"}} UTC. Renouvelez-le pour que le moniteur reste joignable.`,
+			},
+			EventBudgetBurn: {
+				Title: "🔥 {{.Monitor.Name}} consomme son budget d'erreur trop vite",
+				Body:  "{{.Reason}}.",
+			},
+			EventBudgetRecovered: {
+				Title: "✅ Le budget d'erreur de {{.Monitor.Name}} n'est plus consommé trop vite",
+				Body:  "{{.Monitor.Url}} ne consomme plus son budget d'erreur plus vite que soutenable.",
+			},
+			EventDigest: {
+				Title: "📋 {{len .Events}} mises à jour",
+				Body:  digestBody,
+			},
+			EventIncident: {
+				Title: `{{if eq .Incident.Status "resolved"}}✅ Résolu : {{else}}📢 {{end}}{{.Incident.Title}}`,
+				Body: incidentBody(" :") + `
+{{with .Incident.AffectedMonitors}}Concernés : {{join . ", "}}{{end}}`,
+			},
+			EventConfirm: {
+				Title: "Confirmez votre abonnement",
+				Body: `Veuillez confirmer que vous souhaitez recevoir les mises à jour de statut pour {{with .Monitor}}{{.Name}}{{else}}tous les moniteurs{{end}} :
+{{.ConfirmURL}}
+
+Si vous ne vous êtes pas abonné, vous pouvez ignorer cet e-mail.`,
+			},
+			EventTest: {
+				Title: "🔔 Notification de test pour {{.Monitor.Name}}",
+				Body:  "Ceci est une notification de test de Beacon. Si vous pouvez la lire, les notifications pour {{.Monitor.Name}} sont bien délivrées.",
+			},
+		},
+		statuses: map[string]string{
+			"investigating": "En cours d'analyse",
+			"identified":    "Cause identifiée",
+			"monitoring":    "Sous surveillance",
+			"resolved":      "Résolu",
+		},
+		unsubscribe: "Se désabonner",
+	},
+}
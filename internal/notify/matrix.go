@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Matrix posts events to a room through the Matrix client-server API
+type Matrix struct {
+	name       string
+	homeserver string
+	token      string
+	roomID     string
+	edit       bool
+	txnID      atomic.Int64
+}
+
+func newMatrix(name, homeserver, token, roomID string, edit bool) *Matrix {
+	m := &Matrix{
+		name:       name,
+		homeserver: strings.TrimRight(homeserver, "/"),
+		token:      token,
+		roomID:     roomID,
+		edit:       edit,
+	}
+	m.txnID.Store(time.Now().UnixNano())
+	return m
+}
+
+func (m *Matrix) Name() string {
+	return m.name
+}
+
+func (m *Matrix) Send(ctx context.Context, event *Event) error {
+	body := "**" + event.Title() + "**\n" + event.Body()
+	formatted := "<strong>" + html.EscapeString(event.Title()) + "</strong><br>" +
+		strings.ReplaceAll(html.EscapeString(event.Body()), "\n", "<br>")
+	content := map[string]any{
+		"msgtype":        "m.text",
+		"body":           body,
+		"format":         "org.matrix.custom.html",
+		"formatted_body": formatted,
+	}
+
+	if event.Type == EventUp && event.thread != "" {
+		eventID := event.thread
+		if m.edit {
+			// Edits carry a fallback body for clients that don't support them
+			content = map[string]any{
+				"msgtype":        "m.text",
+				"body":           "* " + body,
+				"format":         "org.matrix.custom.html",
+				"formatted_body": "* " + formatted,
+				"m.new_content":  content,
+				"m.relates_to":   map[string]any{"rel_type": "m.replace", "event_id": eventID},
+			}
+		} else {
+			content["m.relates_to"] = map[string]any{
+				"rel_type":        "m.thread",
+				"event_id":        eventID,
+				"is_falling_back": true,
+				"m.in_reply_to":   map[string]any{"event_id": eventID},
+			}
+		}
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		m.homeserver,
+		url.PathEscape(m.roomID),
+		strconv.FormatInt(m.txnID.Add(1), 10),
+	)
+	headers := map[string]string{"Authorization": "Bearer " + m.token}
+
+	var resp struct {
+		EventID string `json:"event_id"`
+	}
+	if err := doJSON(ctx, http.MethodPut, endpoint, headers, content, &resp); err != nil {
+		return fmt.Errorf("matrix send: %w", err)
+	}
+
+	if event.Type == EventDown {
+		event.thread = resp.EventID
+	}
+	return nil
+}
@@ -3,19 +3,55 @@ package notify
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/SherClockHolmes/webpush-go"
 	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/maintenance"
+	"golang.org/x/time/rate"
 )
 
 type Notifier struct {
-	conn      *db.Connection
-	vapidKeys *db.VapidKey
+	conn         *db.Connection
+	vapidSubject string
+	routes       []route
+	workers      map[string]*worker
+	policies     map[string]*EscalationPolicy
+	escalations  map[string]string
+	budgetAlerts map[string]bool
+	baseURL      string
+	secret       []byte
+	messages     *Messages // of push notifications
+	maintenance  *maintenance.Calendar
+
+	// emailChannel delivers to email subscribers, if set
+	emailChannel string
+}
+
+// Options configures where notifications are delivered
+type Options struct {
+	Channels    []ChannelConfig
+	Policies    []EscalationPolicy
+	Escalations map[string]string // monitor name -> escalation policy name
+	BaseURL     string            // public URL used for links in notifications
+	Secret      []byte            // key used to sign links
+	Location    *time.Location    // timezone of quiet hours and digests
+	VAPID       VAPIDOptions
+	Language    string // of built-in messages, English by default
+
+	// Monitors under maintenance don't escalate their open alerts
+	Maintenance *maintenance.Calendar
+
+	// Monitors whose channels are alerted by error budget burn rates instead
+	// of failed checks, by name
+	BudgetAlerts map[string]bool
 }
 
 type NotificationPayload struct {
@@ -25,38 +61,101 @@ type NotificationPayload struct {
 	MonitorID int64  `json:"monitorId"`
 }
 
-func New(ctx context.Context, conn *db.Connection) *Notifier {
-	result, err := conn.Q.VAPIDKeysExist(ctx)
+func New(ctx context.Context, conn *db.Connection, opts Options) *Notifier {
+	if err := setupVAPIDKeys(ctx, conn, opts.VAPID); err != nil {
+		log.Fatal(err)
+	}
+	if opts.VAPID.Subject == "" {
+		opts.VAPID.Subject = DefaultVAPIDSubject
+	}
+	if opts.Language == "" {
+		opts.Language = DefaultLanguage
+	}
+	messages, err := NewMessages(opts.Language, nil)
 	if err != nil {
-		log.Fatal(fmt.Errorf("failed to check VAPID keys: %w", err))
+		log.Fatal(err)
+	}
+
+	n := &Notifier{
+		conn:         conn,
+		vapidSubject: opts.VAPID.Subject,
+		routes:       make([]route, 0, len(opts.Channels)),
+		workers:      make(map[string]*worker, len(opts.Channels)+1),
+		policies:     make(map[string]*EscalationPolicy, len(opts.Policies)),
+		escalations:  opts.Escalations,
+		budgetAlerts: opts.BudgetAlerts,
+		baseURL:      strings.TrimRight(opts.BaseURL, "/"),
+		secret:       opts.Secret,
+		messages:     messages,
+		maintenance:  opts.Maintenance,
 	}
+	n.workers[WebPush] = newWorker(n.deliverPush, webPushRate, webPushBurst)
 
-	// Generate VAPID keys if missing
-	if result == 0 {
-		privateKey, publicKey, err := webpush.GenerateVAPIDKeys()
+	location := opts.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	for _, cfg := range opts.Channels {
+		channel, err := newChannel(cfg)
 		if err != nil {
-			log.Fatal(fmt.Errorf("failed to generate VAPID keys: %w", err))
+			log.Fatal(err)
 		}
-		if err := conn.Q.CreateVAPIDKeys(ctx, &db.CreateVAPIDKeysParams{
-			PublicKey:  publicKey,
-			PrivateKey: privateKey,
-		}); err != nil {
-			log.Fatal(fmt.Errorf("failed to store VAPID keys: %w", err))
+		language := cfg.Language
+		if language == "" {
+			language = opts.Language
+		}
+		messages, err := NewMessages(language, cfg.Templates)
+		if err != nil {
+			log.Fatalf("channel %q: %v", cfg.Name, err)
+		}
+		if cfg.Type != "smtp" || len(cfg.To) > 0 {
+			n.routes = append(n.routes, route{channel: channel, monitors: cfg.Monitors})
 		}
-	}
 
-	vapidKeys, err := conn.Q.GetVAPIDKeys(ctx)
-	if err != nil {
-		log.Fatal(fmt.Errorf("failed to get VAPID keys: %w", err))
+		perMinute := cfg.RateLimit
+		if perMinute <= 0 {
+			perMinute = defaultChannelRate
+		}
+		w := newWorker(
+			n.channelDelivery(channel, messages),
+			rate.Every(time.Minute/time.Duration(perMinute)),
+			channelBurst,
+		)
+		w.schedule = schedule{digest: cfg.Digest, location: location}
+		for _, q := range cfg.QuietHours {
+			hours, _ := parseQuietHours(q) // validated by newChannel
+			w.schedule.quietHours = append(w.schedule.quietHours, hours)
+		}
+		if cfg.Digest > 0 {
+			w.digest = channelDigest(channel, messages, location)
+		}
+		if cfg.Subscribers {
+			if n.emailChannel != "" {
+				log.Fatalf("channel %q: only one channel can deliver to subscribers", cfg.Name)
+			}
+			n.emailChannel = cfg.Name
+
+			perHour := cfg.SubscriberRateLimit
+			if perHour <= 0 {
+				perHour = defaultSubscriberRate
+			}
+			w.limitTargets(perHour)
+		}
+		n.workers[cfg.Name] = w
+	}
+	if n.emailChannel != "" && n.baseURL == "" {
+		slog.Warn("Email subscriptions require BEACON_URL for their links, disabling them")
 	}
 
-	return &Notifier{
-		conn:      conn,
-		vapidKeys: vapidKeys,
+	for i := range opts.Policies {
+		n.policies[opts.Policies[i].Name] = &opts.Policies[i]
 	}
+
+	return n
 }
 
-// SendMonitorDownNotification sends push notifications to all subscribers when a monitor goes down
+// SendMonitorDownNotification opens an alert and notifies subscribers when a monitor goes down
 func (n *Notifier) SendMonitorDownNotification(
 	ctx context.Context,
 	monitor *db.Monitor,
@@ -66,69 +165,316 @@ func (n *Notifier) SendMonitorDownNotification(
 		return nil
 	}
 
-	// Get all subscriptions for this monitor
-	subscriptions, err := n.conn.Q.GetPushSubscriptionsByMonitor(ctx, monitor.ID)
+	// Only alert once per outage, e.g. when restarting while still down
+	_, err := n.conn.Q.GetOpenAlert(ctx, monitor.ID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get open alert: %w", err)
+	}
+
+	// Burn rate alerts replace the escalation of failed checks
+	policyName := n.escalations[monitor.Name]
+	if n.budgetAlerts[monitor.Name] {
+		policyName = ""
+	}
+	alert, err := n.conn.Q.CreateAlert(ctx, &db.CreateAlertParams{
+		MonitorID: monitor.ID,
+		Policy:    policyName,
+		Reason:    reason,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get subscriptions: %w", err)
+		return fmt.Errorf("failed to create alert: %w", err)
 	}
 
-	if len(subscriptions) == 0 {
-		slog.Debug("No subscriptions found for monitor", "monitor_id", monitor.ID)
-		return nil
+	event := n.alertEvent(ctx, EventDown, monitor, alert)
+	policy, ok := n.policies[policyName]
+	if !ok {
+		return n.notify(ctx, event, n.checkChannels(monitor))
 	}
 
-	// Create notification payload
-	payload := NotificationPayload{
-		Title:     fmt.Sprintf("🔴 %s is Down", monitor.Name),
-		Body:      fmt.Sprintf("%s is currently unreachable. Reason: %s", monitor.Url, reason),
-		URL:       "/", // Could be a link to specific monitor page
-		MonitorID: monitor.ID,
+	// Channels are notified by the escalation steps, push subscribers right away
+	return errors.Join(n.notify(ctx, event, nil), n.escalate(ctx, alert, policy, monitor))
+}
+
+// SendMonitorUpNotification resolves the open alert and notifies subscribers when a monitor comes back up
+func (n *Notifier) SendMonitorUpNotification(ctx context.Context, monitor *db.Monitor) error {
+	if monitor == nil {
+		return nil
 	}
 
-	payloadBytes, err := json.Marshal(payload)
+	alert, err := n.conn.Q.ResolveAlert(ctx, monitor.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil // nobody was alerted
+	}
 	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		return fmt.Errorf("failed to resolve alert: %w", err)
 	}
 
-	// Send to all subscribers
-	for _, sub := range subscriptions {
-		if err := n.sendPushNotification(sub, payloadBytes); err != nil {
-			slog.Error("Failed to send push notification",
-				"monitor_id", monitor.ID,
-				"subscription_id", sub.ID,
-				"error", err,
-			)
-
-			if isSubscriptionError(err) {
-				if deleteErr := n.conn.Q.DeletePushSubscriptionByEndpoint(ctx, sub.Endpoint); deleteErr != nil {
-					slog.Error("Failed to delete invalid subscription", "error", deleteErr)
-				}
-			}
-		}
+	// Recoveries go to every channel that was told about the outage
+	channels := n.checkChannels(monitor)
+	if policy, ok := n.policies[alert.Policy]; ok {
+		channels = policy.channels(int(alert.Step))
 	}
+	return n.notify(ctx, n.alertEvent(ctx, EventUp, monitor, alert), channels)
+}
 
-	return nil
+// SendMonitorFlappingNotification notifies subscribers once when a monitor starts flapping
+func (n *Notifier) SendMonitorFlappingNotification(
+	ctx context.Context,
+	monitor *db.Monitor,
+	reason string,
+) error {
+	if monitor == nil {
+		return nil
+	}
+
+	event := n.monitorEvent(ctx, EventFlapping, monitor)
+	event.Reason = reason
+	return n.notify(ctx, event, n.checkChannels(monitor))
 }
 
-// SendMonitorUpNotification sends notifications when a monitor comes back up
-func (n *Notifier) SendMonitorUpNotification(ctx context.Context, monitor *db.Monitor) error {
+// SendMonitorStableNotification notifies subscribers when a monitor stopped flapping and is up
+func (n *Notifier) SendMonitorStableNotification(ctx context.Context, monitor *db.Monitor) error {
+	if monitor == nil {
+		return nil
+	}
+
+	// An outage from before the flapping is over now
+	_, err := n.conn.Q.GetOpenAlert(ctx, monitor.ID)
+	if err == nil {
+		return n.SendMonitorUpNotification(ctx, monitor)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get open alert: %w", err)
+	}
+
+	event := n.monitorEvent(ctx, EventStable, monitor)
+	return n.notify(ctx, event, n.checkChannels(monitor))
+}
+
+// SendMonitorDegradedNotification notifies subscribers when a monitor that is
+// up responds slower than its threshold
+func (n *Notifier) SendMonitorDegradedNotification(
+	ctx context.Context,
+	monitor *db.Monitor,
+	reason string,
+) error {
+	if monitor == nil {
+		return nil
+	}
+
+	event := n.monitorEvent(ctx, EventDegraded, monitor)
+	event.Reason = reason
+	return n.notify(ctx, event, n.checkChannels(monitor))
+}
+
+// SendMonitorNormalNotification notifies subscribers when a degraded monitor
+// responds in time again
+func (n *Notifier) SendMonitorNormalNotification(ctx context.Context, monitor *db.Monitor) error {
+	if monitor == nil {
+		return nil
+	}
+
+	event := n.monitorEvent(ctx, EventNormal, monitor)
+	return n.notify(ctx, event, n.checkChannels(monitor))
+}
+
+// SendBudgetBurnNotification alerts the channels of a monitor when its error
+// budget burns too fast. Subscribers aren't told, they get outage updates.
+func (n *Notifier) SendBudgetBurnNotification(
+	ctx context.Context,
+	monitor *db.Monitor,
+	reason string,
+) error {
+	if monitor == nil {
+		return nil
+	}
+
+	event := n.monitorEvent(ctx, EventBudgetBurn, monitor)
+	event.Reason = reason
+	return n.notifyChannels(ctx, event, n.channelsFor(monitor))
+}
+
+// SendBudgetRecoveredNotification tells the channels of a monitor when its
+// error budget stopped burning too fast
+func (n *Notifier) SendBudgetRecoveredNotification(ctx context.Context, monitor *db.Monitor) error {
 	if monitor == nil {
 		return nil
 	}
-	subscriptions, err := n.conn.Q.GetPushSubscriptionsByMonitor(ctx, monitor.ID)
+
+	event := n.monitorEvent(ctx, EventBudgetRecovered, monitor)
+	return n.notifyChannels(ctx, event, n.channelsFor(monitor))
+}
+
+// notify queues an event for the given channels and all push subscribers.
+// A recipient that can't be queued doesn't keep the others from being told.
+func (n *Notifier) notify(ctx context.Context, event *Event, channels []string) error {
+	errs := []error{
+		n.notifyChannels(ctx, event, channels),
+		n.notifyEmailSubscribers(ctx, event),
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.Join(append(errs, fmt.Errorf("failed to marshal event: %w", err))...)
+	}
+
+	// Get all subscriptions for this monitor, its group and all monitors
+	params := &db.GetPushSubscriptionsByMonitorParams{MonitorID: &event.Monitor.ID}
+	if event.Monitor.GroupName != "" {
+		params.GroupName = &event.Monitor.GroupName
+	}
+	subscriptions, err := n.conn.Q.GetPushSubscriptionsByMonitor(ctx, params)
 	if err != nil {
-		return fmt.Errorf("failed to get subscriptions: %w", err)
+		return errors.Join(append(errs, fmt.Errorf("failed to get subscriptions: %w", err))...)
 	}
 
 	if len(subscriptions) == 0 {
+		slog.Debug("No subscriptions found for monitor", "monitor_id", event.Monitor.ID)
+		return errors.Join(errs...)
+	}
+
+	// A browser may match more than one subscription, but gets a single message
+	seen := make(map[string]bool, len(subscriptions))
+	for _, sub := range subscriptions {
+		if !subscribedTo(sub, event.Monitor) {
+			continue
+		}
+		if seen[sub.Endpoint] {
+			continue
+		}
+		seen[sub.Endpoint] = true
+		errs = append(errs, n.enqueue(ctx, event, WebPush, sub.Endpoint, payload))
+	}
+
+	return errors.Join(errs...)
+}
+
+// subscribedTo reports whether a push subscription covers the monitor.
+// Private monitors only reach browsers subscribed to them directly.
+func subscribedTo(sub *db.PushSubscription, monitor *db.Monitor) bool {
+	if sub.MonitorID != nil {
+		return *sub.MonitorID == monitor.ID
+	}
+	if !monitor.Public || sub.GroupName == nil {
+		return false
+	}
+	return *sub.GroupName == "*" || *sub.GroupName == monitor.GroupName
+}
+
+// notifyChannels queues an event for the given channels only
+func (n *Notifier) notifyChannels(ctx context.Context, event *Event, channels []string) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	var errs []error
+	for _, channel := range channels {
+		errs = append(errs, n.enqueue(ctx, event, channel, "", payload))
+	}
+	return errors.Join(errs...)
+}
+
+// channelsFor returns the names of all channels routed to the monitor
+func (n *Notifier) channelsFor(monitor *db.Monitor) []string {
+	var channels []string
+	for _, r := range n.routes {
+		if r.matches(monitor) {
+			channels = append(channels, r.channel.Name())
+		}
+	}
+	return channels
+}
+
+// checkChannels returns the channels alerted about failed checks of the
+// monitor, none if it's alerted by error budget burn rates
+func (n *Notifier) checkChannels(monitor *db.Monitor) []string {
+	if n.budgetAlerts[monitor.Name] {
 		return nil
 	}
+	return n.channelsFor(monitor)
+}
+
+// monitorEvent returns an event of the monitor, along with its latest check
+func (n *Notifier) monitorEvent(ctx context.Context, eventType EventType, monitor *db.Monitor) *Event {
+	event := &Event{
+		Type:    eventType,
+		Monitor: monitor,
+		Time:    time.Now(),
+	}
+	check, err := n.conn.Q.GetLatestCheck(ctx, monitor.ID)
+	if err == nil {
+		event.Check = check
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		slog.Warn("Failed to get latest check", "monitor_id", monitor.ID, "error", err)
+	}
+	return event
+}
+
+func (n *Notifier) alertEvent(
+	ctx context.Context,
+	eventType EventType,
+	monitor *db.Monitor,
+	alert *db.Alert,
+) *Event {
+	event := n.monitorEvent(ctx, eventType, monitor)
+	event.Reason = alert.Reason
+	event.AlertID = alert.ID
+	if eventType == EventDown && n.baseURL != "" {
+		event.AckURL = fmt.Sprintf(
+			"%s/api/alerts/%d/ack?token=%s",
+			n.baseURL,
+			alert.ID,
+			n.alertToken(alert.ID),
+		)
+	}
+	return event
+}
 
+// Errors of push deliveries that the browser has to resolve by subscribing again
+var (
+	ErrNoSubscription    = errors.New("push subscription not found")
+	ErrStaleSubscription = errors.New("push subscription predates the VAPID key rotation")
+)
+
+// deliverPush sends a queued event to a single push subscription
+func (n *Notifier) deliverPush(ctx context.Context, notification *db.Notification) error {
+	var event Event
+	if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+		return fmt.Errorf("%w: invalid payload: %v", errPermanent, err)
+	}
+	return n.push(ctx, notification.Target, &event)
+}
+
+// push sends an event to the browser subscribed with endpoint
+func (n *Notifier) push(ctx context.Context, endpoint string, event *Event) error {
+	sub, err := n.conn.Q.GetPushSubscription(ctx, endpoint)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %w", errPermanent, ErrNoSubscription)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get subscription: %w", err)
+	}
+	if sub.Stale {
+		return fmt.Errorf("%w: %w", errPermanent, ErrStaleSubscription)
+	}
+
+	// Keys are loaded on every delivery, so rotations apply without a restart
+	keys, err := n.conn.Q.GetVAPIDKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get VAPID keys: %w", err)
+	}
+
+	// Create notification payload
+	event.messages = n.messages
 	payload := NotificationPayload{
-		Title:     fmt.Sprintf("✅ %s is Back Up", monitor.Name),
-		Body:      fmt.Sprintf("%s is now responding normally.", monitor.Url),
-		URL:       "/",
-		MonitorID: monitor.ID,
+		Title:     event.Title(),
+		Body:      event.Body(),
+		URL:       "/", // Could be a link to specific monitor page
+		MonitorID: event.Monitor.ID,
 	}
 
 	payloadBytes, err := json.Marshal(payload)
@@ -136,23 +482,22 @@ func (n *Notifier) SendMonitorUpNotification(ctx context.Context, monitor *db.Mo
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	for _, sub := range subscriptions {
-		if err := n.sendPushNotification(sub, payloadBytes); err != nil {
-			slog.Error("Failed to send recovery notification", "error", err)
-
-			if isSubscriptionError(err) {
-				if err := n.conn.Q.DeletePushSubscriptionByEndpoint(ctx, sub.Endpoint); err != nil {
-					slog.Error("Failed to delete invalid subscription", "error", err)
-				}
+	if err := n.sendPushNotification(ctx, sub, keys, payloadBytes); err != nil {
+		if isSubscriptionError(err) {
+			if deleteErr := n.conn.Q.DeletePushSubscriptionByEndpoint(ctx, sub.Endpoint); deleteErr != nil {
+				slog.Error("Failed to delete invalid subscription", "error", deleteErr)
 			}
+			return fmt.Errorf("%w: %v", errPermanent, err)
 		}
+		return err
 	}
-
 	return nil
 }
 
 func (n *Notifier) sendPushNotification(
+	ctx context.Context,
 	subscription *db.PushSubscription,
+	keys *db.VapidKey,
 	payload []byte,
 ) error {
 	// Create push subscription object
@@ -165,10 +510,10 @@ func (n *Notifier) sendPushNotification(
 	}
 
 	// Send the notification
-	resp, err := webpush.SendNotification(payload, sub, &webpush.Options{
-		Subscriber:      "mailto:beacon@mizuchi.dev", // Contact email for push notifications
-		VAPIDPublicKey:  n.vapidKeys.PublicKey,
-		VAPIDPrivateKey: n.vapidKeys.PrivateKey,
+	resp, err := webpush.SendNotificationWithContext(ctx, payload, sub, &webpush.Options{
+		Subscriber:      n.vapidSubject, // Contact for push services
+		VAPIDPublicKey:  keys.PublicKey,
+		VAPIDPrivateKey: keys.PrivateKey,
 		TTL:             30, // Time to live in seconds
 	})
 	if err != nil {
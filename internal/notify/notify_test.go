@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+func TestSubscribedTo(t *testing.T) {
+	id := func(v int64) *int64 { return &v }
+	group := func(v string) *string { return &v }
+	public := &db.Monitor{ID: 1, GroupName: "api", Public: true}
+	private := &db.Monitor{ID: 2, GroupName: "api"}
+
+	tests := []struct {
+		name    string
+		sub     db.PushSubscription
+		monitor *db.Monitor
+		want    bool
+	}{
+		{"monitor", db.PushSubscription{MonitorID: id(1)}, public, true},
+		{"other monitor", db.PushSubscription{MonitorID: id(3)}, public, false},
+		{"group", db.PushSubscription{GroupName: group("api")}, public, true},
+		{"other group", db.PushSubscription{GroupName: group("db")}, public, false},
+		{"all monitors", db.PushSubscription{GroupName: group("*")}, public, true},
+		{"private monitor", db.PushSubscription{MonitorID: id(2)}, private, true},
+		{"private by group", db.PushSubscription{GroupName: group("api")}, private, false},
+		{"private by all", db.PushSubscription{GroupName: group("*")}, private, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := subscribedTo(&tt.sub, tt.monitor); got != tt.want {
+				t.Errorf("subscribedTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,422 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/metrics"
+	"golang.org/x/time/rate"
+)
+
+// WebPush is the outbox channel name used for browser push subscriptions
+const WebPush = "webpush"
+
+const (
+	maxAttempts  = 8
+	baseBackoff  = 10 * time.Second
+	maxBackoff   = time.Hour
+	pollInterval = 5 * time.Second
+	batchSize    = 50
+
+	defaultChannelRate = 20 // messages per minute
+	channelBurst       = 3
+	webPushRate        = rate.Limit(10) // messages per second
+	webPushBurst       = 20
+)
+
+// errPermanent marks delivery errors that retrying won't fix
+var errPermanent = errors.New("permanent failure")
+
+// worker delivers the queued notifications of a single channel, so a slow or
+// failing channel never holds up the others.
+type worker struct {
+	deliver  func(ctx context.Context, notification *db.Notification) error
+	limiter  *rate.Limiter
+	wake     chan struct{}
+	schedule schedule
+
+	// digest delivers several notifications as one message, if set
+	digest func(ctx context.Context, notifications []*db.Notification) error
+
+	// targets limits the messages per recipient, if set
+	targets     map[string]*rate.Limiter
+	targetLimit rate.Limit
+}
+
+func newWorker(
+	deliver func(context.Context, *db.Notification) error,
+	limit rate.Limit,
+	burst int,
+) *worker {
+	return &worker{
+		deliver: deliver,
+		limiter: rate.NewLimiter(limit, burst),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// limitTargets caps the messages a single recipient gets per hour
+func (w *worker) limitTargets(perHour int) {
+	w.targets = make(map[string]*rate.Limiter)
+	w.targetLimit = rate.Every(time.Hour / time.Duration(perHour))
+}
+
+// targetDelay returns how long a message to target has to wait for its rate limit
+func (w *worker) targetDelay(target string) time.Duration {
+	if w.targets == nil || target == "" {
+		return 0
+	}
+	limiter, ok := w.targets[target]
+	if !ok {
+		limiter = rate.NewLimiter(w.targetLimit, channelBurst)
+		w.targets[target] = limiter
+	}
+	r := limiter.Reserve()
+	delay := r.Delay()
+	if delay > 0 {
+		r.Cancel()
+	}
+	return delay
+}
+
+// pruneTargets forgets recipients whose rate limit has fully recovered, such
+// as removed subscribers. A new limiter behaves the same for them.
+func (w *worker) pruneTargets() {
+	for target, limiter := range w.targets {
+		if limiter.Tokens() >= channelBurst {
+			delete(w.targets, target)
+		}
+	}
+}
+
+// send delivers an event to the channel, or to a single recipient if target is set
+func send(ctx context.Context, channel Channel, event *Event, target string) error {
+	if target == "" {
+		return channel.Send(ctx, event)
+	}
+	recipient, ok := channel.(recipientChannel)
+	if !ok {
+		return fmt.Errorf("%w: channel %q has no recipients", errPermanent, channel.Name())
+	}
+	return recipient.SendTo(ctx, event, target)
+}
+
+// channelDelivery delivers queued events to a channel. Recoveries are
+// attached to the chat message of their down alert, kept with its
+// notification so restarts and retries don't lose it.
+func (n *Notifier) channelDelivery(
+	channel Channel,
+	messages *Messages,
+) func(context.Context, *db.Notification) error {
+	return func(ctx context.Context, notification *db.Notification) error {
+		var event Event
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			return fmt.Errorf("%w: invalid payload: %v", errPermanent, err)
+		}
+		event.messages = messages
+
+		if event.Type == EventUp && notification.MonitorID != nil {
+			thread, err := n.conn.Q.GetNotificationThread(ctx, &db.GetNotificationThreadParams{
+				Channel:   notification.Channel,
+				MonitorID: notification.MonitorID,
+				ID:        notification.ID,
+			})
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("failed to get thread: %w", err)
+			}
+			event.thread = thread
+		}
+
+		if err := send(ctx, channel, &event, notification.Target); err != nil {
+			return err
+		}
+
+		if event.Type == EventDown && event.thread != "" {
+			if err := n.conn.Q.SetNotificationThread(ctx, &db.SetNotificationThreadParams{
+				Thread: event.thread,
+				ID:     notification.ID,
+			}); err != nil {
+				slog.Error("Failed to store thread", "id", notification.ID, "error", err)
+			}
+		}
+		return nil
+	}
+}
+
+func channelDigest(
+	channel Channel,
+	messages *Messages,
+	location *time.Location,
+) func(context.Context, []*db.Notification) error {
+	return func(ctx context.Context, notifications []*db.Notification) error {
+		digest := &Event{
+			Type:     EventDigest,
+			Time:     time.Now().In(location),
+			Events:   make([]*Event, 0, len(notifications)),
+			messages: messages,
+		}
+		for _, notification := range notifications {
+			var event Event
+			if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+				return fmt.Errorf("%w: invalid payload: %v", errPermanent, err)
+			}
+			event.Time = event.Time.In(location)
+			event.messages = messages
+			digest.Events = append(digest.Events, &event)
+			if digest.UnsubscribeURL == "" {
+				digest.UnsubscribeURL = event.UnsubscribeURL
+			}
+		}
+		// Batches share a single target, see drain
+		return send(ctx, channel, digest, notifications[0].Target)
+	}
+}
+
+// Start launches the background delivery and escalation workers
+func (n *Notifier) Start(ctx context.Context) {
+	n.failRemovedChannels(ctx)
+	for name, w := range n.workers {
+		go n.run(ctx, name, w)
+	}
+	go n.escalationJob(ctx)
+}
+
+// failRemovedChannels gives up on notifications queued for channels that were
+// removed from the config, which no worker would ever deliver
+func (n *Notifier) failRemovedChannels(ctx context.Context) {
+	channels, err := n.conn.Q.GetPendingChannels(ctx)
+	if err != nil {
+		slog.Error("Failed to get queued channels", "error", err)
+		return
+	}
+
+	msg := "channel was removed from the config"
+	for _, channel := range channels {
+		if _, ok := n.workers[channel]; ok {
+			continue
+		}
+		slog.Warn("Giving up on notifications of removed channel", "channel", channel)
+		if err := n.conn.Q.FailChannelNotifications(ctx, &db.FailChannelNotificationsParams{
+			LastError: &msg,
+			Channel:   channel,
+		}); err != nil {
+			slog.Error("Failed to mark notifications as failed", "channel", channel, "error", err)
+		}
+	}
+}
+
+func (n *Notifier) enqueue(
+	ctx context.Context,
+	event *Event,
+	channel, target string,
+	payload []byte,
+) error {
+	sendAt := time.Now()
+	w, ok := n.workers[channel]
+	if ok {
+		sendAt = w.schedule.sendAt(event.Type, sendAt)
+	}
+
+	var monitorID *int64
+	if event.Monitor != nil {
+		monitorID = &event.Monitor.ID
+	}
+	if err := n.conn.Q.CreateNotification(ctx, &db.CreateNotificationParams{
+		MonitorID: monitorID,
+		Channel:   channel,
+		Target:    target,
+		EventType: string(event.Type),
+		Payload:   string(payload),
+		SendAt:    sendAt.Unix(),
+	}); err != nil {
+		return fmt.Errorf("failed to queue notification: %w", err)
+	}
+
+	if ok {
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func (n *Notifier) run(ctx context.Context, channel string, w *worker) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		n.drain(ctx, channel, w)
+		w.pruneTargets()
+
+		select {
+		case <-ticker.C:
+		case <-w.wake:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// drain delivers all notifications of a channel that are currently due
+func (n *Notifier) drain(ctx context.Context, channel string, w *worker) {
+	for {
+		due, err := n.conn.Q.GetDueNotifications(ctx, &db.GetDueNotificationsParams{
+			Channel: channel,
+			Limit:   batchSize,
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.Error("Failed to load queued notifications", "channel", channel, "error", err)
+			}
+			return
+		}
+
+		if w.digest != nil && len(due) > 1 {
+			for _, batch := range byTarget(due) {
+				if n.postpone(ctx, w, batch) {
+					continue
+				}
+				if err := w.limiter.Wait(ctx); err != nil {
+					return
+				}
+				if len(batch) == 1 {
+					n.attempt(ctx, batch, func() error { return w.deliver(ctx, batch[0]) })
+				} else {
+					n.attempt(ctx, batch, func() error { return w.digest(ctx, batch) })
+				}
+			}
+		} else {
+			for _, notification := range due {
+				batch := []*db.Notification{notification}
+				if n.postpone(ctx, w, batch) {
+					continue
+				}
+				if err := w.limiter.Wait(ctx); err != nil {
+					return
+				}
+				n.attempt(ctx, batch, func() error {
+					return w.deliver(ctx, notification)
+				})
+			}
+		}
+
+		if len(due) < batchSize {
+			return
+		}
+	}
+}
+
+// byTarget splits notifications into batches per recipient, keeping their order
+func byTarget(notifications []*db.Notification) [][]*db.Notification {
+	var batches [][]*db.Notification
+	index := make(map[string]int)
+	for _, notification := range notifications {
+		i, ok := index[notification.Target]
+		if !ok {
+			i = len(batches)
+			index[notification.Target] = i
+			batches = append(batches, nil)
+		}
+		batches[i] = append(batches[i], notification)
+	}
+	return batches
+}
+
+// postpone delays a batch whose recipient exceeded its rate limit, without
+// counting it as a failed attempt
+func (n *Notifier) postpone(ctx context.Context, w *worker, batch []*db.Notification) bool {
+	delay := w.targetDelay(batch[0].Target)
+	if delay <= 0 {
+		return false
+	}
+
+	seconds := strconv.Itoa(int(delay.Seconds()) + 1)
+	for _, notification := range batch {
+		if err := n.conn.Q.PostponeNotification(ctx, &db.PostponeNotificationParams{
+			Delay: &seconds,
+			ID:    notification.ID,
+		}); err != nil {
+			slog.Error("Failed to postpone notification", "id", notification.ID, "error", err)
+		}
+	}
+	return true
+}
+
+// attempt delivers a batch of notifications and records the outcome for each of them
+func (n *Notifier) attempt(
+	ctx context.Context,
+	notifications []*db.Notification,
+	deliver func() error,
+) {
+	err := deliver()
+	for _, notification := range notifications {
+		n.record(ctx, notification, err)
+	}
+}
+
+func (n *Notifier) record(ctx context.Context, notification *db.Notification, err error) {
+	if err == nil {
+		metrics.ObserveDelivery(notification.Channel, "sent")
+		if err := n.conn.Q.MarkNotificationSent(ctx, notification.ID); err != nil {
+			slog.Error("Failed to mark notification as sent", "id", notification.ID, "error", err)
+		}
+		return
+	}
+
+	msg := err.Error()
+	var monitorID any // incident updates have no monitor
+	if notification.MonitorID != nil {
+		monitorID = *notification.MonitorID
+	}
+	if errors.Is(err, errPermanent) || notification.Attempts+1 >= maxAttempts {
+		metrics.ObserveDelivery(notification.Channel, "failed")
+		slog.Error("Failed to deliver notification, giving up",
+			"id", notification.ID,
+			"channel", notification.Channel,
+			"monitor_id", monitorID,
+			"attempts", notification.Attempts+1,
+			"error", err,
+		)
+		if err := n.conn.Q.MarkNotificationFailed(ctx, &db.MarkNotificationFailedParams{
+			LastError: &msg,
+			ID:        notification.ID,
+		}); err != nil {
+			slog.Error("Failed to mark notification as failed", "id", notification.ID, "error", err)
+		}
+		return
+	}
+
+	metrics.ObserveDelivery(notification.Channel, "retried")
+	delay := backoff(notification.Attempts)
+	slog.Warn("Failed to deliver notification, retrying",
+		"id", notification.ID,
+		"channel", notification.Channel,
+		"monitor_id", monitorID,
+		"retry_in", delay,
+		"error", err,
+	)
+	delayStr := strconv.Itoa(int(delay.Seconds()))
+	if err := n.conn.Q.MarkNotificationRetry(ctx, &db.MarkNotificationRetryParams{
+		LastError: &msg,
+		Delay:     &delayStr,
+		ID:        notification.ID,
+	}); err != nil {
+		slog.Error("Failed to reschedule notification", "id", notification.ID, "error", err)
+	}
+}
+
+// backoff returns the exponential delay before the next delivery attempt
+func backoff(attempts int64) time.Duration {
+	if attempts >= 16 {
+		return maxBackoff
+	}
+	return min(baseBackoff<<attempts, maxBackoff)
+}
@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// quietHours is a daily time range, which may span midnight, in minutes since midnight
+type quietHours struct {
+	start, end int
+}
+
+// parseQuietHours parses ranges like "22:00-07:00"
+func parseQuietHours(s string) (quietHours, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return quietHours{}, fmt.Errorf("invalid quiet hours %q: expected HH:MM-HH:MM", s)
+	}
+
+	var q quietHours
+	for i, part := range []string{from, to} {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return quietHours{}, fmt.Errorf("invalid quiet hours %q: expected HH:MM-HH:MM", s)
+		}
+		minutes := t.Hour()*60 + t.Minute()
+		if i == 0 {
+			q.start = minutes
+		} else {
+			q.end = minutes
+		}
+	}
+	if q.start == q.end {
+		return quietHours{}, fmt.Errorf("invalid quiet hours %q: start equals end", s)
+	}
+	return q, nil
+}
+
+// until returns the end of the quiet hours if t falls into them
+func (q quietHours) until(t time.Time) (time.Time, bool) {
+	minutes := t.Hour()*60 + t.Minute()
+	quiet := minutes >= q.start && minutes < q.end
+	if q.start > q.end {
+		quiet = minutes >= q.start || minutes < q.end
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	end := time.Date(t.Year(), t.Month(), t.Day(), q.end/60, q.end%60, 0, 0, t.Location())
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end, true
+}
+
+// schedule delays the delivery of a channel's notifications
+type schedule struct {
+	digest     time.Duration
+	quietHours []quietHours
+	location   *time.Location
+}
+
+// sendAt returns when an event queued at now may be delivered. Subscription
+// confirmations are sent right away and down alerts ignore quiet hours. In digest mode, events are held until the end of the
+// current interval, so they can be sent as a single message. Intervals are
+// aligned to the local clock, e.g. a daily digest goes out at midnight.
+func (s *schedule) sendAt(eventType EventType, now time.Time) time.Time {
+	if eventType == EventConfirm {
+		return now
+	}
+
+	at := now
+	if eventType != EventDown {
+		for _, q := range s.quietHours {
+			if end, ok := q.until(now.In(s.location)); ok && end.After(at) {
+				at = end
+			}
+		}
+	}
+	if s.digest > 0 {
+		_, offset := at.In(s.location).Zone()
+		local := time.Duration(offset) * time.Second
+		at = at.Add(local).Truncate(s.digest).Add(s.digest - local)
+	}
+	return at
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSendAt(t *testing.T) {
+	vienna, err := time.LoadLocation("Europe/Vienna")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+	night, _ := parseQuietHours("22:00-07:00")
+
+	// Times in Vienna, UTC+1 in February
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 2, day, hour, minute, 0, 0, vienna)
+	}
+
+	tests := []struct {
+		name     string
+		schedule schedule
+		event    EventType
+		now      time.Time
+		want     time.Time
+	}{
+		{"immediate", schedule{location: vienna}, EventUp, at(1, 12, 34), at(1, 12, 34)},
+		{"digest", schedule{digest: 30 * time.Minute, location: vienna}, EventUp, at(1, 12, 34), at(1, 13, 0)},
+		{"digest on boundary", schedule{digest: 30 * time.Minute, location: vienna}, EventUp, at(1, 13, 0), at(1, 13, 30)},
+		{"daily digest at local midnight", schedule{digest: 24 * time.Hour, location: vienna}, EventUp, at(1, 0, 30), at(2, 0, 0)},
+		{"6h digest in local time", schedule{digest: 6 * time.Hour, location: vienna}, EventUp, at(1, 5, 0), at(1, 6, 0)},
+		{"quiet hours", schedule{quietHours: []quietHours{night}, location: vienna}, EventUp, at(1, 23, 0), at(2, 7, 0)},
+		{"quiet hours after midnight", schedule{quietHours: []quietHours{night}, location: vienna}, EventDegraded, at(2, 3, 0), at(2, 7, 0)},
+		{"outside quiet hours", schedule{quietHours: []quietHours{night}, location: vienna}, EventUp, at(1, 12, 0), at(1, 12, 0)},
+		{"down ignores quiet hours", schedule{quietHours: []quietHours{night}, location: vienna}, EventDown, at(1, 23, 0), at(1, 23, 0)},
+		{"confirmation ignores digest", schedule{digest: time.Hour, quietHours: []quietHours{night}, location: vienna}, EventConfirm, at(1, 23, 0), at(1, 23, 0)},
+		{"digest after quiet hours", schedule{digest: time.Hour, quietHours: []quietHours{night}, location: vienna}, EventUp, at(1, 23, 0), at(2, 8, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.sendAt(tt.event, tt.now.UTC()); !got.Equal(tt.want) {
+				t.Errorf("sendAt() = %v, want %v", got.In(vienna), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQuietHours(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    quietHours
+		wantErr bool
+	}{
+		{"22:00-07:00", quietHours{22 * 60, 7 * 60}, false},
+		{"12:30 - 13:15", quietHours{12*60 + 30, 13*60 + 15}, false},
+		{"22:00", quietHours{}, true},
+		{"22:00-25:00", quietHours{}, true},
+		{"08:00-08:00", quietHours{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseQuietHours(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseQuietHours() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseQuietHours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
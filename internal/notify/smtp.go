@@ -0,0 +1,192 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const smtpTimeout = 30 * time.Second
+
+// SMTP sends events as plain text emails
+type SMTP struct {
+	name     string
+	host     string
+	port     int
+	username string
+	password string
+	from     *mail.Address
+	to       []string
+}
+
+func newSMTP(name, host string, port int, username, password string, from *mail.Address, to []string) *SMTP {
+	if port == 0 {
+		port = 587
+	}
+	return &SMTP{
+		name:     name,
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+		to:       to,
+	}
+}
+
+func (m *SMTP) Name() string {
+	return m.name
+}
+
+// Send mails the event to the configured recipients
+func (m *SMTP) Send(ctx context.Context, event *Event) error {
+	return m.send(ctx, m.to, event)
+}
+
+// SendTo mails the event to a single subscriber
+func (m *SMTP) SendTo(ctx context.Context, event *Event, to string) error {
+	return m.send(ctx, []string{to}, event)
+}
+
+func (m *SMTP) send(ctx context.Context, to []string, event *Event) error {
+	if len(to) == 0 {
+		return nil
+	}
+
+	msg, err := m.message(to, event)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(smtpTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	addr := net.JoinHostPort(m.host, strconv.Itoa(m.port))
+	dialer := &net.Dialer{Deadline: deadline}
+	var conn net.Conn
+	if m.port == 465 {
+		// Implicit TLS
+		conn, err = (&tls.Dialer{
+			NetDialer: dialer,
+			Config:    &tls.Config{ServerName: m.host, MinVersion: tls.VersionTLS12},
+		}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("smtp: %w", err)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if err := m.deliver(c, to, msg); err != nil {
+		return fmt.Errorf("smtp: %w", smtpError(err))
+	}
+	return c.Quit()
+}
+
+func (m *SMTP) deliver(c *smtp.Client, to []string, msg []byte) error {
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if m.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return err
+		}
+	}
+
+	// The envelope takes the bare address, the header the display name too
+	if err := c.Mail(m.from.Address); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// message renders the event as a MIME message
+func (m *SMTP) message(to []string, event *Event) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	domain := m.host
+	if _, d, ok := strings.Cut(m.from.Address, "@"); ok {
+		domain = d
+	}
+
+	var b bytes.Buffer
+	header := func(key, value string) {
+		b.WriteString(key + ": " + value + "\r\n")
+	}
+	header("From", m.from.String())
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", event.Title()))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", "<"+hex.EncodeToString(id)+"@"+domain+">")
+	if event.UnsubscribeURL != "" {
+		// One-click unsubscribe (RFC 8058)
+		header("List-Unsubscribe", "<"+event.UnsubscribeURL+">")
+		header("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	b.WriteString("\r\n")
+
+	body := event.Body()
+	if event.UnsubscribeURL != "" {
+		body += "\n\n--\n" + event.msgs().Unsubscribe() + ": " + event.UnsubscribeURL
+	}
+
+	w := quotedprintable.NewWriter(&b)
+	if _, err := w.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// smtpError marks rejections by the server as permanent
+func smtpError(err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+		return fmt.Errorf("%w: %v", errPermanent, err)
+	}
+	return err
+}
@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"errors"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+// fakeSMTP is a minimal SMTP server on one end of a pipe, recording the
+// envelope and the message it receives
+type fakeSMTP struct {
+	mailFrom string
+	rcptTo   []string
+	data     string
+
+	rejectMail bool // answers MAIL with 550
+}
+
+func (f *fakeSMTP) serve(t *testing.T, conn net.Conn) {
+	t.Helper()
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	reply := func(line string) {
+		if err := tp.PrintfLine("%s", line); err != nil {
+			t.Errorf("fake smtp: %v", err)
+		}
+	}
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			reply("250 fake")
+		case "MAIL":
+			if f.rejectMail {
+				reply("550 5.1.7 invalid sender")
+				continue
+			}
+			f.mailFrom = arg
+			reply("250 OK")
+		case "RCPT":
+			f.rcptTo = append(f.rcptTo, arg)
+			reply("250 OK")
+		case "DATA":
+			reply("354 go ahead")
+			lines, err := tp.ReadDotLines()
+			if err != nil {
+				t.Errorf("fake smtp: %v", err)
+				return
+			}
+			f.data = strings.Join(lines, "\r\n")
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 unknown command")
+		}
+	}
+}
+
+// deliverTo runs the SMTP conversation of m against the fake server
+func deliverTo(t *testing.T, f *fakeSMTP, m *SMTP, to []string, msg []byte) error {
+	t.Helper()
+	client, server := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.serve(t, server)
+	}()
+	_ = client.SetDeadline(time.Now().Add(5 * time.Second))
+
+	c, err := smtp.NewClient(client, m.host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = smtpError(m.deliver(c, to, msg))
+	if err == nil {
+		err = c.Quit()
+	} else {
+		_ = c.Close()
+	}
+	<-done
+	return err
+}
+
+func TestSMTPDeliver(t *testing.T) {
+	from, err := mail.ParseAddress("Beacon Ünterwegs <beacon@example.com>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newSMTP("mail", "smtp.example.com", 0, "", "", from, []string{"ops@example.com"})
+	event := &Event{
+		Type:           EventDown,
+		Monitor:        &db.Monitor{ID: 1, Name: "API", Url: "https://api.example.com"},
+		Reason:         "unexpected status code 503",
+		Time:           time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC),
+		UnsubscribeURL: "https://status.example.com/unsubscribe?token=abc",
+	}
+	msg, err := m.message(m.to, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fakeSMTP{}
+	if err := deliverTo(t, f, m, m.to, msg); err != nil {
+		t.Fatalf("deliver() error = %v", err)
+	}
+	if f.mailFrom != "FROM:<beacon@example.com>" {
+		t.Errorf("MAIL %s, want FROM:<beacon@example.com>", f.mailFrom)
+	}
+	if len(f.rcptTo) != 1 || f.rcptTo[0] != "TO:<ops@example.com>" {
+		t.Errorf("RCPT %v, want TO:<ops@example.com>", f.rcptTo)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(f.data + "\r\n"))
+	if err != nil {
+		t.Fatalf("invalid message: %v", err)
+	}
+	header, err := parsed.Header.AddressList("From")
+	if err != nil || len(header) != 1 || *header[0] != *from {
+		t.Errorf("From: %q, want %q", parsed.Header.Get("From"), from.String())
+	}
+	if got := parsed.Header.Get("Message-ID"); !strings.HasSuffix(got, "@example.com>") {
+		t.Errorf("Message-ID: %q, want the domain of the sender", got)
+	}
+	if got := parsed.Header.Get("List-Unsubscribe"); got != "<"+event.UnsubscribeURL+">" {
+		t.Errorf("List-Unsubscribe: %q", got)
+	}
+}
+
+func TestSMTPDeliverRejected(t *testing.T) {
+	from := &mail.Address{Address: "beacon@example.com"}
+	m := newSMTP("mail", "smtp.example.com", 0, "", "", from, []string{"ops@example.com"})
+
+	err := deliverTo(t, &fakeSMTP{rejectMail: true}, m, m.to, []byte("Subject: test\r\n\r\ntest\r\n"))
+	if !errors.Is(err, errPermanent) {
+		t.Errorf("deliver() error = %v, want a permanent error", err)
+	}
+}
+
+func TestChannelConfigFrom(t *testing.T) {
+	tests := []struct {
+		from    string
+		wantErr bool
+	}{
+		{"beacon@example.com", false},
+		{"Beacon <beacon@example.com>", false},
+		{`"Beacon, Ops" <beacon@example.com>`, false},
+		{"Beacon", true},
+		{"Beacon <beacon@example.com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from, func(t *testing.T) {
+			cfg := ChannelConfig{Name: "mail", Type: "smtp", Host: "smtp.example.com", From: tt.from, To: []string{"ops@example.com"}}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const telegramAPI = "https://api.telegram.org"
+
+// Telegram posts events to a chat through the Telegram Bot API
+type Telegram struct {
+	name    string
+	baseURL string
+	chatID  string
+	edit    bool
+}
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+	Result      struct {
+		MessageID int64 `json:"message_id"`
+	} `json:"result"`
+}
+
+func newTelegram(name, token, chatID string, edit bool) *Telegram {
+	return &Telegram{
+		name:    name,
+		baseURL: telegramAPI + "/bot" + token,
+		chatID:  chatID,
+		edit:    edit,
+	}
+}
+
+func (t *Telegram) Name() string {
+	return t.name
+}
+
+func (t *Telegram) Send(ctx context.Context, event *Event) error {
+	body := map[string]any{
+		"chat_id":    t.chatID,
+		"text":       "*" + escapeMarkdownV2(event.Title()) + "*\n" + escapeMarkdownV2(event.Body()),
+		"parse_mode": "MarkdownV2",
+		// Previews would fetch (and thereby confirm) acknowledgement links
+		"link_preview_options": map[string]any{"is_disabled": true},
+	}
+
+	method := "sendMessage"
+	if event.Type == EventUp && event.thread != "" {
+		messageID, err := strconv.ParseInt(event.thread, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: invalid message ID %q", errPermanent, event.thread)
+		}
+		if t.edit {
+			method = "editMessageText"
+			body["message_id"] = messageID
+		} else {
+			body["reply_parameters"] = map[string]any{
+				"message_id":                  messageID,
+				"allow_sending_without_reply": true,
+			}
+		}
+	}
+
+	var resp telegramResponse
+	if err := doJSON(ctx, http.MethodPost, t.baseURL+"/"+method, nil, body, &resp); err != nil {
+		return fmt.Errorf("telegram %s: %w", method, err)
+	}
+	if !resp.OK {
+		return fmt.Errorf("telegram %s: %s", method, resp.Description)
+	}
+
+	if event.Type == EventDown {
+		event.thread = strconv.FormatInt(resp.Result.MessageID, 10)
+	}
+	return nil
+}
+
+// escapeMarkdownV2 escapes all characters reserved by Telegram's MarkdownV2
+func escapeMarkdownV2(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune("_*[]()~`>#+-=|{}.!\\", r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,163 @@
+package notify
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// DefaultLanguage is used for notifications if no language is configured
+const DefaultLanguage = "en"
+
+// TemplateConfig overrides the title and body of an event type. Both are Go
+// text/template strings executed with the event, e.g. "{{.Monitor.Name}}".
+type TemplateConfig struct {
+	Title string `yaml:"title,omitempty"`
+	Body  string `yaml:"body,omitempty"`
+}
+
+// TemplateEvents lists the event types that can be templated
+var TemplateEvents = []EventType{
+	EventDown,
+	EventUp,
+	EventFlapping,
+	EventStable,
+	EventDegraded,
+	EventNormal,
+	EventCertExpiring,
+	EventBudgetBurn,
+	EventBudgetRecovered,
+	EventDigest,
+	EventIncident,
+	EventConfirm,
+	EventTest,
+}
+
+// Messages renders events into the title and body of a notification
+type Messages struct {
+	translation *translation
+	title       map[EventType]*template.Template
+	body        map[EventType]*template.Template
+
+	// built-in templates of the language, if templates were overridden
+	fallback *Messages
+}
+
+// NewMessages returns the built-in messages of a language with the given
+// templates replacing them
+func NewMessages(language string, overrides map[EventType]TemplateConfig) (*Messages, error) {
+	t, ok := translations[language]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language '%s': must be one of %v", language, ValidLanguages)
+	}
+
+	builtin, err := parseMessages(t, t.templates)
+	if err != nil {
+		return nil, err
+	}
+	if len(overrides) == 0 {
+		return builtin, nil
+	}
+
+	templates := make(map[EventType]TemplateConfig, len(t.templates))
+	for eventType, tmpl := range t.templates {
+		if override, ok := overrides[eventType]; ok {
+			if override.Title != "" {
+				tmpl.Title = override.Title
+			}
+			if override.Body != "" {
+				tmpl.Body = override.Body
+			}
+		}
+		templates[eventType] = tmpl
+	}
+	for eventType := range overrides {
+		if !slices.Contains(TemplateEvents, eventType) {
+			return nil, fmt.Errorf("invalid template '%s': must be one of %v", eventType, TemplateEvents)
+		}
+	}
+
+	m, err := parseMessages(t, templates)
+	if err != nil {
+		return nil, err
+	}
+	m.fallback = builtin
+	return m, nil
+}
+
+func parseMessages(t *translation, templates map[EventType]TemplateConfig) (*Messages, error) {
+	m := &Messages{
+		translation: t,
+		title:       make(map[EventType]*template.Template, len(templates)),
+		body:        make(map[EventType]*template.Template, len(templates)),
+	}
+	funcs := template.FuncMap{
+		"title":      m.Title,
+		"status":     t.status,
+		"join":       strings.Join,
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"capitalize": capitalize,
+	}
+
+	for eventType, tmpl := range templates {
+		var err error
+		name := string(eventType)
+		if m.title[eventType], err = template.New(name).Funcs(funcs).Parse(tmpl.Title); err != nil {
+			return nil, fmt.Errorf("template %s: invalid title: %w", name, err)
+		}
+		if m.body[eventType], err = template.New(name).Funcs(funcs).Parse(tmpl.Body); err != nil {
+			return nil, fmt.Errorf("template %s: invalid body: %w", name, err)
+		}
+	}
+	return m, nil
+}
+
+// Title renders the title of an event
+func (m *Messages) Title(e *Event) string {
+	return m.render(e, m.title, func(f *Messages) string { return f.Title(e) })
+}
+
+// Body renders the body of an event
+func (m *Messages) Body(e *Event) string {
+	return m.render(e, m.body, func(f *Messages) string { return f.Body(e) })
+}
+
+// render executes the template of the event type. Custom templates that fail,
+// e.g. by accessing a field the event doesn't have, fall back to the built-in
+// ones, so the notification still goes out.
+func (m *Messages) render(
+	e *Event,
+	templates map[EventType]*template.Template,
+	fallback func(*Messages) string,
+) string {
+	tmpl, ok := templates[e.Type]
+	if !ok {
+		return string(e.Type)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, e); err != nil {
+		if m.fallback != nil {
+			slog.Warn("Failed to render notification template", "event", e.Type, "error", err)
+			return fallback(m.fallback)
+		}
+		slog.Error("Failed to render notification", "event", e.Type, "error", err)
+		return string(e.Type)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Unsubscribe returns the label of unsubscribe links
+func (m *Messages) Unsubscribe() string {
+	return m.translation.unsubscribe
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
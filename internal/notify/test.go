@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+func testEvent(monitor *db.Monitor) *Event {
+	return &Event{
+		Type:    EventTest,
+		Monitor: monitor,
+		Reason:  "test notification",
+		Time:    time.Now(),
+	}
+}
+
+// ErrPushRateLimited is returned when a test push would exceed the rate
+// limit of push deliveries
+var ErrPushRateLimited = errors.New("push rate limit exceeded")
+
+// SendTestPush sends a test event of the monitor to a browser subscribed to
+// it. Test pushes share the rate limit of queued ones.
+func (n *Notifier) SendTestPush(ctx context.Context, monitor *db.Monitor, endpoint string) error {
+	subs, err := n.conn.Q.GetPushSubscriptionsByEndpoint(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+	if !slices.ContainsFunc(subs, func(sub *db.PushSubscription) bool {
+		return subscribedTo(sub, monitor)
+	}) {
+		return ErrNoSubscription
+	}
+
+	if w, ok := n.workers[WebPush]; ok && !w.limiter.Allow() {
+		return ErrPushRateLimited
+	}
+	return n.push(ctx, endpoint, testEvent(monitor))
+}
+
+// SendTestNotification sends a test event of the monitor through a channel,
+// or to a single recipient of it if to is set, and returns the delivery result
+func SendTestNotification(
+	ctx context.Context,
+	cfg ChannelConfig,
+	language string,
+	monitor *db.Monitor,
+	to string,
+) error {
+	channel, err := newChannel(cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Language != "" {
+		language = cfg.Language
+	}
+	messages, err := NewMessages(language, cfg.Templates)
+	if err != nil {
+		return fmt.Errorf("channel %q: %w", cfg.Name, err)
+	}
+
+	event := testEvent(monitor)
+	event.messages = messages
+	if to == "" && cfg.Type == "smtp" && len(cfg.To) == 0 {
+		return fmt.Errorf("channel %q only delivers to subscribers, pass a recipient", cfg.Name)
+	}
+	return send(ctx, channel, event, to)
+}
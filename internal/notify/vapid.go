@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/SherClockHolmes/webpush-go"
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+// DefaultVAPIDSubject is the contact sent to push services if none is configured
+const DefaultVAPIDSubject = "mailto:beacon@mizuchi.dev"
+
+// VAPIDOptions configures how push notifications identify themselves
+type VAPIDOptions struct {
+	Subject    string // mailto: or https: contact for push services
+	PublicKey  string // imported keys, generated and stored in the database if empty
+	PrivateKey string
+}
+
+// Validate checks the subject and imported keys
+func (o *VAPIDOptions) Validate() error {
+	if o.Subject != "" &&
+		!strings.HasPrefix(o.Subject, "mailto:") &&
+		!strings.HasPrefix(o.Subject, "https://") {
+		return fmt.Errorf("invalid VAPID subject %q: must be a mailto: or https:// URL", o.Subject)
+	}
+	if (o.PublicKey == "") != (o.PrivateKey == "") {
+		return fmt.Errorf("both VAPID public and private key are required")
+	}
+	if o.PublicKey == "" {
+		return nil
+	}
+	if key, err := base64.RawURLEncoding.DecodeString(o.PublicKey); err != nil || len(key) != 65 {
+		return fmt.Errorf("invalid VAPID public key: expected 65 bytes, base64url encoded")
+	}
+	if key, err := base64.RawURLEncoding.DecodeString(o.PrivateKey); err != nil || len(key) != 32 {
+		return fmt.Errorf("invalid VAPID private key: expected 32 bytes, base64url encoded")
+	}
+	return nil
+}
+
+// setupVAPIDKeys stores imported keys, or generates keys on first start
+func setupVAPIDKeys(ctx context.Context, conn *db.Connection, opts VAPIDOptions) error {
+	keys, err := conn.Q.GetVAPIDKeys(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get VAPID keys: %w", err)
+	}
+
+	if opts.PublicKey == "" {
+		if err == nil {
+			return nil
+		}
+		privateKey, publicKey, err := webpush.GenerateVAPIDKeys()
+		if err != nil {
+			return fmt.Errorf("failed to generate VAPID keys: %w", err)
+		}
+		return conn.Q.CreateVAPIDKeys(ctx, &db.CreateVAPIDKeysParams{
+			PublicKey:  publicKey,
+			PrivateKey: privateKey,
+		})
+	}
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return conn.Q.CreateVAPIDKeys(ctx, &db.CreateVAPIDKeysParams{
+			PublicKey:  opts.PublicKey,
+			PrivateKey: opts.PrivateKey,
+		})
+	case keys.PublicKey != opts.PublicKey || keys.PrivateKey != opts.PrivateKey:
+		slog.Info("Imported VAPID keys differ from the stored ones, replacing them")
+		return replaceVAPIDKeys(ctx, conn, opts.PublicKey, opts.PrivateKey)
+	}
+	return nil
+}
+
+// RotateVAPIDKeys replaces the VAPID keys with new ones. Existing push
+// subscriptions are bound to the old public key, so they are marked as stale
+// until the browsers subscribe again.
+func RotateVAPIDKeys(ctx context.Context, conn *db.Connection) (*db.VapidKey, error) {
+	privateKey, publicKey, err := webpush.GenerateVAPIDKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate VAPID keys: %w", err)
+	}
+	if err := replaceVAPIDKeys(ctx, conn, publicKey, privateKey); err != nil {
+		return nil, err
+	}
+	return conn.Q.GetVAPIDKeys(ctx)
+}
+
+func replaceVAPIDKeys(ctx context.Context, conn *db.Connection, publicKey, privateKey string) error {
+	tx, err := conn.Get().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := conn.Q.WithTx(tx)
+	if err := q.UpdateVAPIDKeys(ctx, &db.UpdateVAPIDKeysParams{
+		PublicKey:  publicKey,
+		PrivateKey: privateKey,
+	}); err != nil {
+		return fmt.Errorf("failed to store VAPID keys: %w", err)
+	}
+	if err := q.MarkPushSubscriptionsStale(ctx); err != nil {
+		return fmt.Errorf("failed to mark push subscriptions as stale: %w", err)
+	}
+	return tx.Commit()
+}
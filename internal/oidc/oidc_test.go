@@ -0,0 +1,400 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID    = "beacon"
+	testRedirectURL = "https://status.example.com/api/auth/oidc/callback"
+)
+
+// signingKey is a private key of the mock issuer
+type signingKey struct {
+	kid    string
+	method jwt.SigningMethod
+	key    crypto.Signer
+}
+
+func newRSAKey(t *testing.T, kid string) *signingKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &signingKey{kid: kid, method: jwt.SigningMethodRS256, key: key}
+}
+
+func newECKey(t *testing.T, kid string) *signingKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &signingKey{kid: kid, method: jwt.SigningMethodES256, key: key}
+}
+
+func (k *signingKey) jwk() map[string]string {
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	switch pub := k.key.Public().(type) {
+	case *rsa.PublicKey:
+		return map[string]string{
+			"kty": "RSA", "kid": k.kid, "use": "sig",
+			"n": encode(pub.N.Bytes()),
+			"e": encode(big.NewInt(int64(pub.E)).Bytes()),
+		}
+	case *ecdsa.PublicKey:
+		return map[string]string{
+			"kty": "EC", "kid": k.kid, "use": "sig", "crv": "P-256",
+			"x": encode(pub.X.FillBytes(make([]byte, 32))),
+			"y": encode(pub.Y.FillBytes(make([]byte, 32))),
+		}
+	}
+	panic("unsupported key")
+}
+
+// grant is an authorization code handed out by the mock issuer
+type grant struct {
+	challenge string
+	nonce     string
+}
+
+// issuer is a mock OpenID Connect provider serving discovery, its keys and
+// the token endpoint
+type issuer struct {
+	*httptest.Server
+	t *testing.T
+
+	mu        sync.Mutex
+	published []*signingKey    // served as JWKS
+	signer    *signingKey      // signs ID tokens, usually a published key
+	claims    jwt.MapClaims    // overrides of the ID token claims
+	grants    map[string]grant // by code
+}
+
+func newIssuer(t *testing.T) *issuer {
+	t.Helper()
+	key := newRSAKey(t, "rsa-1")
+	iss := &issuer{
+		t:         t,
+		published: []*signingKey{key},
+		signer:    key,
+		grants:    make(map[string]grant),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                 iss.URL,
+			"authorization_endpoint": iss.URL + "/authorize",
+			"token_endpoint":         iss.URL + "/token",
+			"jwks_uri":               iss.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		iss.mu.Lock()
+		defer iss.mu.Unlock()
+		keys := make([]map[string]string, 0, len(iss.published))
+		for _, k := range iss.published {
+			keys = append(keys, k.jwk())
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"keys": keys})
+	})
+	mux.HandleFunc("POST /token", iss.token)
+	iss.Server = httptest.NewServer(mux)
+	t.Cleanup(iss.Close)
+	return iss
+}
+
+// authorize plays the user signing in at the auth URL and returns the code
+// the provider redirects back with
+func (iss *issuer) authorize(authURL string) string {
+	iss.t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		iss.t.Fatal(err)
+	}
+	query := u.Query()
+	if got := query.Get("code_challenge_method"); got != "S256" {
+		iss.t.Fatalf("code_challenge_method = %q, want S256", got)
+	}
+	if got := query.Get("redirect_uri"); got != testRedirectURL {
+		iss.t.Fatalf("redirect_uri = %q, want %q", got, testRedirectURL)
+	}
+
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+	code := "code-" + query.Get("state")
+	iss.grants[code] = grant{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	return code
+}
+
+func (iss *issuer) token(w http.ResponseWriter, r *http.Request) {
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	g, ok := iss.grants[r.PostForm.Get("code")]
+	delete(iss.grants, r.PostForm.Get("code"))
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok ||
+		r.PostForm.Get("client_id") != testClientID ||
+		r.PostForm.Get("redirect_uri") != testRedirectURL ||
+		base64.RawURLEncoding.EncodeToString(challenge[:]) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error":             "invalid_grant",
+			"error_description": "code or verifier mismatch",
+		})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   iss.URL,
+		"aud":   testClientID,
+		"sub":   "user-1",
+		"nonce": g.nonce,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+	}
+	for name, v := range iss.claims {
+		if v == nil {
+			delete(claims, name)
+			continue
+		}
+		claims[name] = v
+	}
+	token := jwt.NewWithClaims(iss.signer.method, claims)
+	token.Header["kid"] = iss.signer.kid
+	idToken, err := token.SignedString(iss.signer.key)
+	if err != nil {
+		iss.t.Error(err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"id_token": idToken, "token_type": "Bearer"})
+}
+
+// rotate publishes a new key and signs with it, dropping the old ones
+func (iss *issuer) rotate(key *signingKey) {
+	iss.mu.Lock()
+	defer iss.mu.Unlock()
+	iss.published = []*signingKey{key}
+	iss.signer = key
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newProvider(iss *issuer) *Provider {
+	return New(Config{
+		Issuer:      iss.URL,
+		ClientID:    testClientID,
+		RedirectURL: testRedirectURL,
+		Scopes:      []string{"openid", "profile"},
+	})
+}
+
+// signIn runs the authorization code flow, redeeming the code with exchange
+// instead of the request that started it
+func signIn(t *testing.T, iss *issuer, p *Provider, exchange func(Request) Request) (Claims, error) {
+	t.Helper()
+	req, err := NewRequest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := p.AuthURL(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := iss.authorize(authURL)
+	if exchange != nil {
+		req = exchange(req)
+	}
+	return p.Exchange(context.Background(), code, req)
+}
+
+func TestExchange(t *testing.T) {
+	iss := newIssuer(t)
+	unpublished := newRSAKey(t, "rsa-1") // the published key ID, but another key
+
+	tests := []struct {
+		name     string
+		claims   jwt.MapClaims
+		signer   *signingKey
+		exchange func(Request) Request
+		wantErr  string
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name:   "authorized party of the client",
+			claims: jwt.MapClaims{"aud": []string{testClientID, "other"}, "azp": testClientID},
+		},
+		{
+			name: "nonce mismatch",
+			exchange: func(req Request) Request {
+				req.Nonce = "other"
+				return req
+			},
+			wantErr: "nonce mismatch",
+		},
+		{
+			name:    "missing nonce",
+			claims:  jwt.MapClaims{"nonce": nil},
+			wantErr: "nonce mismatch",
+		},
+		{
+			name: "wrong PKCE verifier",
+			exchange: func(req Request) Request {
+				req.Verifier = "other"
+				return req
+			},
+			wantErr: "invalid_grant",
+		},
+		{
+			name:    "bad signature",
+			signer:  unpublished,
+			wantErr: "signature is invalid",
+		},
+		{
+			name:    "other audience",
+			claims:  jwt.MapClaims{"aud": "other"},
+			wantErr: "invalid audience",
+		},
+		{
+			name:    "other authorized party",
+			claims:  jwt.MapClaims{"aud": []string{testClientID, "other"}, "azp": "other"},
+			wantErr: `issued to "other"`,
+		},
+		{
+			name:    "other issuer",
+			claims:  jwt.MapClaims{"iss": "https://evil.example.com"},
+			wantErr: "invalid issuer",
+		},
+		{
+			name:    "expired",
+			claims:  jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()},
+			wantErr: "token is expired",
+		},
+		{
+			name:    "without expiry",
+			claims:  jwt.MapClaims{"exp": nil},
+			wantErr: "exp claim is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iss.mu.Lock()
+			iss.claims = tt.claims
+			iss.signer = iss.published[0]
+			if tt.signer != nil {
+				iss.signer = tt.signer
+			}
+			iss.mu.Unlock()
+
+			claims, err := signIn(t, iss, newProvider(iss), tt.exchange)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Exchange() error = %v", err)
+				}
+				if got := claims.String("sub"); got != "user-1" {
+					t.Errorf("sub = %q, want user-1", got)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Exchange() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExchangeKeyRotation(t *testing.T) {
+	iss := newIssuer(t)
+	p := newProvider(iss)
+
+	if _, err := signIn(t, iss, p, nil); err != nil {
+		t.Fatalf("sign in before rotation: %v", err)
+	}
+
+	iss.rotate(newECKey(t, "ec-2"))
+
+	// Unknown keys are only refetched once keysRefresh passed
+	if _, err := signIn(t, iss, p, nil); err == nil || !strings.Contains(err.Error(), `unknown key "ec-2"`) {
+		t.Fatalf("sign in right after rotation: error = %v, want unknown key", err)
+	}
+	p.mu.Lock()
+	p.keysFetched = time.Now().Add(-keysRefresh)
+	p.mu.Unlock()
+
+	if _, err := signIn(t, iss, p, nil); err != nil {
+		t.Fatalf("sign in after rotation: %v", err)
+	}
+	if _, ok := p.keys["rsa-1"]; ok {
+		t.Error("the rotated out key is still trusted")
+	}
+}
+
+func TestDiscoverIssuerMismatch(t *testing.T) {
+	iss := newIssuer(t)
+	p := New(Config{Issuer: iss.URL + "/", ClientID: testClientID, RedirectURL: testRedirectURL})
+
+	req, err := NewRequest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.AuthURL(context.Background(), req); err == nil || !strings.Contains(err.Error(), "expected") {
+		t.Errorf("AuthURL() error = %v, want issuer mismatch", err)
+	}
+}
+
+func TestClaimsStrings(t *testing.T) {
+	tests := []struct {
+		name  string
+		claim any
+		want  []string
+	}{
+		{"list", []any{"sre", "oncall"}, []string{"sre", "oncall"}},
+		{"single", "sre", []string{"sre"}},
+		{"mixed", []any{"sre", 42}, []string{"sre"}},
+		{"missing", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := Claims{}
+			if tt.claim != nil {
+				claims["groups"] = tt.claim
+			}
+			got := claims.Strings("groups")
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Strings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
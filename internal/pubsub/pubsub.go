@@ -0,0 +1,60 @@
+// Package pubsub delivers events to subscribers within the process
+package pubsub
+
+import "sync"
+
+// bufferSize is the number of events a subscriber may fall behind
+const bufferSize = 64
+
+// Event is a message published to all subscribers
+type Event struct {
+	Type string
+	Data any
+}
+
+// Broker fans out published events to its subscribers
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]struct{}
+}
+
+func New() *Broker {
+	return &Broker{subscribers: make(map[chan Event]struct{})}
+}
+
+// Subscribe returns a channel of published events and a function that ends
+// the subscription and closes the channel
+func (b *Broker) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, bufferSize)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish sends an event to all subscribers. Subscribers that fall behind
+// miss events rather than blocking the publisher.
+func (b *Broker) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
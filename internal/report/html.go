@@ -0,0 +1,44 @@
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+//go:embed report.html
+var pageHTML string
+
+var page = template.Must(template.New("report").Funcs(template.FuncMap{
+	"date":     func(t time.Time) string { return t.Format("2 January 2006") },
+	"deref":    func(v *float64) float64 { return *v },
+	"derefInt": func(v *int64) int64 { return *v },
+	"duration": formatDuration,
+}).Parse(pageHTML))
+
+// WriteHTML writes the report as a page for printing or saving as PDF
+func (r *Report) WriteHTML(w io.Writer, title string) error {
+	return page.Execute(w, struct {
+		Title  string
+		Report *Report
+	}{title, r})
+}
+
+// formatDuration formats seconds with their two largest units, e.g. "2h 5m"
+func formatDuration(seconds int64) string {
+	d := time.Duration(seconds) * time.Second
+	days := int64(d / (24 * time.Hour))
+	hours := int64(d % (24 * time.Hour) / time.Hour)
+	minutes := int64(d % time.Hour / time.Minute)
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm %ds", minutes, seconds%60)
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
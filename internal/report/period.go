@@ -0,0 +1,57 @@
+package report
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Period is a calendar period of a report
+type Period string
+
+const (
+	PeriodWeek    Period = "week" // starting on Monday
+	PeriodMonth   Period = "month"
+	PeriodQuarter Period = "quarter"
+)
+
+var periods = []Period{PeriodWeek, PeriodMonth, PeriodQuarter}
+
+// ParsePeriod validates a period
+func ParsePeriod(s string) (Period, error) {
+	if !slices.Contains(periods, Period(s)) {
+		return "", fmt.Errorf("invalid period %q, expected week, month or quarter", s)
+	}
+	return Period(s), nil
+}
+
+// Bounds returns the start and the exclusive end of the period containing t,
+// in the location of t
+func (p Period) Bounds(t time.Time) (time.Time, time.Time) {
+	year, month, day := t.Date()
+	switch p {
+	case PeriodWeek:
+		// Weekdays count from Sunday, weeks start on Monday
+		offset := (int(t.Weekday()) + 6) % 7
+		start := time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 0, 7)
+	case PeriodQuarter:
+		start := time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, t.Location())
+		return start, start.AddDate(0, 3, 0)
+	}
+	start := time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 1, 0)
+}
+
+// Label names the period starting at start, e.g. "2025-W07", "February 2025"
+// or "Q1 2025"
+func (p Period) Label(start time.Time) string {
+	switch p {
+	case PeriodWeek:
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case PeriodQuarter:
+		return fmt.Sprintf("Q%d %d", (int(start.Month())-1)/3+1, start.Year())
+	}
+	return start.Format("January 2006")
+}
@@ -0,0 +1,132 @@
+package report
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriodBounds(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("no timezone database:", err)
+	}
+	date := func(year int, month time.Month, day, hour int, loc *time.Location) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name     string
+		period   Period
+		t        time.Time
+		from, to time.Time
+		label    string
+	}{
+		{
+			name:   "week from Wednesday",
+			period: PeriodWeek,
+			t:      date(2025, 2, 12, 15, time.UTC),
+			from:   date(2025, 2, 10, 0, time.UTC),
+			to:     date(2025, 2, 17, 0, time.UTC),
+			label:  "2025-W07",
+		},
+		{
+			name:   "week from Sunday",
+			period: PeriodWeek,
+			t:      date(2025, 2, 16, 23, time.UTC),
+			from:   date(2025, 2, 10, 0, time.UTC),
+			to:     date(2025, 2, 17, 0, time.UTC),
+			label:  "2025-W07",
+		},
+		{
+			name:   "week from Monday midnight",
+			period: PeriodWeek,
+			t:      date(2025, 2, 17, 0, time.UTC),
+			from:   date(2025, 2, 17, 0, time.UTC),
+			to:     date(2025, 2, 24, 0, time.UTC),
+			label:  "2025-W08",
+		},
+		{
+			name:   "week across the year",
+			period: PeriodWeek,
+			t:      date(2025, 1, 1, 12, time.UTC),
+			from:   date(2024, 12, 30, 0, time.UTC),
+			to:     date(2025, 1, 6, 0, time.UTC),
+			label:  "2025-W01",
+		},
+		{
+			name:   "month",
+			period: PeriodMonth,
+			t:      date(2025, 2, 28, 23, time.UTC),
+			from:   date(2025, 2, 1, 0, time.UTC),
+			to:     date(2025, 3, 1, 0, time.UTC),
+			label:  "February 2025",
+		},
+		{
+			name:   "December",
+			period: PeriodMonth,
+			t:      date(2025, 12, 31, 12, time.UTC),
+			from:   date(2025, 12, 1, 0, time.UTC),
+			to:     date(2026, 1, 1, 0, time.UTC),
+			label:  "December 2025",
+		},
+		{
+			name:   "month across daylight saving time",
+			period: PeriodMonth,
+			t:      date(2025, 3, 15, 12, berlin),
+			from:   date(2025, 3, 1, 0, berlin),
+			to:     date(2025, 4, 1, 0, berlin),
+			label:  "March 2025",
+		},
+		{
+			name:   "first quarter",
+			period: PeriodQuarter,
+			t:      date(2025, 3, 31, 23, time.UTC),
+			from:   date(2025, 1, 1, 0, time.UTC),
+			to:     date(2025, 4, 1, 0, time.UTC),
+			label:  "Q1 2025",
+		},
+		{
+			name:   "second quarter",
+			period: PeriodQuarter,
+			t:      date(2025, 4, 1, 0, time.UTC),
+			from:   date(2025, 4, 1, 0, time.UTC),
+			to:     date(2025, 7, 1, 0, time.UTC),
+			label:  "Q2 2025",
+		},
+		{
+			name:   "last quarter",
+			period: PeriodQuarter,
+			t:      date(2025, 11, 20, 8, berlin),
+			from:   date(2025, 10, 1, 0, berlin),
+			to:     date(2026, 1, 1, 0, berlin),
+			label:  "Q4 2025",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := tt.period.Bounds(tt.t)
+			if !from.Equal(tt.from) || !to.Equal(tt.to) {
+				t.Errorf("Bounds(%v) = %v, %v, want %v, %v", tt.t, from, to, tt.from, tt.to)
+			}
+			if from.Location() != tt.t.Location() {
+				t.Errorf("Bounds(%v) in %v, want %v", tt.t, from.Location(), tt.t.Location())
+			}
+			if got := tt.period.Label(from); got != tt.label {
+				t.Errorf("Label(%v) = %q, want %q", from, got, tt.label)
+			}
+		})
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	for _, s := range []string{"week", "month", "quarter"} {
+		if p, err := ParsePeriod(s); err != nil || string(p) != s {
+			t.Errorf("ParsePeriod(%q) = %q, %v", s, p, err)
+		}
+	}
+	for _, s := range []string{"", "year", "Month"} {
+		if _, err := ParsePeriod(s); err == nil {
+			t.Errorf("ParsePeriod(%q) succeeded, want an error", s)
+		}
+	}
+}
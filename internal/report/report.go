@@ -0,0 +1,204 @@
+// Package report computes uptime reports of monitors for calendar periods,
+// e.g. for SLA reviews
+package report
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/util"
+)
+
+// Report holds the figures of monitors for a period
+type Report struct {
+	Period      Period           `json:"period"`
+	Label       string           `json:"label"`
+	From        time.Time        `json:"from"`
+	To          time.Time        `json:"to"` // exclusive
+	Timezone    string           `json:"timezone"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	Monitors    []*MonitorReport `json:"monitors"`
+}
+
+// MonitorReport holds the figures of a monitor. Checks during maintenance
+// don't count, figures without checks to base them on are null.
+type MonitorReport struct {
+	ID              int64    `json:"id"`
+	Name            string   `json:"name"`
+	Group           string   `json:"group,omitempty"`
+	Checks          int64    `json:"checks"`
+	UptimePct       *float64 `json:"uptime_pct"`
+	Outages         int      `json:"outages"`
+	DowntimeSeconds int64    `json:"downtime_seconds"`
+	LongestSeconds  int64    `json:"longest_outage_seconds"`
+	MTTRSeconds     *int64   `json:"mttr_seconds"` // mean time to recovery
+	MTBFSeconds     *int64   `json:"mtbf_seconds"` // mean time between failures
+	P95ResponseTime *int64   `json:"p95_response_time"`
+}
+
+// Generate computes the report of the monitors for the period containing t,
+// in the location of t. Ongoing periods are reported up to now.
+func Generate(
+	ctx context.Context,
+	conn *db.Connection,
+	monitors []*db.Monitor,
+	period Period,
+	t time.Time,
+) (*Report, error) {
+	from, to := period.Bounds(t)
+	report := &Report{
+		Period:      period,
+		Label:       period.Label(from),
+		From:        from,
+		To:          to,
+		Timezone:    t.Location().String(),
+		GeneratedAt: time.Now().In(t.Location()),
+		Monitors:    make([]*MonitorReport, 0, len(monitors)),
+	}
+
+	end := to
+	if report.GeneratedAt.Before(end) {
+		end = report.GeneratedAt
+	}
+	// Until the outages of older checks are derived, e.g. right after an
+	// upgrade, they're derived from the checks of the period instead
+	recorded, err := conn.Q.OutagesDerived(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check whether outages were derived: %w", err)
+	}
+	since, until := from.UTC(), to.UTC()
+	for _, m := range monitors {
+		mr, err := monitorReport(ctx, conn, m, since, until, end, recorded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to report on monitor %d: %w", m.ID, err)
+		}
+		report.Monitors = append(report.Monitors, mr)
+	}
+	return report, nil
+}
+
+// monitorReport reads the checks of a monitor in the period once, along with
+// its recorded outages, or deriving outages from consecutive down checks if
+// they aren't all recorded
+func monitorReport(
+	ctx context.Context,
+	conn *db.Connection,
+	m *db.Monitor,
+	since, until, end time.Time,
+	recorded bool,
+) (*MonitorReport, error) {
+	mr := &MonitorReport{ID: m.ID, Name: m.Name, Group: m.GroupName}
+
+	var (
+		first     time.Time
+		last      time.Time
+		down      *time.Time // start of the ongoing outage
+		upCount   int64
+		downtime  time.Duration
+		longest   time.Duration
+		latencies []int64
+	)
+	closeOutage := func(at time.Time) {
+		d := at.Sub(*down)
+		downtime += d
+		longest = max(longest, d)
+		mr.Outages++
+		down = nil
+	}
+
+	err := conn.Q.EachCheck(ctx, m.ID, &since, &until, func(c *db.Check) error {
+		if c.State != nil && *c.State == "maintenance" {
+			return nil
+		}
+		if mr.Checks == 0 {
+			first = c.CheckedAt
+		}
+		mr.Checks++
+		last = c.CheckedAt
+		if c.IsUp {
+			upCount++
+			if c.State == nil {
+				latencies = append(latencies, c.ResponseTime)
+			}
+			if down != nil {
+				closeOutage(c.CheckedAt)
+			}
+		} else if down == nil {
+			at := c.CheckedAt
+			down = &at
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if mr.Checks == 0 {
+		return mr, nil
+	}
+	// Checks cover the period up to the next one that would be due, but not
+	// beyond its end or now, e.g. after a monitor was removed
+	if next := last.Add(time.Duration(m.CheckInterval) * time.Second); next.Before(end) {
+		end = next
+	}
+	if down != nil {
+		closeOutage(end)
+	}
+	if recorded {
+		var err error
+		if mr.Outages, downtime, longest, err = recordedOutages(ctx, conn, m.ID, since, end); err != nil {
+			return nil, err
+		}
+	}
+
+	uptime := math.Round(float64(upCount)*100/float64(mr.Checks)*1000) / 1000
+	mr.UptimePct = &uptime
+	mr.DowntimeSeconds = int64(downtime.Seconds())
+	mr.LongestSeconds = int64(longest.Seconds())
+	if mr.Outages > 0 {
+		mttr := int64(downtime.Seconds()) / int64(mr.Outages)
+		mtbf := int64((end.Sub(first) - downtime).Seconds()) / int64(mr.Outages)
+		mr.MTTRSeconds, mr.MTBFSeconds = &mttr, &mtbf
+	}
+	if len(latencies) > 0 {
+		slices.Sort(latencies)
+		p95 := util.Percentile(latencies, 95)
+		mr.P95ResponseTime = &p95
+	}
+	return mr, nil
+}
+
+// recordedOutages returns the number, total and longest duration of the
+// recorded outages of a monitor, clipped to since and end
+func recordedOutages(
+	ctx context.Context,
+	conn *db.Connection,
+	monitorID int64,
+	since, end time.Time,
+) (count int, downtime, longest time.Duration, err error) {
+	outages, err := conn.Q.GetPeriodOutages(ctx, &db.GetPeriodOutagesParams{
+		MonitorID: monitorID,
+		Until:     end,
+		Since:     &since,
+	})
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	for _, o := range outages {
+		start, stop := o.StartedAt, end
+		if start.Before(since) {
+			start = since
+		}
+		if o.EndedAt != nil && o.EndedAt.Before(end) {
+			stop = *o.EndedAt
+		}
+		d := stop.Sub(start)
+		downtime += d
+		longest = max(longest, d)
+		count++
+	}
+	return count, downtime, longest, nil
+}
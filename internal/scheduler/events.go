@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/pubsub"
+)
+
+// Types of events published by the scheduler
+const (
+	EventCheck = "check"
+	EventState = "state"
+)
+
+// CheckEvent is published for every stored check
+type CheckEvent struct {
+	MonitorID    int64     `json:"monitor_id"`
+	CheckedAt    time.Time `json:"checked_at"`
+	IsUp         bool      `json:"is_up"`
+	StatusCode   int64     `json:"status_code"`
+	ResponseTime int64     `json:"response_time"`
+	Error        *string   `json:"error,omitempty"`
+	State        *string   `json:"state,omitempty"` // maintenance or dependency_down
+	Private      bool      `json:"private,omitempty"`
+}
+
+// StateEvent is published when a monitor changes its status, or starts or
+// stops flapping
+type StateEvent struct {
+	MonitorID int64  `json:"monitor_id"`
+	Previous  Status `json:"previous"`
+	Reason    string `json:"reason,omitempty"` // of down and degraded statuses
+	Private   bool   `json:"private,omitempty"`
+	State
+}
+
+func (s *Scheduler) publishCheck(
+	monitor *db.Monitor,
+	result *db.CreateCheckParams,
+	checkedAt time.Time,
+) {
+	s.events.Publish(pubsub.Event{Type: EventCheck, Data: CheckEvent{
+		MonitorID:    result.MonitorID,
+		CheckedAt:    checkedAt.UTC(),
+		IsUp:         result.IsUp,
+		StatusCode:   result.StatusCode,
+		ResponseTime: result.ResponseTime,
+		Error:        result.Error,
+		State:        result.State,
+		Private:      !monitor.Public,
+	}})
+}
+
+func (s *Scheduler) publishState(monitor *db.Monitor, prev Status, reason string) {
+	state := s.State(monitor.ID)
+	if state.Status == StatusUp {
+		reason = ""
+	}
+	s.events.Publish(pubsub.Event{Type: EventState, Data: StateEvent{
+		MonitorID: monitor.ID,
+		Previous:  prev,
+		Reason:    reason,
+		Private:   !monitor.Public,
+		State:     state,
+	}})
+}
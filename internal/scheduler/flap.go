@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+// Flap detection compares the weighted share of state changes within the
+// recent checks against two thresholds, so a monitor doesn't toggle between
+// flapping and stable on every check.
+const (
+	flapWindow    = 21 // checks, i.e. up to 20 state changes
+	flapMinChecks = 10
+	flapStart     = 0.5
+	flapStop      = 0.25
+)
+
+// flapScore returns the weighted share of state changes in history, which is
+// ordered newest first. Recent changes weigh up to 1.5 times older ones.
+func flapScore(history []bool) float64 {
+	if len(history) < flapMinChecks {
+		return 0
+	}
+
+	var changes, total float64
+	last := float64(len(history) - 2)
+	for i := 1; i < len(history); i++ {
+		weight := 1.2 - 0.4*float64(i-1)/last
+		total += weight
+		if history[i] != history[i-1] {
+			changes += weight
+		}
+	}
+	return changes / total
+}
+
+// detectFlapping updates the flapping state of a monitor, whose status changed
+// from prev to status with the latest check, and reports whether its regular
+// up/down notifications have to be suppressed.
+func (s *Scheduler) detectFlapping(
+	ctx context.Context,
+	monitor *db.Monitor,
+	prev, status Status,
+	reason string,
+) bool {
+	flapping := s.State(monitor.ID).Flapping
+
+	history, err := s.conn.Q.GetRecentChecks(ctx, &db.GetRecentChecksParams{
+		MonitorID: monitor.ID,
+		Limit:     flapWindow,
+	})
+	if err != nil {
+		slog.Error("Failed to get recent checks", "monitor_id", monitor.ID, "error", err)
+		return flapping
+	}
+
+	score := flapScore(history)
+	switch {
+	case !flapping && score >= flapStart:
+		s.setFlapping(ctx, monitor.ID, true)
+		s.publishState(monitor, prev, reason)
+		slog.Info("Monitor is flapping", "monitor_id", monitor.ID, "score", score)
+
+		reason := fmt.Sprintf("%.0f%% of the last %d checks changed state", score*100, len(history))
+		if err := s.notifier.SendMonitorFlappingNotification(ctx, monitor, reason); err != nil {
+			slog.Error(
+				"Failed to send monitor flapping notification",
+				"monitor_id",
+				monitor.ID,
+				"error",
+				err,
+			)
+		}
+		return true
+
+	case flapping && score < flapStop:
+		s.setFlapping(ctx, monitor.ID, false)
+		s.publishState(monitor, prev, reason)
+		slog.Info("Monitor stopped flapping", "monitor_id", monitor.ID, "status", status)
+
+		// Report where the monitor settled
+		if status == StatusDown {
+			err = s.notifier.SendMonitorDownNotification(ctx, monitor, reason)
+		} else {
+			err = s.notifier.SendMonitorStableNotification(ctx, monitor)
+		}
+		if err != nil {
+			slog.Error(
+				"Failed to send monitor stable notification",
+				"monitor_id",
+				monitor.ID,
+				"error",
+				err,
+			)
+		}
+		return true
+	}
+	return flapping
+}
@@ -0,0 +1,182 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+// recordOutage keeps the outages of a monitor, runs of consecutive failed
+// checks, up to date with a check stored at checkedAt. Checks during
+// maintenance neither start nor end outages.
+func (s *Scheduler) recordOutage(
+	ctx context.Context,
+	monitor *db.Monitor,
+	result *db.CreateCheckParams,
+	checkedAt time.Time,
+) {
+	if result.State != nil && *result.State == CheckStateMaintenance {
+		return
+	}
+
+	open, err := s.conn.Q.GetOpenOutage(ctx, monitor.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		open, err = nil, nil
+	} else if err != nil {
+		slog.Error("Failed to get open outage", "monitor_id", monitor.ID, "error", err)
+		return
+	}
+
+	switch {
+	case result.IsUp && open != nil:
+		err = s.conn.Q.EndOutage(ctx, &db.EndOutageParams{EndedAt: &checkedAt, ID: open.ID})
+	case !result.IsUp && open != nil:
+		err = s.conn.Q.ExtendOutage(ctx, &db.ExtendOutageParams{
+			StatusCodes: addStatusCode(open.StatusCodes, result.StatusCode),
+			ID:          open.ID,
+		})
+	case !result.IsUp:
+		err = s.conn.Q.CreateOutage(ctx, &db.CreateOutageParams{
+			MonitorID:   monitor.ID,
+			StartedAt:   checkedAt,
+			FirstError:  checkError(result.Error, result.StatusCode),
+			StatusCodes: addStatusCode("", result.StatusCode),
+			Checks:      1,
+		})
+	}
+	if err != nil {
+		slog.Error("Failed to record outage", "monitor_id", monitor.ID, "error", err)
+	}
+}
+
+// deriveOutages derives the outages of the checks stored before outages were
+// recorded, scheduling each monitor once its outages are derived so checks
+// don't record outages meanwhile. Failed monitors are derived again on the
+// next start.
+func (s *Scheduler) deriveOutages(ctx context.Context, monitors []*db.Monitor) {
+	slog.Info("Deriving outages from stored checks", "monitors", len(monitors))
+
+	complete := true
+	for i, monitor := range monitors {
+		if ctx.Err() != nil {
+			return
+		}
+		if monitor == nil {
+			continue
+		}
+
+		count, err := s.deriveMonitorOutages(ctx, monitor.ID)
+		if err != nil {
+			slog.Error("Failed to derive outages", "monitor_id", monitor.ID, "error", err)
+			complete = false
+		} else {
+			slog.Info("Derived outages",
+				"monitor_id", monitor.ID,
+				"outages", count,
+				"progress", fmt.Sprintf("%d/%d", i+1, len(monitors)),
+			)
+		}
+
+		// With its current settings, unless removed meanwhile
+		current, err := s.conn.Q.GetMonitor(ctx, monitor.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		} else if err != nil {
+			current = monitor
+		}
+		s.scheduleIdle(current)
+	}
+	if !complete {
+		return
+	}
+
+	if err := s.conn.Q.CreateSetting(ctx, &db.CreateSettingParams{
+		Key:   db.SettingOutagesDerived,
+		Value: "1",
+	}); err != nil {
+		slog.Error("Failed to store that outages were derived", "error", err)
+	}
+}
+
+// deriveMonitorOutages replaces the outages of a monitor by those derived
+// from its stored checks, in a transaction so a failed attempt leaves none
+// behind
+func (s *Scheduler) deriveMonitorOutages(ctx context.Context, monitorID int64) (int, error) {
+	tx, err := s.conn.Get().BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := s.conn.Q.WithTx(tx)
+	if err := q.DeleteMonitorOutages(ctx, monitorID); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	var open *db.CreateOutageParams
+	err = q.EachCheck(ctx, monitorID, nil, nil, func(c *db.Check) error {
+		if c.State != nil && *c.State == CheckStateMaintenance {
+			return nil
+		}
+		switch {
+		case c.IsUp && open != nil:
+			open.EndedAt = &c.CheckedAt
+			if err := q.CreateOutage(ctx, open); err != nil {
+				return err
+			}
+			open = nil
+			count++
+		case !c.IsUp && open != nil:
+			open.Checks++
+			open.StatusCodes = addStatusCode(open.StatusCodes, c.StatusCode)
+		case !c.IsUp:
+			open = &db.CreateOutageParams{
+				MonitorID:   monitorID,
+				StartedAt:   c.CheckedAt,
+				FirstError:  checkError(c.Error, c.StatusCode),
+				StatusCodes: addStatusCode("", c.StatusCode),
+				Checks:      1,
+			}
+		}
+		return nil
+	})
+	if err == nil && open != nil {
+		// Still ongoing, ended by the next successful check
+		err = q.CreateOutage(ctx, open)
+		count++
+	}
+	if err != nil {
+		return 0, err
+	}
+	return count, tx.Commit()
+}
+
+// checkError describes why a check failed
+func checkError(err *string, statusCode int64) string {
+	if err != nil {
+		return *err
+	}
+	return fmt.Sprintf("unexpected status code %d", statusCode)
+}
+
+// addStatusCode adds a status code to the comma separated codes of an outage,
+// unless it was seen before. Checks failing without a response have none.
+func addStatusCode(codes string, statusCode int64) string {
+	code := strconv.FormatInt(statusCode, 10)
+	if statusCode == 0 || slices.Contains(strings.Split(codes, ","), code) {
+		return codes
+	}
+	if codes == "" {
+		return code
+	}
+	return codes + "," + code
+}
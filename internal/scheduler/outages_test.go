@@ -0,0 +1,37 @@
+package scheduler
+
+import "testing"
+
+func TestAddStatusCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		codes      string
+		statusCode int64
+		want       string
+	}{
+		{"first", "", 503, "503"},
+		{"next", "503", 502, "503,502"},
+		{"seen before", "503,502", 503, "503,502"},
+		{"seen last", "503,502", 502, "503,502"},
+		{"no response", "", 0, ""},
+		{"no response after codes", "503", 0, "503"},
+		{"prefix of a seen code", "5030", 503, "5030,503"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := addStatusCode(tt.codes, tt.statusCode); got != tt.want {
+				t.Errorf("addStatusCode(%q, %d) = %q, want %q", tt.codes, tt.statusCode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckError(t *testing.T) {
+	refused := "connection refused"
+	if got := checkError(&refused, 0); got != refused {
+		t.Errorf("checkError() = %q, want %q", got, refused)
+	}
+	if got, want := checkError(nil, 503), "unexpected status code 503"; got != want {
+		t.Errorf("checkError() = %q, want %q", got, want)
+	}
+}
@@ -3,28 +3,44 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/mizuchilabs/beacon/internal/checker"
 	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/maintenance"
+	"github.com/mizuchilabs/beacon/internal/metrics"
 	"github.com/mizuchilabs/beacon/internal/notify"
+	"github.com/mizuchilabs/beacon/internal/pubsub"
+	"github.com/mizuchilabs/beacon/internal/util"
 )
 
 type Scheduler struct {
 	conn          *db.Connection
 	checker       *checker.Checker
 	notifier      *notify.Notifier
+	maintenance   *maintenance.Calendar
+	events        *pubsub.Broker
 	wg            sync.WaitGroup
+	mu            sync.RWMutex
+	states        map[int64]State
 	RetentionDays int
+
+	// Context of Start, which monitors scheduled later are checked within
+	ctx     context.Context
+	running map[int64]context.CancelFunc // by monitor ID
 }
 
 func New(
 	conn *db.Connection,
 	checker *checker.Checker,
 	notifier *notify.Notifier,
+	maintenance *maintenance.Calendar,
+	events *pubsub.Broker,
 	retentionDays int,
 ) *Scheduler {
 	if retentionDays <= 1 {
@@ -35,6 +51,10 @@ func New(
 		conn:          conn,
 		checker:       checker,
 		notifier:      notifier,
+		maintenance:   maintenance,
+		events:        events,
+		states:        make(map[int64]State),
+		running:       make(map[int64]context.CancelFunc),
 		RetentionDays: retentionDays,
 	}
 }
@@ -47,13 +67,30 @@ func (s *Scheduler) Start(ctx context.Context) {
 		return
 	}
 
-	// Start monitoring
+	s.mu.Lock()
+	s.ctx = ctx
 	for _, monitor := range monitors {
-		if monitor == nil {
-			continue
+		if monitor != nil && monitor.Flapping {
+			s.states[monitor.ID] = State{Flapping: true}
 		}
+	}
+	s.mu.Unlock()
 
-		s.wg.Go(func() { s.runMonitor(ctx, monitor) })
+	// Start monitoring, in the background after deriving the outages of the
+	// stored checks if they weren't yet
+	derived, err := s.conn.Q.OutagesDerived(ctx)
+	if err != nil {
+		slog.Error("Failed to check whether outages were derived", "error", err)
+	}
+	if derived || err != nil {
+		for _, monitor := range monitors {
+			if monitor == nil {
+				continue
+			}
+			s.Schedule(monitor)
+		}
+	} else {
+		s.wg.Go(func() { s.deriveOutages(ctx, monitors) })
 	}
 	s.wg.Go(func() { s.cleanupJob(ctx) })
 
@@ -64,8 +101,50 @@ func (s *Scheduler) Start(ctx context.Context) {
 	}()
 }
 
+// Schedule starts checking a monitor, or restarts checking it with changed
+// settings. Until Start is called, monitors are left to Start.
+func (s *Scheduler) Schedule(monitor *db.Monitor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx == nil {
+		return
+	}
+	if cancel, ok := s.running[monitor.ID]; ok {
+		cancel()
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.running[monitor.ID] = cancel
+	s.wg.Go(func() { s.runMonitor(ctx, monitor) })
+}
+
+// scheduleIdle schedules a monitor unless it was scheduled meanwhile, e.g.
+// with changed settings by the config sync
+func (s *Scheduler) scheduleIdle(monitor *db.Monitor) {
+	s.mu.RLock()
+	_, ok := s.running[monitor.ID]
+	s.mu.RUnlock()
+	if !ok {
+		s.Schedule(monitor)
+	}
+}
+
+// Unschedule stops checking a monitor and forgets its state
+func (s *Scheduler) Unschedule(monitorID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cancel, ok := s.running[monitorID]; ok {
+		cancel()
+		delete(s.running, monitorID)
+	}
+	delete(s.states, monitorID)
+	metrics.RemoveMonitor(monitorID)
+}
+
 func (s *Scheduler) runMonitor(ctx context.Context, monitor *db.Monitor) {
-	defer s.wg.Done()
+	metrics.MonitorStarted()
+	defer metrics.MonitorStopped()
 
 	ticker := time.NewTicker(time.Duration(monitor.CheckInterval) * time.Second)
 	defer ticker.Stop()
@@ -91,28 +170,200 @@ func (s *Scheduler) performCheck(ctx context.Context, monitor *db.Monitor) {
 	result := s.checker.Check(checkCtx, monitor.Url)
 	result.MonitorID = monitor.ID
 
-	// Store check result
-	if err := s.conn.Q.CreateCheck(checkCtx, result); err != nil {
+	// Unscheduled or shutting down while checking
+	if ctx.Err() != nil {
+		return
+	}
+
+	state := s.checkState(ctx, monitor, result)
+	if state != "" {
+		result.State = &state
+	}
+	metrics.ObserveCheck(monitor.ID, monitor.Name, monitor.Url, monitor.GroupName, metrics.Check{
+		Up:           result.IsUp,
+		StatusCode:   result.StatusCode,
+		ResponseTime: time.Duration(result.ResponseTime) * time.Millisecond,
+		CertExpiry:   result.CertExpiresAt,
+	})
+
+	// Store check result, the check itself may have used up the timeout
+	checkedAt, err := s.conn.Q.CreateCheck(ctx, result)
+	if err != nil {
 		slog.Error("Failed to store check", "monitor_id", monitor.ID, "error", err)
 		return
 	}
+	s.publishCheck(monitor, result, checkedAt)
+	s.recordOutage(ctx, monitor, result, checkedAt)
+	if result.CertExpiresAt != nil {
+		err := s.notifier.SendCertExpiringNotification(ctx, monitor, *result.CertExpiresAt)
+		if err != nil {
+			slog.Error("Failed to send certificate notification", "monitor_id", monitor.ID, "error", err)
+		}
+	}
+
+	// Keep the last known status while notifications are suppressed, so an
+	// outage that outlasts the maintenance or the parent's outage is still
+	// reported afterwards.
+	if state != "" {
+		slog.Debug("Skipping notifications", "monitor_id", monitor.ID, "state", state)
+		return
+	}
+	s.transition(ctx, monitor, result)
+}
 
-	if !result.IsUp && result.Error != nil {
-		if err := s.notifier.SendMonitorDownNotification(ctx, monitor, *result.Error); err != nil {
-			slog.Error(
-				"Failed to send monitor down notification",
-				"monitor_id",
-				monitor.ID,
-				"error",
-				err,
+// checkState tags checks that must not trigger notifications
+func (s *Scheduler) checkState(
+	ctx context.Context,
+	monitor *db.Monitor,
+	result *db.CreateCheckParams,
+) string {
+	if _, ok := s.maintenance.Active(monitor.Name, monitor.GroupName, time.Now()); ok {
+		return CheckStateMaintenance
+	}
+	if !result.IsUp {
+		if parent := s.downParent(ctx, monitor); parent != nil {
+			slog.Debug("Monitor failed because a dependency is down",
+				"monitor_id", monitor.ID,
+				"parent_id", parent.ID,
 			)
+			return CheckStateDependencyDown
 		}
 	}
+	return ""
 }
 
-func (s *Scheduler) cleanupJob(ctx context.Context) {
-	defer s.wg.Done()
+// downParent returns a parent of the monitor that is down, if any. Unless the
+// monitor is already known to be down, parents that look healthy are checked
+// again, as they may have failed after their last check.
+func (s *Scheduler) downParent(ctx context.Context, monitor *db.Monitor) *db.Monitor {
+	parents, err := s.conn.Q.GetMonitorParents(ctx, monitor.ID)
+	if err != nil {
+		slog.Error("Failed to get monitor dependencies", "monitor_id", monitor.ID, "error", err)
+		return nil
+	}
+
+	confirm := s.State(monitor.ID).Status != StatusDown
+	for _, parent := range parents {
+		if s.State(parent.ID).Status == StatusDown {
+			return parent
+		}
+		if confirm && !s.confirmUp(ctx, parent) {
+			return parent
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) confirmUp(ctx context.Context, monitor *db.Monitor) bool {
+	checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return s.checker.Check(checkCtx, monitor.Url).IsUp
+}
+
+// transition notifies subscribers when a monitor changes between up,
+// degraded and down, unless it is flapping. The notifier keeps track of open
+// alerts, so repeated calls are harmless.
+func (s *Scheduler) transition(
+	ctx context.Context,
+	monitor *db.Monitor,
+	result *db.CreateCheckParams,
+) {
+	status := StatusDown
+	reason := fmt.Sprintf("unexpected status code %d", result.StatusCode)
+	if result.Error != nil {
+		reason = *result.Error
+	}
+	if result.IsUp {
+		status = StatusUp
+		if slow, ok := s.degradedReason(ctx, monitor, result); ok {
+			status = StatusDegraded
+			reason = slow
+		}
+	}
+
+	prev := s.setStatus(monitor.ID, status)
+	if prev != status {
+		s.publishState(monitor, prev, reason)
+	}
+	if s.detectFlapping(ctx, monitor, prev, status, reason) {
+		return
+	}
+
+	var err error
+	switch {
+	case status == StatusDown && prev != StatusDown:
+		err = s.notifier.SendMonitorDownNotification(ctx, monitor, reason)
+	case status != StatusDown && (prev == StatusDown || prev == StatusUnknown):
+		// Also after a restart, in case the outage ended while we weren't watching
+		err = s.notifier.SendMonitorUpNotification(ctx, monitor)
+		if err == nil && status == StatusDegraded && prev == StatusDown {
+			err = s.notifier.SendMonitorDegradedNotification(ctx, monitor, reason)
+		}
+	case status == StatusDegraded && prev == StatusUp:
+		err = s.notifier.SendMonitorDegradedNotification(ctx, monitor, reason)
+	case status == StatusUp && prev == StatusDegraded:
+		err = s.notifier.SendMonitorNormalNotification(ctx, monitor)
+	}
+	if err != nil {
+		slog.Error(
+			"Failed to send monitor notification",
+			"monitor_id",
+			monitor.ID,
+			"status",
+			status,
+			"error",
+			err,
+		)
+	}
+}
+
+// degradedReason reports whether a successful check of the monitor counts as
+// degraded, judged by its response time or, if configured, by a percentile of
+// the response times within the window
+func (s *Scheduler) degradedReason(
+	ctx context.Context,
+	monitor *db.Monitor,
+	result *db.CreateCheckParams,
+) (string, bool) {
+	if monitor.DegradedPercentile == 0 {
+		if result.ResponseTime <= monitor.DegradedThreshold {
+			return "", false
+		}
+		return fmt.Sprintf(
+			"response time of %d ms exceeds %d ms",
+			result.ResponseTime,
+			monitor.DegradedThreshold,
+		), true
+	}
+
+	window := time.Duration(monitor.DegradedWindow) * time.Second
+	times, err := s.conn.Q.GetMonitorResponseTimes(ctx, &db.GetMonitorResponseTimesParams{
+		MonitorID: monitor.ID,
+		Since:     time.Now().UTC().Add(-window),
+	})
+	if err != nil {
+		slog.Error("Failed to get response times", "monitor_id", monitor.ID, "error", err)
+		return "", s.State(monitor.ID).Status == StatusDegraded
+	}
+	if len(times) == 0 {
+		return "", false
+	}
+
+	slices.Sort(times)
+	value := util.Percentile(times, float64(monitor.DegradedPercentile))
+	if value <= monitor.DegradedThreshold {
+		return "", false
+	}
+	return fmt.Sprintf(
+		"p%d response time of %d ms over the last %s exceeds %d ms",
+		monitor.DegradedPercentile,
+		value,
+		window,
+		monitor.DegradedThreshold,
+	), true
+}
 
+func (s *Scheduler) cleanupJob(ctx context.Context) {
 	ticker := time.NewTicker(1 * time.Hour)
 	defer ticker.Stop()
 
@@ -123,6 +374,15 @@ func (s *Scheduler) cleanupJob(ctx context.Context) {
 			if err := s.conn.Q.CleanupChecks(ctx, &daysStr); err != nil {
 				slog.Error("Failed to cleanup old checks", "error", err)
 			}
+			if err := s.conn.Q.CleanupNotifications(ctx, &daysStr); err != nil {
+				slog.Error("Failed to cleanup old notifications", "error", err)
+			}
+			if err := s.conn.Q.CleanupAlerts(ctx, &daysStr); err != nil {
+				slog.Error("Failed to cleanup old alerts", "error", err)
+			}
+			if err := s.conn.Q.CleanupEmailSubscribers(ctx); err != nil {
+				slog.Error("Failed to cleanup unconfirmed email subscribers", "error", err)
+			}
 		case <-ctx.Done():
 			return
 		}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+// Status is the last known health of a monitor
+type Status string
+
+const (
+	StatusUnknown  Status = ""
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded" // up, but slower than its threshold
+	StatusDown     Status = "down"
+)
+
+// Check states of checks that don't trigger notifications. Checks during a
+// maintenance window also don't count towards uptime.
+const (
+	CheckStateMaintenance    = "maintenance"
+	CheckStateDependencyDown = "dependency_down"
+)
+
+type State struct {
+	Status   Status    `json:"status"`
+	Since    time.Time `json:"since"`
+	Flapping bool      `json:"flapping"`
+}
+
+// State returns the last known state of a monitor
+func (s *Scheduler) State(monitorID int64) State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.states[monitorID]
+}
+
+// setStatus records the new status and returns the previous one
+func (s *Scheduler) setStatus(monitorID int64, status Status) Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev := s.states[monitorID]
+	if prev.Status != status {
+		s.states[monitorID] = State{Status: status, Since: time.Now(), Flapping: prev.Flapping}
+	}
+	return prev.Status
+}
+
+// setFlapping records whether a monitor is flapping. It's stored with the
+// monitor, so notifications stay suppressed across restarts and the notifier
+// holds escalation in the meantime.
+func (s *Scheduler) setFlapping(ctx context.Context, monitorID int64, flapping bool) {
+	s.mu.Lock()
+	state := s.states[monitorID]
+	state.Flapping = flapping
+	s.states[monitorID] = state
+	s.mu.Unlock()
+
+	if err := s.conn.Q.SetMonitorFlapping(ctx, &db.SetMonitorFlappingParams{
+		Flapping: flapping,
+		ID:       monitorID,
+	}); err != nil {
+		slog.Error("Failed to store flapping state", "monitor_id", monitorID, "error", err)
+	}
+}
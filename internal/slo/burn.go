@@ -0,0 +1,167 @@
+package slo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+)
+
+// evaluationInterval is how often burn rates are compared with the alerts
+const evaluationInterval = time.Minute
+
+// Start evaluates the burn rate alerts in the background
+func (t *Tracker) Start(ctx context.Context) {
+	if t == nil || len(t.objectives) == 0 {
+		return
+	}
+
+	// Alerts that fired before a restart aren't sent again
+	monitors, err := t.conn.Q.GetMonitors(ctx)
+	if err != nil {
+		slog.Error("Failed to get monitors", "error", err)
+	}
+	t.mu.Lock()
+	for _, monitor := range monitors {
+		if monitor.BudgetBurn > 0 {
+			t.firing[monitor.ID] = monitor.BudgetBurn
+		}
+	}
+	t.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(evaluationInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				t.evaluate(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// evaluate notifies the channels of monitors whose burn rate alert started
+// firing, got more severe or stopped. Monitors in maintenance are left as
+// they are until it's over.
+func (t *Tracker) evaluate(ctx context.Context) {
+	monitors, err := t.conn.Q.GetMonitors(ctx)
+	if err != nil {
+		slog.Error("Failed to get monitors", "error", err)
+		return
+	}
+
+	now := time.Now()
+	for _, monitor := range monitors {
+		o, ok := t.objective(monitor.Name)
+		if !ok {
+			continue
+		}
+		if _, ok := t.maintenance.Active(monitor.Name, monitor.GroupName, now); ok {
+			continue
+		}
+
+		burnRate, reason, err := t.firingAlert(ctx, monitor, o)
+		if err != nil {
+			slog.Error("Failed to evaluate burn rate", "monitor_id", monitor.ID, "error", err)
+			continue
+		}
+
+		t.mu.Lock()
+		prev := t.firing[monitor.ID]
+		t.firing[monitor.ID] = burnRate
+		t.mu.Unlock()
+		if burnRate != prev {
+			if err := t.conn.Q.SetMonitorBudgetBurn(ctx, &db.SetMonitorBudgetBurnParams{
+				BudgetBurn: burnRate,
+				ID:         monitor.ID,
+			}); err != nil {
+				slog.Error("Failed to store burn rate", "monitor_id", monitor.ID, "error", err)
+			}
+		}
+
+		switch {
+		case burnRate > prev:
+			slog.Info("Error budget is burning", "monitor_id", monitor.ID, "reason", reason)
+			err = t.notifier.SendBudgetBurnNotification(ctx, monitor, reason)
+		case burnRate == 0 && prev > 0:
+			slog.Info("Error budget stopped burning", "monitor_id", monitor.ID)
+			err = t.notifier.SendBudgetRecoveredNotification(ctx, monitor)
+		}
+		if err != nil {
+			slog.Error(
+				"Failed to send error budget notification",
+				"monitor_id",
+				monitor.ID,
+				"error",
+				err,
+			)
+		}
+	}
+}
+
+// firingAlert returns the burn rate of the most severe alert of the monitor
+// that fires, along with a description, or 0 if none fires
+func (t *Tracker) firingAlert(
+	ctx context.Context,
+	monitor *db.Monitor,
+	o *Objective,
+) (float64, string, error) {
+	now := time.Now().UTC()
+
+	// Alerts may share windows, so each is only counted once
+	counts := make(map[time.Duration]*db.GetCheckCountsRow)
+	countSince := func(window time.Duration) (*db.GetCheckCountsRow, error) {
+		if c, ok := counts[window]; ok {
+			return c, nil
+		}
+		c, err := t.counts(ctx, monitor.ID, o, now.Add(-window))
+		if err != nil {
+			return nil, err
+		}
+		counts[window] = c
+		return c, nil
+	}
+
+	for _, a := range sortedAlerts(o) {
+		long, err := countSince(a.Long)
+		if err != nil {
+			return 0, "", err
+		}
+		short, err := countSince(a.Short)
+		if err != nil {
+			return 0, "", err
+		}
+
+		for _, ind := range o.indicators() {
+			longRate, shortRate := ind.burnRate(long), ind.burnRate(short)
+			if longRate < a.BurnRate || shortRate < a.BurnRate {
+				continue
+			}
+
+			window, err := countSince(o.window())
+			if err != nil {
+				return 0, "", err
+			}
+			budget := fmt.Sprintf("the %d day budget is exhausted", o.WindowDays)
+			if remaining := 1 - ind.burnRate(window); remaining > 0 {
+				budget = fmt.Sprintf("%.0f%% of the %d day budget left", remaining*100, o.WindowDays)
+			}
+			return a.BurnRate, fmt.Sprintf(
+				"%s error budget burns %.1fx as fast as sustainable over %s and %.1fx over %s, %s",
+				ind.name,
+				longRate,
+				formatWindow(a.Long),
+				shortRate,
+				formatWindow(a.Short),
+				budget,
+			), nil
+		}
+	}
+	return 0, "", nil
+}
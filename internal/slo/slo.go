@@ -0,0 +1,327 @@
+// Package slo tracks service level objectives of monitors and their error budgets
+package slo
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mizuchilabs/beacon/internal/db"
+	"github.com/mizuchilabs/beacon/internal/maintenance"
+	"github.com/mizuchilabs/beacon/internal/notify"
+)
+
+// defaultWindowDays is the rolling window of objectives
+const defaultWindowDays = 30
+
+// DefaultAlerts fire when 2% of a 30 day error budget is spent within an
+// hour, or 5% within six hours
+var DefaultAlerts = []BurnRateAlert{
+	{Long: time.Hour, Short: 5 * time.Minute, BurnRate: 14.4},
+	{Long: 6 * time.Hour, Short: 30 * time.Minute, BurnRate: 6},
+}
+
+// Objective is the service level objective of a monitor over a rolling
+// window. Availability is the share of checks that have to succeed, latency
+// the share of successful checks that have to be faster than the threshold,
+// both in percent. Checks during maintenance don't count.
+type Objective struct {
+	Availability     float64         `yaml:"availability,omitempty"`         // e.g. 99.9
+	Latency          float64         `yaml:"latency,omitempty"`              // e.g. 99
+	LatencyThreshold int64           `yaml:"latency_threshold_ms,omitempty"` // degraded threshold by default
+	WindowDays       int             `yaml:"window_days,omitempty"`          // 30 by default
+	Alerts           []BurnRateAlert `yaml:"alerts,omitempty"`
+
+	// Failed checks alert channels as well, not only burn rates
+	CheckAlerts bool `yaml:"check_alerts,omitempty"`
+}
+
+// BurnRateAlert fires when the error budget burns at least BurnRate times
+// faster than sustainable over both windows. The long window makes sure the
+// burn is significant, the short one that it is still going on.
+type BurnRateAlert struct {
+	Long     time.Duration `yaml:"long"`
+	Short    time.Duration `yaml:"short"`
+	BurnRate float64       `yaml:"burn_rate"`
+}
+
+// Validate checks the objective and applies its defaults
+func (o *Objective) Validate() error {
+	if o.Availability == 0 && o.Latency == 0 {
+		return fmt.Errorf("availability or latency is required")
+	}
+	if o.Availability < 0 || o.Availability >= 100 {
+		return fmt.Errorf("availability must be between 0 and 100, got %g", o.Availability)
+	}
+	if o.Latency < 0 || o.Latency >= 100 {
+		return fmt.Errorf("latency must be between 0 and 100, got %g", o.Latency)
+	}
+	if o.LatencyThreshold < 0 {
+		return fmt.Errorf("latency_threshold_ms must not be negative")
+	}
+	if o.LatencyThreshold > 0 && o.Latency == 0 {
+		return fmt.Errorf("latency_threshold_ms requires latency")
+	}
+
+	if o.WindowDays == 0 {
+		o.WindowDays = defaultWindowDays
+	}
+	if o.WindowDays < 1 || o.WindowDays > 365 {
+		return fmt.Errorf("window_days must be between 1 and 365, got %d", o.WindowDays)
+	}
+
+	if len(o.Alerts) == 0 {
+		o.Alerts = DefaultAlerts
+	}
+	for i, a := range o.Alerts {
+		if a.Short <= 0 || a.Long <= a.Short {
+			return fmt.Errorf("alert #%d: long must be longer than short, and both positive", i+1)
+		}
+		if a.Long > o.window() {
+			return fmt.Errorf("alert #%d: long must not exceed the window", i+1)
+		}
+		if a.BurnRate <= 0 {
+			return fmt.Errorf("alert #%d: burn_rate must be positive", i+1)
+		}
+	}
+	return nil
+}
+
+func (o *Objective) window() time.Duration {
+	return time.Duration(o.WindowDays) * 24 * time.Hour
+}
+
+// indicator is a service level indicator of an objective
+type indicator struct {
+	name   string
+	target float64
+
+	// count returns the number of bad and of all relevant checks
+	count func(*db.GetCheckCountsRow) (bad, total int64)
+}
+
+func (o *Objective) indicators() []indicator {
+	var indicators []indicator
+	if o.Availability > 0 {
+		indicators = append(indicators, indicator{
+			name:   "availability",
+			target: o.Availability,
+			count: func(c *db.GetCheckCountsRow) (int64, int64) {
+				return c.DownCount, c.Total
+			},
+		})
+	}
+	if o.Latency > 0 {
+		indicators = append(indicators, indicator{
+			name:   "latency",
+			target: o.Latency,
+			count: func(c *db.GetCheckCountsRow) (int64, int64) {
+				return c.SlowCount, c.Total - c.DownCount
+			},
+		})
+	}
+	return indicators
+}
+
+// burnRate returns how many times faster than sustainable the error budget
+// was spent, 1 meaning it lasts exactly for the window
+func (i *indicator) burnRate(c *db.GetCheckCountsRow) float64 {
+	bad, total := i.count(c)
+	if total == 0 {
+		return 0
+	}
+	return float64(bad) / float64(total) / (1 - i.target/100)
+}
+
+// Status describes the error budgets of an objective
+type Status struct {
+	WindowDays   int        `json:"window_days"`
+	Availability *Indicator `json:"availability,omitempty"`
+	Latency      *Indicator `json:"latency,omitempty"`
+	Burning      bool       `json:"burning"` // a burn rate alert is firing
+}
+
+// Indicator compares a service level indicator with its target
+type Indicator struct {
+	Target    float64 `json:"target"`
+	Current   float64 `json:"current"` // percent of good checks within the window
+	Good      int64   `json:"good"`
+	Total     int64   `json:"total"`
+	Threshold int64   `json:"threshold_ms,omitempty"` // of latency objectives
+
+	// Share of the error budget left, negative once it's exceeded
+	BudgetRemaining float64 `json:"budget_remaining"`
+
+	// Burn rates over the long windows of the alerts, e.g. "1h"
+	BurnRates map[string]float64 `json:"burn_rates"`
+}
+
+// Tracker computes the error budgets of monitors and alerts their channels
+// when they burn too fast
+type Tracker struct {
+	conn        *db.Connection
+	notifier    *notify.Notifier
+	maintenance *maintenance.Calendar
+	objectives  map[string]*Objective // by monitor name
+
+	mu      sync.Mutex
+	firing  map[int64]float64 // monitor ID -> burn rate of the firing alert, stored with the monitor
+	budgets map[int64]*budget // by monitor ID
+}
+
+// budget is the status of an objective as of the latest check of its monitor.
+// Budgets only change with new checks, so it's computed once per check.
+type budget struct {
+	checkedAt time.Time
+	status    Status
+}
+
+// New creates a tracker of validated objectives
+func New(
+	conn *db.Connection,
+	notifier *notify.Notifier,
+	maintenance *maintenance.Calendar,
+	objectives map[string]*Objective,
+) *Tracker {
+	return &Tracker{
+		conn:        conn,
+		notifier:    notifier,
+		maintenance: maintenance,
+		objectives:  objectives,
+		firing:      make(map[int64]float64),
+		budgets:     make(map[int64]*budget),
+	}
+}
+
+// Status returns the error budgets of a monitor, or nil if it has no objective
+func (t *Tracker) Status(ctx context.Context, monitorID int64, name string) (*Status, error) {
+	o, ok := t.objective(name)
+	if !ok {
+		return nil, nil
+	}
+
+	var checkedAt time.Time
+	check, err := t.conn.Q.GetLatestCheck(ctx, monitorID)
+	if err == nil {
+		checkedAt = check.CheckedAt
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get latest check: %w", err)
+	}
+
+	t.mu.Lock()
+	cached, ok := t.budgets[monitorID]
+	t.mu.Unlock()
+	if !ok || !cached.checkedAt.Equal(checkedAt) {
+		status, err := t.budget(ctx, monitorID, o)
+		if err != nil {
+			return nil, err
+		}
+		cached = &budget{checkedAt: checkedAt, status: *status}
+		t.mu.Lock()
+		t.budgets[monitorID] = cached
+		t.mu.Unlock()
+	}
+
+	status := cached.status
+	t.mu.Lock()
+	status.Burning = t.firing[monitorID] > 0
+	t.mu.Unlock()
+	return &status, nil
+}
+
+// budget computes the error budgets of an objective
+func (t *Tracker) budget(ctx context.Context, monitorID int64, o *Objective) (*Status, error) {
+	now := time.Now().UTC()
+	counts, err := t.counts(ctx, monitorID, o, now.Add(-o.window()))
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{WindowDays: o.WindowDays}
+
+	indicators := o.indicators()
+	results := make([]*Indicator, len(indicators))
+	for i, ind := range indicators {
+		bad, total := ind.count(counts)
+		results[i] = &Indicator{
+			Target:          ind.target,
+			Current:         100,
+			Good:            total - bad,
+			Total:           total,
+			BudgetRemaining: 1 - ind.burnRate(counts),
+			BurnRates:       make(map[string]float64, len(o.Alerts)),
+		}
+		if total > 0 {
+			results[i].Current = float64(total-bad) * 100 / float64(total)
+		}
+		if ind.name == "latency" {
+			results[i].Threshold = o.LatencyThreshold
+			status.Latency = results[i]
+		} else {
+			status.Availability = results[i]
+		}
+	}
+
+	for _, a := range o.Alerts {
+		counts, err := t.counts(ctx, monitorID, o, now.Add(-a.Long))
+		if err != nil {
+			return nil, err
+		}
+		for i, ind := range indicators {
+			results[i].BurnRates[formatWindow(a.Long)] = ind.burnRate(counts)
+		}
+	}
+	return status, nil
+}
+
+func (t *Tracker) objective(name string) (*Objective, bool) {
+	if t == nil {
+		return nil, false
+	}
+	o, ok := t.objectives[name]
+	return o, ok
+}
+
+func (t *Tracker) counts(
+	ctx context.Context,
+	monitorID int64,
+	o *Objective,
+	since time.Time,
+) (*db.GetCheckCountsRow, error) {
+	counts, err := t.conn.Q.GetCheckCounts(ctx, &db.GetCheckCountsParams{
+		LatencyThreshold: o.LatencyThreshold,
+		MonitorID:        monitorID,
+		Since:            since,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count checks: %w", err)
+	}
+	return counts, nil
+}
+
+// formatWindow shortens durations like 1h0m0s to 1h
+func formatWindow(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// sortedAlerts returns the alerts of the objective, the fastest burn first
+func sortedAlerts(o *Objective) []BurnRateAlert {
+	alerts := slices.Clone(o.Alerts)
+	slices.SortStableFunc(alerts, func(a, b BurnRateAlert) int {
+		return cmp.Compare(b.BurnRate, a.BurnRate)
+	})
+	return alerts
+}
@@ -0,0 +1,19 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// Sign returns a URL-safe HMAC-SHA256 signature of msg
+func Sign(secret []byte, msg string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(msg))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether sig is a valid signature of msg
+func Verify(secret []byte, msg, sig string) bool {
+	return hmac.Equal([]byte(Sign(secret, msg)), []byte(sig))
+}
@@ -0,0 +1,13 @@
+package util
+
+import "math"
+
+// Percentile returns the p-th percentile of sorted values by the nearest-rank
+// method, or 0 if there are none
+func Percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(float64(len(sorted))*p/100)) - 1
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
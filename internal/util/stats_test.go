@@ -0,0 +1,31 @@
+package util
+
+import "testing"
+
+func TestPercentile(t *testing.T) {
+	ten := []int64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+
+	tests := []struct {
+		name   string
+		values []int64
+		p      float64
+		want   int64
+	}{
+		{"empty", nil, 95, 0},
+		{"single", []int64{42}, 95, 42},
+		{"minimum", ten, 0, 10},
+		{"median", ten, 50, 50},
+		{"p90", ten, 90, 90},
+		{"p95", ten, 95, 100},
+		{"maximum", ten, 100, 100},
+		{"rank rounds up", []int64{1, 2, 3}, 50, 2},
+		{"p95 of twenty", []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}, 95, 19},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Percentile(tt.values, tt.p); got != tt.want {
+				t.Errorf("Percentile() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
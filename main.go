@@ -1,13 +1,18 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/mizuchilabs/beacon/internal/api"
 	"github.com/mizuchilabs/beacon/internal/config"
@@ -41,6 +46,302 @@ func main() {
 			cfg := config.New(ctx, cmd)
 			return api.NewServer(cfg).Start(ctx)
 		},
+		Commands: []*cli.Command{
+			{
+				Name:  "notify",
+				Usage: "Manage notification channels",
+				Commands: []*cli.Command{
+					{
+						Name:  "test",
+						Usage: "Send a test notification through a channel of the config file",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "channel",
+								Usage:    "Name of the channel",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "monitor",
+								Usage: "Monitor to send the notification for (default: the first one)",
+							},
+							&cli.StringFlag{
+								Name:  "to",
+								Usage: "Single recipient, e.g. the email address of a subscriber",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							name := cmd.String("channel")
+							if err := config.SendTestNotification(ctx, cmd); err != nil {
+								return fmt.Errorf("test notification through %q failed: %w", name, err)
+							}
+							fmt.Printf("Test notification delivered through %q\n", name)
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name:  "token",
+				Usage: "Manage the tokens of the admin API",
+				Commands: []*cli.Command{
+					{
+						Name:  "create",
+						Usage: "Create a token, it is only shown once",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "name",
+								Usage:    "Name of the token, e.g. of its user",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "scope",
+								Usage: "read, write or admin",
+								Value: "read",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							token, err := config.CreateAPIToken(ctx, cmd.String("name"), cmd.String("scope"))
+							if err != nil {
+								return err
+							}
+							fmt.Println(token)
+							return nil
+						},
+					},
+					{
+						Name:  "list",
+						Usage: "List the tokens",
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							tokens, err := config.APITokens(ctx)
+							if err != nil {
+								return err
+							}
+							for _, t := range tokens {
+								lastUsed := "never"
+								if t.LastUsedAt != nil {
+									lastUsed = t.LastUsedAt.Format(time.DateTime)
+								}
+								fmt.Printf("%s\t%s\tlast used %s\n", t.Name, t.Scope, lastUsed)
+							}
+							return nil
+						},
+					},
+					{
+						Name:  "revoke",
+						Usage: "Delete a token",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "name",
+								Usage:    "Name of the token",
+								Required: true,
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							name := cmd.String("name")
+							if err := config.RevokeAPIToken(ctx, name); err != nil {
+								return err
+							}
+							fmt.Printf("Revoked token %q\n", name)
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name:  "user",
+				Usage: "Manage the users of the dashboard",
+				Commands: []*cli.Command{
+					{
+						Name:  "create",
+						Usage: "Create a user, the password is read from stdin",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "name",
+								Usage:    "Name to sign in with",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "role",
+								Usage: "viewer, editor or admin",
+								Value: "viewer",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							password, err := readPassword()
+							if err != nil {
+								return err
+							}
+							name := cmd.String("name")
+							if err := config.CreateUser(ctx, name, password, cmd.String("role")); err != nil {
+								return err
+							}
+							fmt.Printf("Created user %q\n", name)
+							return nil
+						},
+					},
+					{
+						Name:  "list",
+						Usage: "List the users",
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							users, err := config.Users(ctx)
+							if err != nil {
+								return err
+							}
+							for _, u := range users {
+								lastLogin := "never"
+								if u.LastLoginAt != nil {
+									lastLogin = u.LastLoginAt.Format(time.DateTime)
+								}
+								fmt.Printf("%s\t%s\tlast signed in %s\n", u.Username, u.Role, lastLogin)
+							}
+							return nil
+						},
+					},
+					{
+						Name:  "passwd",
+						Usage: "Change the password of a user, the password is read from stdin",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "name",
+								Usage:    "Name of the user",
+								Required: true,
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							password, err := readPassword()
+							if err != nil {
+								return err
+							}
+							name := cmd.String("name")
+							if err := config.SetUserPassword(ctx, name, password); err != nil {
+								return err
+							}
+							fmt.Printf("Changed the password of %q\n", name)
+							return nil
+						},
+					},
+					{
+						Name:  "role",
+						Usage: "Change the role of a user, which signs them out",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "name",
+								Usage:    "Name of the user",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "role",
+								Usage:    "viewer, editor or admin",
+								Required: true,
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							name, role := cmd.String("name"), cmd.String("role")
+							if err := config.SetUserRole(ctx, name, role); err != nil {
+								return err
+							}
+							fmt.Printf("Changed the role of %q to %s\n", name, role)
+							return nil
+						},
+					},
+					{
+						Name:  "delete",
+						Usage: "Delete a user",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "name",
+								Usage:    "Name of the user",
+								Required: true,
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							name := cmd.String("name")
+							if err := config.DeleteUser(ctx, name); err != nil {
+								return err
+							}
+							fmt.Printf("Deleted user %q\n", name)
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name:  "vapid",
+				Usage: "Manage the VAPID keys of push notifications",
+				Commands: []*cli.Command{
+					{
+						Name:  "rotate",
+						Usage: "Replace the VAPID keys, browsers have to subscribe again",
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							keys, err := config.RotateVAPIDKeys(ctx)
+							if err != nil {
+								return err
+							}
+							fmt.Printf("Rotated VAPID keys, new public key: %s\n", keys.PublicKey)
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name:  "export",
+				Usage: "Export the checks of a monitor from the database",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "monitor",
+						Usage:    "Name or ID of the monitor",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "csv, json or ndjson",
+						Value: "csv",
+					},
+					&cli.StringFlag{
+						Name:  "from",
+						Usage: "Start of the period as RFC 3339 time (default: the oldest check)",
+					},
+					&cli.StringFlag{
+						Name:  "to",
+						Usage: "End of the period as RFC 3339 time, exclusive (default: now)",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "File to write to (default: stdout)",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					out := os.Stdout
+					if path := cmd.String("output"); path != "" {
+						f, err := os.Create(path)
+						if err != nil {
+							return err
+						}
+						defer f.Close()
+						out = f
+					}
+					count, err := config.ExportChecks(
+						ctx,
+						out,
+						cmd.String("monitor"),
+						cmd.String("format"),
+						cmd.String("from"),
+						cmd.String("to"),
+					)
+					if err != nil {
+						return err
+					}
+					if out != os.Stdout {
+						if err := out.Close(); err != nil {
+							return err
+						}
+					}
+					fmt.Fprintf(os.Stderr, "Exported %d checks\n", count)
+					return nil
+				},
+			},
+		},
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:    "debug",
@@ -80,3 +381,14 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// readPassword reads a password from the first line of stdin, so it doesn't
+// end up in the shell history
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,12 @@
+import { queryClient } from './client';
+import { BackendURL } from './queries';
+
+// Refetches monitors as soon as one of them changes its state, rather than
+// waiting for the next poll
+export function watchEvents(): () => void {
+	const source = new EventSource(`${BackendURL}/events`, { withCredentials: true });
+	source.addEventListener('state', () => {
+		queryClient.invalidateQueries({ queryKey: ['monitors'] });
+	});
+	return () => source.close();
+}
@@ -7,6 +7,13 @@ export interface Config {
 	timezone: string;
 	chart_type: string;
 	incidents_enabled: boolean;
+	email_enabled: boolean;
+	auth: '' | 'local' | 'proxy' | 'oidc';
+}
+
+export interface User {
+	name: string;
+	scope: string;
 }
 
 export interface MonitorStats {
@@ -24,6 +31,41 @@ export interface MonitorStats {
 		p99: number | null;
 	};
 	data_points: ChartDataPoint[];
+	alert?: Alert;
+	group?: string;
+	maintenance?: string;
+	flapping?: boolean;
+	status?: 'up' | 'degraded' | 'down';
+	private?: boolean;
+	degraded_threshold: number;
+	slo?: SLO;
+}
+
+export interface SLO {
+	window_days: number;
+	availability?: SLOIndicator;
+	latency?: SLOIndicator;
+	burning: boolean;
+}
+
+export interface SLOIndicator {
+	target: number;
+	current: number;
+	good: number;
+	total: number;
+	threshold_ms?: number;
+	budget_remaining: number;
+	burn_rates: Record<string, number>;
+}
+
+export interface Alert {
+	id: number;
+	started_at: string;
+	reason: string;
+	acknowledged_at?: string;
+	acknowledged_by?: string;
+	snoozed_until?: string;
+	snoozed_by?: string;
 }
 
 export interface ChartDataPoint {
@@ -33,6 +75,7 @@ export interface ChartDataPoint {
 	up_ratio?: number;
 	degraded_ratio?: number;
 	down_ratio?: number;
+	maintenance_ratio?: number;
 }
 
 export interface Incident {
@@ -53,10 +96,23 @@ export interface IncidentUpdate {
 	created_at: string;
 }
 
+export interface Outage {
+	id: number;
+	monitor_id: number;
+	monitor_name?: string;
+	started_at: string;
+	ended_at: string | null;
+	duration_seconds: number;
+	first_error: string;
+	status_codes: number[];
+	checks: number;
+}
+
 export const BackendURL = import.meta.env.PROD ? '/api' : 'http://localhost:3000/api';
 
 async function fetchAPI<T>(endpoint: string, options?: RequestInit): Promise<T> {
 	const response = await fetch(`${BackendURL}${endpoint}`, {
+		credentials: 'include',
 		headers: {
 			'Content-Type': 'application/json',
 			...options?.headers
@@ -82,7 +138,12 @@ export const api = {
 		get: (seconds = '86400') => fetchAPI<MonitorStats[]>(`/monitors?seconds=${seconds}`),
 		getIncidents: () => fetchAPI<Incident[]>('/incidents'),
 		getIncident: (id: string) => fetchAPI<Incident>(`/incidents/${id}`),
+		getOutages: () => fetchAPI<Outage[]>('/outages'),
 		config: () => fetchAPI<Config>('/config')
+	},
+	auth: {
+		session: () => fetchAPI<{ user: User | null }>('/auth/session'),
+		logout: () => fetchAPI<void>('/auth/logout', { method: 'POST' })
 	}
 };
 
@@ -112,9 +173,24 @@ export function useIncident(id: string) {
 	}));
 }
 
+export function useOutages() {
+	return createQuery(() => ({
+		queryKey: ['outages'],
+		queryFn: () => api.monitors.getOutages(),
+		refetchInterval: 60000 // Refresh every minute
+	}));
+}
+
 export function useConfig() {
 	return createQuery(() => ({
 		queryKey: ['config'],
 		queryFn: () => api.monitors.config()
 	}));
 }
+
+export function useSession() {
+	return createQuery(() => ({
+		queryKey: ['session'],
+		queryFn: () => api.auth.session()
+	}));
+}
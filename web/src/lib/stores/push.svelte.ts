@@ -1,19 +1,23 @@
 import { BackendURL } from '$lib/api/queries';
-import { SvelteMap } from 'svelte/reactivity';
 
 interface PushSubscriptionState {
 	supported: boolean;
 	permission: NotificationPermission;
-	subscriptions: SvelteMap<number, PushSubscription>;
+	monitors: number[];
+	groups: string[];
 	loading: boolean;
 	error: string | null;
 }
 
+// Subscribing to the group "*" covers all monitors, including ones added later
+export const AllMonitors = '*';
+
 class PushNotificationStore {
 	private state = $state<PushSubscriptionState>({
 		supported: false,
 		permission: 'default',
-		subscriptions: new SvelteMap(),
+		monitors: [],
+		groups: [],
 		loading: false,
 		error: null
 	});
@@ -26,10 +30,6 @@ class PushNotificationStore {
 		return this.state.permission;
 	}
 
-	get subscriptions() {
-		return this.state.subscriptions;
-	}
-
 	get loading() {
 		return this.state.loading;
 	}
@@ -43,7 +43,19 @@ class PushNotificationStore {
 	}
 
 	get subscribedMonitorIds() {
-		return Array.from(this.state.subscriptions.keys());
+		return this.state.monitors;
+	}
+
+	get subscribedGroups() {
+		return this.state.groups;
+	}
+
+	get subscriptionCount() {
+		return this.state.monitors.length + this.state.groups.length;
+	}
+
+	get subscribedToAll() {
+		return this.state.groups.includes(AllMonitors);
 	}
 
 	checkSupport() {
@@ -72,39 +84,53 @@ class PushNotificationStore {
 		return data.publicKey;
 	}
 
-	async subscribeToMonitor(monitorID: number): Promise<boolean> {
-		this.state.loading = true;
-		this.state.error = null;
+	private async getPushSubscription(): Promise<PushSubscription> {
+		// Get service worker registration
+		const registration = await navigator.serviceWorker.ready;
+		if (!registration) {
+			throw new Error('Service worker not ready');
+		}
 
-		try {
-			// Get service worker registration
-			const registration = await navigator.serviceWorker.ready;
-			if (!registration) {
-				throw new Error('Service worker not ready');
-			}
+		// Request permission if not granted
+		const permission = await this.requestPermission();
+		if (permission !== 'granted') {
+			throw new Error('Notification permission denied');
+		}
 
-			// Request permission if not granted
-			const permission = await this.requestPermission();
-			if (permission !== 'granted') {
-				throw new Error('Notification permission denied');
-			}
+		// Get VAPID public key
+		const vapidPublicKey = await this.getVAPIDPublicKey();
+		const applicationServerKey = this.urlBase64ToUint8Array(vapidPublicKey);
+
+		// Check for existing subscription, it's bound to the key it was created with
+		let subscription = await registration.pushManager.getSubscription();
+		if (
+			subscription &&
+			!this.sameKey(subscription.options.applicationServerKey, applicationServerKey)
+		) {
+			await subscription.unsubscribe();
+			subscription = null;
+		}
 
-			// Check for existing subscription
-			let subscription = await registration.pushManager.getSubscription();
+		if (!subscription) {
+			// Subscribe to push notifications
+			subscription = await registration.pushManager.subscribe({
+				userVisibleOnly: true,
+				applicationServerKey
+			});
+		}
+		return subscription;
+	}
 
-			if (!subscription) {
-				// Get VAPID public key
-				const vapidPublicKey = await this.getVAPIDPublicKey();
+	// update replaces the monitors and groups this browser is subscribed to
+	async update(monitors: number[], groups: string[]): Promise<boolean> {
+		this.state.loading = true;
+		this.state.error = null;
 
-				// Subscribe to push notifications
-				subscription = await registration.pushManager.subscribe({
-					userVisibleOnly: true,
-					applicationServerKey: this.urlBase64ToUint8Array(vapidPublicKey)
-				});
-			}
+		try {
+			const subscription = await this.getPushSubscription();
 
 			// Send subscription to backend
-			const response = await fetch(`${BackendURL}/monitor/${monitorID}/subscribe`, {
+			const response = await fetch(`${BackendURL}/subscribe`, {
 				method: 'POST',
 				headers: { 'Content-Type': 'application/json' },
 				body: JSON.stringify({
@@ -112,7 +138,9 @@ class PushNotificationStore {
 					keys: {
 						p256dh: this.arrayBufferToBase64(subscription.getKey('p256dh')),
 						auth: this.arrayBufferToBase64(subscription.getKey('auth'))
-					}
+					},
+					monitors,
+					groups
 				})
 			});
 
@@ -120,10 +148,15 @@ class PushNotificationStore {
 				throw new Error('Failed to save subscription on server');
 			}
 
-			// Save subscription locally
-			this.state.subscriptions.set(monitorID, subscription);
+			const data = await response.json();
+			this.state.monitors = data.monitors;
+			this.state.groups = data.groups;
 			this.state.loading = false;
-			this.saveSubscriptions();
+
+			// If no more subscriptions, unsubscribe from push manager
+			if (this.subscriptionCount === 0) {
+				await subscription.unsubscribe();
+			}
 			return true;
 		} catch (error) {
 			this.state.loading = false;
@@ -135,67 +168,48 @@ class PushNotificationStore {
 		}
 	}
 
-	async unsubscribeFromMonitor(monitorID: number): Promise<boolean> {
-		this.state.loading = true;
-		this.state.error = null;
-
-		try {
-			const subscription = this.state.subscriptions.get(monitorID);
-
-			if (subscription) {
-				// Notify backend to remove association
-				await fetch(`${BackendURL}/monitor/${monitorID}/unsubscribe`, {
-					method: 'POST',
-					headers: {
-						'Content-Type': 'application/json'
-					},
-					body: JSON.stringify({
-						endpoint: subscription.endpoint
-					})
-				});
-			}
+	async subscribeToMonitor(monitorID: number): Promise<boolean> {
+		return this.update([...this.state.monitors, monitorID], this.state.groups);
+	}
 
-			// Remove from local state
-			this.state.subscriptions.delete(monitorID);
-			this.state.loading = false;
-			this.saveSubscriptions();
+	async unsubscribeFromMonitor(monitorID: number): Promise<boolean> {
+		return this.update(this.state.monitors.filter((id) => id !== monitorID), this.state.groups);
+	}
 
-			// If no more subscriptions, unsubscribe from push manager
-			if (this.state.subscriptions.size === 0) {
-				const registration = await navigator.serviceWorker.ready;
-				const pushSubscription = await registration.pushManager.getSubscription();
-				if (pushSubscription) {
-					await pushSubscription.unsubscribe();
-				}
-			}
+	async subscribeToGroup(group: string): Promise<boolean> {
+		return this.update(this.state.monitors, [...this.state.groups, group]);
+	}
 
-			return true;
-		} catch (error) {
-			console.error('Failed to unsubscribe:', error);
-			this.state.loading = false;
-			this.state.error = 'Failed to unsubscribe';
-			return false;
-		}
+	async unsubscribeFromGroup(group: string): Promise<boolean> {
+		return this.update(this.state.monitors, this.state.groups.filter((g) => g !== group));
 	}
 
-	isSubscribed(monitorID: number): boolean {
-		return this.state.subscriptions.has(monitorID);
+	async unsubscribeAll(): Promise<boolean> {
+		return this.update([], []);
 	}
 
-	private saveSubscriptions() {
-		const subscriptionData = Array.from(this.state.subscriptions.keys());
-		localStorage.setItem('monitor-subscriptions', JSON.stringify(subscriptionData));
+	isSubscribed(monitorID: number): boolean {
+		return this.state.monitors.includes(monitorID);
 	}
 
-	private loadSubscriptions() {
+	// loadSubscriptions fetches the subscriptions of this browser from the server
+	private async loadSubscriptions() {
 		try {
-			const stored = localStorage.getItem('monitor-subscriptions');
-			if (stored) {
-				const monitorIds: number[] = JSON.parse(stored);
-				// Store just the IDs - actual subscriptions are managed by the browser
-				monitorIds.forEach((id) => {
-					this.state.subscriptions.set(id, {} as PushSubscription);
-				});
+			const registration = await navigator.serviceWorker.ready;
+			const subscription = await registration.pushManager.getSubscription();
+			if (!subscription) return;
+
+			const params = new URLSearchParams({ endpoint: subscription.endpoint });
+			const response = await fetch(`${BackendURL}/subscriptions?${params}`);
+			if (!response.ok) return;
+
+			const data = await response.json();
+			this.state.monitors = data.monitors;
+			this.state.groups = data.groups;
+
+			// The VAPID keys were rotated, subscribe again with the new key
+			if (data.stale && Notification.permission === 'granted') {
+				await this.update(data.monitors, data.groups);
 			}
 		} catch (error) {
 			console.error('Failed to load subscriptions:', error);
@@ -223,6 +237,13 @@ class PushNotificationStore {
 		}
 	}
 
+	private sameKey(a: ArrayBuffer | null, b: BufferSource): boolean {
+		if (!a) return false;
+		const x = new Uint8Array(a);
+		const y = new Uint8Array(b as Uint8Array);
+		return x.length === y.length && x.every((v, i) => v === y[i]);
+	}
+
 	private arrayBufferToBase64(buffer: ArrayBuffer | null): string {
 		if (!buffer) return '';
 		const bytes = new Uint8Array(buffer);